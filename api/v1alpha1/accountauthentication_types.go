@@ -0,0 +1,155 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AccountAuthenticationSpec defines the desired state of AccountAuthentication
+type AccountAuthenticationSpec struct {
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=36
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// The account the authentication method belongs to
+	AccountID string `json:"accountId"`
+
+	// +kubebuilder:validation:Enum=saml
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// Authentication method type
+	Type string `json:"type"`
+
+	// +kubebuilder:validation:MaxLength=36
+	// Team ID the users are added to on their first login
+	AutoJoinTeamID string `json:"autoJoinTeamId,omitempty"`
+
+	// SAML configuration, required for the saml type
+	SAML *AccountAuthenticationSAML `json:"saml,omitempty"`
+
+	// Authentication reference to Aiven token in a secret
+	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
+}
+
+// AccountAuthenticationSAML SAML identity provider configuration
+type AccountAuthenticationSAML struct {
+	// +kubebuilder:validation:MaxLength=2048
+	// Identity provider entity ID
+	EntityID string `json:"entityId"`
+
+	// +kubebuilder:validation:MaxLength=2048
+	// Identity provider login URL
+	IdpURL string `json:"idpUrl"`
+
+	// Secret key with the identity provider PEM certificate
+	CertificateSecretRef SAMLCertificateSecretReference `json:"certificateSecretRef"`
+
+	// +kubebuilder:validation:Enum=sha1;sha256;sha384;sha512
+	// Digest algorithm, by default, is sha256
+	DigestAlgorithm string `json:"digestAlgorithm,omitempty"`
+
+	// +kubebuilder:validation:Enum=rsa-sha1;dsa-sha1;rsa-sha256;rsa-sha384;rsa-sha512
+	// Signature algorithm, by default, is rsa-sha256
+	SignatureAlgorithm string `json:"signatureAlgorithm,omitempty"`
+
+	// +kubebuilder:validation:Enum=adfs
+	// Identity provider variant, set adfs for Active Directory Federation Services
+	Variant string `json:"variant,omitempty"`
+
+	// Allows logging in from the identity provider
+	IdpLoginAllowed bool `json:"idpLoginAllowed,omitempty"`
+
+	// Identity provider attributes mapping
+	FieldMapping *SAMLFieldMapping `json:"fieldMapping,omitempty"`
+}
+
+// SAMLCertificateSecretReference references a Secret key containing a SAML identity provider certificate
+type SAMLCertificateSecretReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// SAMLFieldMapping maps the identity provider attributes to Aiven user fields
+type SAMLFieldMapping struct {
+	// Email attribute
+	Email string `json:"email,omitempty"`
+
+	// First name attribute
+	FirstName string `json:"firstName,omitempty"`
+
+	// User identity attribute
+	Identity string `json:"identity,omitempty"`
+
+	// Last name attribute
+	LastName string `json:"lastName,omitempty"`
+
+	// Full name attribute
+	RealName string `json:"realName,omitempty"`
+}
+
+// AccountAuthenticationStatus defines the observed state of AccountAuthentication
+type AccountAuthenticationStatus struct {
+	// Conditions represent the latest available observations of an AccountAuthentication state
+	Conditions []metav1.Condition `json:"conditions"`
+
+	// Set when the finalizer was removed by the timeout, the resource may still exist on Aiven side
+	Orphaned bool `json:"orphaned,omitempty"`
+
+	// Lifecycle phase of the resource, a summary of the conditions
+	Phase Phase `json:"phase,omitempty"`
+
+	// Authentication method ID
+	ID string `json:"id,omitempty"`
+
+	// Authentication method state
+	State string `json:"state,omitempty"`
+
+	// Is the authentication method enabled. Methods are enabled in Aiven Console
+	Enabled bool `json:"enabled,omitempty"`
+
+	// SAML Assertion Consumer Service URL to configure in the identity provider
+	SAMLAcsURL string `json:"samlAcsUrl,omitempty"`
+
+	// SAML metadata URL to configure in the identity provider
+	SAMLMetadataURL string `json:"samlMetadataUrl,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// AccountAuthentication is the Schema for the accountauthentications API.
+// Manages Aiven account authentication methods, the resource name is the method name
+// +kubebuilder:printcolumn:name="Account",type="string",JSONPath=".spec.accountId"
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Enabled",type="boolean",JSONPath=".status.enabled"
+// +kubebuilder:validation:XValidation:rule="self.spec.type != 'saml' || has(self.spec.saml)",message="saml is required for the saml type"
+type AccountAuthentication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AccountAuthenticationSpec   `json:"spec,omitempty"`
+	Status AccountAuthenticationStatus `json:"status,omitempty"`
+}
+
+func (in *AccountAuthentication) GetConditions() *[]metav1.Condition {
+	return &in.Status.Conditions
+}
+
+func (in *AccountAuthentication) AuthSecretRef() *AuthSecretReference {
+	return in.Spec.AuthSecretRef
+}
+
+// +kubebuilder:object:root=true
+
+// AccountAuthenticationList contains a list of AccountAuthentication
+type AccountAuthenticationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AccountAuthentication `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AccountAuthentication{}, &AccountAuthenticationList{})
+}
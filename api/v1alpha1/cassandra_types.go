@@ -11,6 +11,7 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // CassandraSpec defines the desired state of Cassandra
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type CassandraSpec struct {
 	ServiceCommonSpec `json:",inline"`
 
@@ -22,7 +23,10 @@ type CassandraSpec struct {
 	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
 
 	// Information regarding secret creation
-	ConnInfoSecretTarget ConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+	ConnInfoSecretTarget ServiceConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+
+	// Information regarding config map creation. Gets non-sensitive connection info, like hosts and ports
+	ConnInfoConfigMapTarget *ConnInfoConfigMapTarget `json:"connInfoConfigMapTarget,omitempty"`
 
 	// Cassandra specific user configuration options
 	UserConfig *cassandrauserconfig.CassandraUserConfig `json:"userConfig,omitempty"`
@@ -43,14 +47,42 @@ type Cassandra struct {
 	Status ServiceStatus `json:"status,omitempty"`
 }
 
+func (in *Cassandra) GetConditions() *[]metav1.Condition {
+	return &in.Status.Conditions
+}
+
 func (in *Cassandra) AuthSecretRef() *AuthSecretReference {
 	return in.Spec.AuthSecretRef
 }
 
+func (in *Cassandra) GetConnInfoSecretTarget() ConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget.ConnInfoSecretTarget
+}
+
+func (in *Cassandra) GetServiceConnInfoSecretTarget() ServiceConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget
+}
+
+func (in *Cassandra) SetProject(name string) {
+	in.Spec.Project = name
+}
+
+func (in *Cassandra) GetProject() string {
+	return in.Spec.Project
+}
+
+func (in *Cassandra) GetConnInfoConfigMapTarget() *ConnInfoConfigMapTarget {
+	return in.Spec.ConnInfoConfigMapTarget
+}
+
 func (in *Cassandra) GetRefs() []*ResourceReferenceObject {
 	return in.Spec.GetRefs(in.GetNamespace())
 }
 
+func (in *Cassandra) GetCreationDelay() int {
+	return in.Spec.CreationDelay
+}
+
 //+kubebuilder:object:root=true
 
 // CassandraList contains a list of Cassandra
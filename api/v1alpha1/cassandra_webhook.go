@@ -36,6 +36,10 @@ var _ webhook.Validator = &Cassandra{}
 func (in *Cassandra) ValidateCreate() error {
 	cassandralog.Info("validate create", "name", in.Name)
 
+	if err := validateProject(in.Spec.Project, in.Spec.ProjectRef); err != nil {
+		return err
+	}
+
 	return in.Spec.Validate()
 }
 
@@ -43,12 +47,12 @@ func (in *Cassandra) ValidateCreate() error {
 func (in *Cassandra) ValidateUpdate(old runtime.Object) error {
 	cassandralog.Info("validate update", "name", in.Name)
 
-	if in.Spec.Project != old.(*Cassandra).Spec.Project {
-		return errors.New("cannot update a Cassandra service, project field is immutable and cannot be updated")
+	if err := validateProject(in.Spec.Project, in.Spec.ProjectRef); err != nil {
+		return err
 	}
 
-	if in.Spec.ConnInfoSecretTarget.Name != old.(*Cassandra).Spec.ConnInfoSecretTarget.Name {
-		return errors.New("cannot update a Cassandra service, connInfoSecretTarget.name field is immutable and cannot be updated")
+	if isProjectChanged(old.(*Cassandra).Spec.Project, in.Spec.Project, in.Spec.ProjectRef) {
+		return errors.New("cannot update a Cassandra service, project field is immutable and cannot be updated")
 	}
 
 	return in.Spec.Validate()
@@ -9,6 +9,7 @@ import (
 )
 
 // ClickhouseSpec defines the desired state of Clickhouse
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type ClickhouseSpec struct {
 	ServiceCommonSpec `json:",inline"`
 
@@ -20,7 +21,10 @@ type ClickhouseSpec struct {
 	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
 
 	// Information regarding secret creation
-	ConnInfoSecretTarget ConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+	ConnInfoSecretTarget ServiceConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+
+	// Information regarding config map creation. Gets non-sensitive connection info, like hosts and ports
+	ConnInfoConfigMapTarget *ConnInfoConfigMapTarget `json:"connInfoConfigMapTarget,omitempty"`
 
 	// OpenSearch specific user configuration options
 	UserConfig *clickhouseuserconfig.ClickhouseUserConfig `json:"userConfig,omitempty"`
@@ -38,6 +42,10 @@ type Clickhouse struct {
 	Status ServiceStatus  `json:"status,omitempty"`
 }
 
+func (in *Clickhouse) GetConditions() *[]metav1.Condition {
+	return &in.Status.Conditions
+}
+
 //+kubebuilder:object:root=true
 
 // ClickhouseList contains a list of Clickhouse
@@ -51,10 +59,34 @@ func (in *Clickhouse) AuthSecretRef() *AuthSecretReference {
 	return in.Spec.AuthSecretRef
 }
 
+func (in *Clickhouse) GetConnInfoSecretTarget() ConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget.ConnInfoSecretTarget
+}
+
+func (in *Clickhouse) GetServiceConnInfoSecretTarget() ServiceConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget
+}
+
+func (in *Clickhouse) SetProject(name string) {
+	in.Spec.Project = name
+}
+
+func (in *Clickhouse) GetProject() string {
+	return in.Spec.Project
+}
+
+func (in *Clickhouse) GetConnInfoConfigMapTarget() *ConnInfoConfigMapTarget {
+	return in.Spec.ConnInfoConfigMapTarget
+}
+
 func (in *Clickhouse) GetRefs() []*ResourceReferenceObject {
 	return in.Spec.GetRefs(in.GetNamespace())
 }
 
+func (in *Clickhouse) GetCreationDelay() int {
+	return in.Spec.CreationDelay
+}
+
 func init() {
 	SchemeBuilder.Register(&Clickhouse{}, &ClickhouseList{})
 }
@@ -37,6 +37,10 @@ var _ webhook.Validator = &Clickhouse{}
 func (r *Clickhouse) ValidateCreate() error {
 	clickhouselog.Info("validate create", "name", r.Name)
 
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
 	return r.Spec.Validate()
 }
 
@@ -44,12 +48,12 @@ func (r *Clickhouse) ValidateCreate() error {
 func (r *Clickhouse) ValidateUpdate(old runtime.Object) error {
 	clickhouselog.Info("validate update", "name", r.Name)
 
-	if r.Spec.Project != old.(*Clickhouse).Spec.Project {
-		return errors.New("cannot update a Clickhouse service, project field is immutable and cannot be updated")
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
 	}
 
-	if r.Spec.ConnInfoSecretTarget.Name != old.(*Clickhouse).Spec.ConnInfoSecretTarget.Name {
-		return errors.New("cannot update a Clickhouse service, connInfoSecretTarget.name field is immutable and cannot be updated")
+	if isProjectChanged(old.(*Clickhouse).Spec.Project, r.Spec.Project, r.Spec.ProjectRef) {
+		return errors.New("cannot update a Clickhouse service, project field is immutable and cannot be updated")
 	}
 
 	return r.Spec.Validate()
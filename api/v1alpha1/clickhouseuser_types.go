@@ -7,11 +7,16 @@ import (
 )
 
 // ClickhouseUserSpec defines the desired state of ClickhouseUser
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type ClickhouseUserSpec struct {
 	// +kubebuilder:validation:MaxLength=63
 	// +kubebuilder:validation:Format="^[a-zA-Z0-9_-]*$"
 	// Project to link the user to
-	Project string `json:"project"`
+	Project string `json:"project,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// ProjectRef reference to Project resource to use its name as Project automatically
+	ProjectRef *ResourceReference `json:"projectRef,omitempty"`
 
 	// +kubebuilder:validation:MaxLength=63
 	// Service to link the user to
@@ -36,6 +41,12 @@ type ClickhouseUserStatus struct {
 	// Conditions represent the latest available observations of an ClickhouseUser state
 	// +kubebuilder:validation:type=array
 	Conditions []metav1.Condition `json:"conditions"`
+
+	// Set when the finalizer was removed by the timeout, the resource may still exist on Aiven side
+	Orphaned bool `json:"orphaned,omitempty"`
+
+	// Lifecycle phase of the resource, a summary of the conditions
+	Phase Phase `json:"phase,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -53,10 +64,33 @@ type ClickhouseUser struct {
 	Status ClickhouseUserStatus `json:"status,omitempty"`
 }
 
+func (u *ClickhouseUser) GetConditions() *[]metav1.Condition {
+	return &u.Status.Conditions
+}
+
 func (u ClickhouseUser) AuthSecretRef() *AuthSecretReference {
 	return u.Spec.AuthSecretRef
 }
 
+func (u *ClickhouseUser) GetConnInfoSecretTarget() ConnInfoSecretTarget {
+	return u.Spec.ConnInfoSecretTarget
+}
+
+func (u *ClickhouseUser) SetProject(name string) {
+	u.Spec.Project = name
+}
+
+func (u *ClickhouseUser) GetProject() string {
+	return u.Spec.Project
+}
+
+func (u *ClickhouseUser) GetRefs() []*ResourceReferenceObject {
+	if u.Spec.ProjectRef == nil {
+		return nil
+	}
+	return []*ResourceReferenceObject{u.Spec.ProjectRef.Project(u.GetNamespace())}
+}
+
 //+kubebuilder:object:root=true
 
 // ClickhouseUserList contains a list of ClickhouseUser
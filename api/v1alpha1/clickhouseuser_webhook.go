@@ -34,14 +34,15 @@ var _ webhook.Validator = &ClickhouseUser{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *ClickhouseUser) ValidateCreate() error {
 	clickhouseuserlog.Info("validate create", "name", r.Name)
-	return nil
+
+	return validateProject(r.Spec.Project, r.Spec.ProjectRef)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *ClickhouseUser) ValidateUpdate(old runtime.Object) error {
 	clickhouseuserlog.Info("validate update", "name", r.Name)
 
-	return nil
+	return validateProject(r.Spec.Project, r.Spec.ProjectRef)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
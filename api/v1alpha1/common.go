@@ -7,6 +7,7 @@ import (
 
 	"github.com/docker/go-units"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -14,18 +15,132 @@ import (
 
 var ErrDeleteDependencies = errors.New("object has dependencies and cannot be deleted")
 
-// AuthSecretReference references a Secret containing an Aiven authentication token
+// AuthSecretReference references a Secret containing an Aiven authentication token.
+// Alternatively, the token is read from a file, for instance, injected by Vault agent or a CSI driver,
+// or from the operator environment variable
+// +kubebuilder:validation:XValidation:rule="has(self.name) ? has(self.key) && !has(self.file) && !has(self.env) : has(self.file) != has(self.env) && !has(self.namespace)",message="set either name and key, file or env"
 type AuthSecretReference struct {
 	// +kubebuilder:validation:MinLength=1
-	Name string `json:"name"`
+	// Secret name
+	Name string `json:"name,omitempty"`
 	// +kubebuilder:validation:MinLength=1
-	Key string `json:"key"`
+	// Secret key with the token
+	Key string `json:"key,omitempty"`
+
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	// Secret namespace, by default, the resource namespace.
+	// A secret in another namespace must list the resource namespace in its controllers.aiven.io/allowed-namespaces annotation
+	Namespace string `json:"namespace,omitempty"`
+
+	// +kubebuilder:validation:MinLength=1
+	// Token file path, must be in the operator --auth-token-dir directory
+	File string `json:"file,omitempty"`
+
+	// +kubebuilder:validation:Pattern="^AIVEN_TOKEN_[A-Z0-9_]+$"
+	// Operator environment variable with the token, must start with AIVEN_TOKEN_
+	Env string `json:"env,omitempty"`
 }
 
 // ConnInfoSecretTarget contains information secret name
 type ConnInfoSecretTarget struct {
 	// Name of the secret resource to be created. By default, is equal to the resource name
 	Name string `json:"name"`
+
+	// +kubebuilder:validation:Enum=replace;merge
+	// Secret update strategy. "replace" (default) sets the whole secret data,
+	// "merge" updates keys managed by the operator and keeps the others, for instance, added by other tools
+	UpdateStrategy string `json:"updateStrategy,omitempty"`
+
+	// Per key value encoding, for instance, to get CA_CERT base64 encoded instead of raw PEM
+	KeyEncodings []SecretKeyEncoding `json:"keyEncodings,omitempty"`
+
+	// Renames the secret keys to the names the consuming application expects, for instance, PASSWORD: DB_PASSWORD.
+	// Maps the default key names, which are used in keyEncodings too. Unknown keys are reported with a warning event
+	KeyNames map[string]string `json:"keyNames,omitempty"`
+
+	// Keeps the replaced credentials (PASSWORD, ACCESS_KEY and ACCESS_CERT keys) under the _PREVIOUS suffixed keys
+	// for the period, for instance, PASSWORD_PREVIOUS, so in-flight connections with the old credentials don't break
+	PreviousCredentialsGracePeriod *metav1.Duration `json:"previousCredentialsGracePeriod,omitempty"`
+}
+
+// ServiceConnInfoSecretTarget contains service connection info secret settings
+type ServiceConnInfoSecretTarget struct {
+	ConnInfoSecretTarget `json:",inline"`
+
+	// Adds the Prometheus scrape config of the service prometheus integration to the secret:
+	// PROMETHEUS_URL, PROMETHEUS_USERNAME and PROMETHEUS_PASSWORD
+	Prometheus *bool `json:"prometheus,omitempty"`
+
+	// Requires prometheus. Creates a Prometheus Operator ScrapeConfig with the secret name,
+	// which scrapes the service metrics endpoint. Ignored if the ScrapeConfig CRD is not installed
+	PrometheusScrapeConfig *bool `json:"prometheusScrapeConfig,omitempty"`
+}
+
+// KafkaConnInfoSecretTarget contains Kafka connection info secret settings
+type KafkaConnInfoSecretTarget struct {
+	ServiceConnInfoSecretTarget `json:",inline"`
+
+	// Adds the Kafka Connect REST API connection info to the secret, when kafka_connect is enabled:
+	// KAFKA_CONNECT_URI, KAFKA_CONNECT_USERNAME and KAFKA_CONNECT_PASSWORD
+	KafkaConnect *bool `json:"kafkaConnect,omitempty"`
+}
+
+// SecretKeyEncoding value encoding of a connection info secret key
+type SecretKeyEncoding struct {
+	// +kubebuilder:validation:MinLength=1
+	// Secret key, for instance, CA_CERT
+	Key string `json:"key"`
+
+	// +kubebuilder:validation:Enum=raw;base64
+	// "raw" (default) stores the value as is. "base64" stores the base64 encoded value,
+	// which is read base64 encoded from the mounted secret
+	Encoding string `json:"encoding"`
+}
+
+// ConnInfoConfigMapTarget contains information config map name
+type ConnInfoConfigMapTarget struct {
+	// +kubebuilder:validation:MinLength=1
+	// Name of the config map resource to be created
+	Name string `json:"name"`
+}
+
+// Phase machine-readable lifecycle state of the resource
+// +kubebuilder:validation:Enum=Pending;Creating;Running;Updating;Deleting;Failed
+type Phase string
+
+const (
+	// PhasePending the resource waits for its dependencies to be created
+	PhasePending Phase = "Pending"
+	// PhaseCreating the resource is being created on Aiven side
+	PhaseCreating Phase = "Creating"
+	// PhaseRunning the resource is running on Aiven side
+	PhaseRunning Phase = "Running"
+	// PhaseUpdating the changes are being applied on Aiven side
+	PhaseUpdating Phase = "Updating"
+	// PhaseDeleting the resource is being deleted on Aiven side
+	PhaseDeleting Phase = "Deleting"
+	// PhaseFailed the last change failed to apply
+	PhaseFailed Phase = "Failed"
+)
+
+// ServiceDryRunPlan changes which would be applied to the service, nothing is applied in the dry-run mode
+type ServiceDryRunPlan struct {
+	// Generation the plan is computed for
+	Generation int64 `json:"generation"`
+
+	// False if the service would be created
+	Exists bool `json:"exists"`
+
+	// Fields which would be changed
+	Changes []DryRunChange `json:"changes,omitempty"`
+}
+
+// DryRunChange a field which would be changed, the values are JSON encoded
+type DryRunChange struct {
+	Field   string `json:"field"`
+	Live    string `json:"live,omitempty"`
+	Desired string `json:"desired"`
 }
 
 // ServiceStatus defines the observed state of service
@@ -33,8 +148,81 @@ type ServiceStatus struct {
 	// Conditions represent the latest available observations of a service state
 	Conditions []metav1.Condition `json:"conditions"`
 
+	// Set when the finalizer was removed by the timeout, the resource may still exist on Aiven side
+	Orphaned bool `json:"orphaned,omitempty"`
+
+	// Lifecycle phase of the resource, a summary of the conditions
+	Phase Phase `json:"phase,omitempty"`
+
 	// Service state
 	State string `json:"state"`
+
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// Sanitized copy of the live service object, secrets are excluded. Set only when spec.serviceSnapshot is enabled
+	ServiceSnapshot *runtime.RawExtension `json:"serviceSnapshot,omitempty"`
+
+	// Changes which would be applied to the service, set when the resource is in the dry-run mode
+	DryRunPlan *ServiceDryRunPlan `json:"dryRunPlan,omitempty"`
+
+	// Plan-derived limits of the service
+	PlanDetails *ServicePlanDetails `json:"planDetails,omitempty"`
+
+	// PostgreSQL only. Connection pools of all the service databases, including the ones not managed with ConnectionPool
+	ConnectionPools []ServiceConnectionPool `json:"connectionPools,omitempty"`
+
+	// Pending maintenance updates, set when there are any or spec.maintenance is set
+	Maintenance *ServiceMaintenanceStatus `json:"maintenance,omitempty"`
+}
+
+// ServiceMaintenanceStatus pending maintenance of the service
+type ServiceMaintenanceStatus struct {
+	// Number of maintenance updates waiting to be applied
+	PendingUpdates int `json:"pendingUpdates"`
+
+	// Start of the next allowed window the pending updates are applied in, set when spec.maintenance.autoApply is enabled
+	NextWindow *metav1.Time `json:"nextWindow,omitempty"`
+
+	// When the operator started the maintenance the last time
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+}
+
+// ServiceConnectionPool PgBouncer connection pool of the service
+type ServiceConnectionPool struct {
+	// Pool name
+	Name string `json:"name"`
+
+	// Database the pool connects to
+	Database string `json:"database"`
+
+	// Username the pool connects with, empty if the pool uses the client credentials
+	Username string `json:"username,omitempty"`
+
+	// Pool mode: session, transaction or statement
+	Mode string `json:"mode"`
+
+	// Number of connections the pool may create towards the database
+	Size int `json:"size"`
+}
+
+// ServicePlanDetails plan-derived limits of the service, reflect plan changes
+type ServicePlanDetails struct {
+	// Subscription plan on Aiven side, may differ from the spec plan when Aiven remaps plan names
+	Plan string `json:"plan,omitempty"`
+
+	// Number of service nodes
+	NodeCount int `json:"nodeCount,omitempty"`
+
+	// Number of CPUs per node
+	NodeCPUCount int `json:"nodeCPUCount,omitempty"`
+
+	// Memory per node in MB
+	NodeMemoryMB int `json:"nodeMemoryMB,omitempty"`
+
+	// Disk space in MB
+	DiskSpaceMB int `json:"diskSpaceMB,omitempty"`
+
+	// Maximum number of connections, PostgreSQL only
+	MaxConnections int `json:"maxConnections,omitempty"`
 }
 
 type ServiceCommonSpec struct {
@@ -42,14 +230,19 @@ type ServiceCommonSpec struct {
 	// +kubebuilder:validation:Format="^[a-zA-Z0-9_-]*$"
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	// Target project.
-	Project string `json:"project"`
+	Project string `json:"project,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// ProjectRef reference to Project resource to use its name as Project automatically
+	ProjectRef *ResourceReference `json:"projectRef,omitempty"`
 
 	// +kubebuilder:validation:MaxLength=128
 	// Subscription plan.
 	Plan string `json:"plan"`
 
 	// +kubebuilder:validation:MaxLength=256
-	// Cloud the service runs in.
+	// Cloud the service runs in. By default, is the project default cloud:
+	// the cloud of the referenced Project resource or the Aiven project default cloud
 	CloudName string `json:"cloudName,omitempty"`
 
 	// +kubebuilder:validation:MaxLength=36
@@ -69,16 +262,99 @@ type ServiceCommonSpec struct {
 	// Time of day when maintenance operations should be performed. UTC time in HH:mm:ss format.
 	MaintenanceWindowTime string `json:"maintenanceWindowTime,omitempty"`
 
+	// Maintenance update policy, for instance, to apply pending updates automatically during the allowed windows
+	Maintenance *ServiceMaintenance `json:"maintenance,omitempty"`
+
 	// Prevent service from being deleted. It is recommended to have this enabled for all services.
 	TerminationProtection *bool `json:"terminationProtection,omitempty"`
 
+	// Disables termination protection on Aiven side before the service is deleted.
+	// Otherwise, the deletion of a protected service is blocked until the protection is disabled
+	ClearTerminationProtectionOnDelete *bool `json:"clearTerminationProtectionOnDelete,omitempty"`
+
 	// Tags are key-value pairs that allow you to categorize services.
+	// Tags removed from the spec are removed from the service, tags set outside the operator are kept
 	Tags map[string]string `json:"tags,omitempty"`
 
+	// Sends only the user config options that differ from the live service configuration on update.
+	// Options that are set outside the operator (for instance, in the Aiven Console) are not reset
+	PartialUserConfigUpdate *bool `json:"partialUserConfigUpdate,omitempty"`
+
+	// Explicitly unsets user config options removed from the spec since the last update.
+	// Otherwise, Aiven keeps the values of removed options
+	UnsetRemovedUserConfig *bool `json:"unsetRemovedUserConfig,omitempty"`
+
+	// Powers the service off when set to false, for instance, during migrations.
+	// A powered off service keeps its backups and is not billed. Not applied on service creation
+	Powered *bool `json:"powered,omitempty"`
+
+	// Stores a sanitized copy of the live service object in status.serviceSnapshot to help diagnose config drift.
+	// Disabled by default, because the object can be large
+	ServiceSnapshot *bool `json:"serviceSnapshot,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// Seconds to defer the first reconcile of a new resource, so the resources it depends on are created first on mass apply
+	CreationDelay int `json:"creationDelay,omitempty"`
+
 	// +kubebuilder:validation:MaxItems=1
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	// Service integrations to specify when creating a service. Not applied after initial service creation
 	ServiceIntegrations []*ServiceIntegrationItem `json:"serviceIntegrations,omitempty"`
+
+	// ConfigMap key with CIDR blocks separated by newlines or commas, for instance, maintained by another controller.
+	// The blocks are added to the user config ipFilter, the ConfigMap changes are applied automatically
+	IPFilterConfigMapRef *IPFilterConfigMapReference `json:"ipFilterConfigMapRef,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// Creates the service as a fork of another service with its configuration and data,
+	// for instance, a staging copy of production. Not applied after initial service creation
+	ForkFrom *ServiceForkSource `json:"forkFrom,omitempty"`
+}
+
+// ServiceMaintenance maintenance update policy of the service
+type ServiceMaintenance struct {
+	// Applies pending maintenance updates as soon as an allowed window starts,
+	// instead of waiting for the maintenance window or the update deadline
+	AutoApply *bool `json:"autoApply,omitempty"`
+
+	// +kubebuilder:validation:MaxItems=21
+	// Weekly windows the pending updates are applied in. The updates are applied at any time if empty
+	AllowedWindows []MaintenanceAllowedWindow `json:"allowedWindows,omitempty"`
+}
+
+// MaintenanceAllowedWindow weekly UTC time window
+type MaintenanceAllowedWindow struct {
+	// +kubebuilder:validation:Enum=monday;tuesday;wednesday;thursday;friday;saturday;sunday
+	// Day of week the window starts on
+	DayOfWeek string `json:"dayOfWeek"`
+
+	// +kubebuilder:validation:Pattern="^([01][0-9]|2[0-3]):[0-5][0-9]$"
+	// Window start, UTC time in HH:mm format
+	StartTime string `json:"startTime"`
+
+	// Window length, for instance, 2h. The maintenance may last longer, it is only started within the window
+	Duration metav1.Duration `json:"duration"`
+}
+
+// IPFilterConfigMapReference references a ConfigMap key in the same namespace
+type IPFilterConfigMapReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// ServiceForkSource the service to fork from
+type ServiceForkSource struct {
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=64
+	// Source service name, must be of the same type and running
+	ServiceName string `json:"serviceName"`
+
+	// +kubebuilder:validation:MaxLength=63
+	// Source service project. By default, is equal to the service project
+	Project string `json:"project,omitempty"`
 }
 
 // Validate runs complex validation on ServiceCommonSpec
@@ -90,8 +366,16 @@ func (in *ServiceCommonSpec) Validate() error {
 	return nil
 }
 
+// IsPowered returns false only if the service is explicitly powered off
+func (in *ServiceCommonSpec) IsPowered() bool {
+	return in.Powered == nil || *in.Powered
+}
+
 // GetRefs is inherited by kafka, pg, os, etc
 func (in *ServiceCommonSpec) GetRefs(namespace string) (refs []*ResourceReferenceObject) {
+	if in.ProjectRef != nil {
+		refs = append(refs, in.ProjectRef.Project(namespace))
+	}
 	if in.ProjectVPCRef != nil {
 		refs = append(refs, in.ProjectVPCRef.ProjectVPC(namespace))
 	}
@@ -133,11 +417,21 @@ func (in *ResourceReference) ref(kind string, objNamespace string) *ResourceRefe
 	}
 }
 
+// Project returns reference Project kind
+func (in *ResourceReference) Project(objNamespace string) *ResourceReferenceObject {
+	return in.ref("Project", objNamespace)
+}
+
 // ProjectVPC returns reference ProjectVPC kind
 func (in *ResourceReference) ProjectVPC(objNamespace string) *ResourceReferenceObject {
 	return in.ref("ProjectVPC", objNamespace)
 }
 
+// PostgreSQL returns reference PostgreSQL kind
+func (in *ResourceReference) PostgreSQL(objNamespace string) *ResourceReferenceObject {
+	return in.ref("PostgreSQL", objNamespace)
+}
+
 // ResourceReferenceObject is a composite "key" to resource
 // GroupVersionKind is for resource "type": GroupVersionKind{Group: "aiven.io", Version: "v1alpha1", Kind: "Kafka"}
 // NamespacedName is for specific instance: NamespacedName{Name: "my-kafka", Namespace: "default"}
@@ -154,6 +448,45 @@ func ConvertDiscSpace(v string) int {
 	return int(diskSizeMB / units.MiB)
 }
 
+// name returns the reference name without the namespace
+func (in *ResourceReference) name() string {
+	if i := strings.LastIndex(in.Name, string(types.Separator)); i >= 0 {
+		return in.Name[i+1:]
+	}
+	return in.Name
+}
+
+// validateProject checks either project or projectRef is set.
+// The operator sets project to the Project kind name, so both must match if both are set
+func validateProject(project string, ref *ResourceReference) error {
+	switch {
+	case project == "" && ref == nil:
+		return errors.New("either project or projectRef must be set")
+	case project != "" && ref != nil && project != ref.name():
+		return fmt.Errorf("project %q doesn't match projectRef name %q", project, ref.name())
+	}
+	return nil
+}
+
+// isProjectChanged returns true if the project is changed.
+// The empty project can be set once only, to the projectRef name by the operator
+func isProjectChanged(old, project string, ref *ResourceReference) bool {
+	if old == "" && ref != nil && project == ref.name() {
+		return false
+	}
+	return old != project
+}
+
+// FindProject returns Project from reference list
+func FindProject(refs []client.Object) *Project {
+	for _, o := range refs {
+		if p, ok := o.(*Project); ok {
+			return p
+		}
+	}
+	return nil
+}
+
 // FindProjectVPC returns ProjectVPC from reference list
 func FindProjectVPC(refs []client.Object) *ProjectVPC {
 	for _, o := range refs {
@@ -0,0 +1,77 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package v1alpha1
+
+import (
+	"testing"
+)
+
+func Test_validateProject(t *testing.T) {
+	tests := []struct {
+		name    string
+		project string
+		ref     *ResourceReference
+		wantErr bool
+	}{
+		{name: "project", project: "my-project"},
+		{name: "projectRef", ref: &ResourceReference{Name: "my-project"}},
+		{name: "projectRef with namespace", ref: &ResourceReference{Name: "team-a/my-project"}},
+		{name: "both match", project: "my-project", ref: &ResourceReference{Name: "my-project"}},
+		{name: "both match with namespace", project: "my-project", ref: &ResourceReference{Name: "team-a/my-project"}},
+		{name: "both mismatch", project: "other", ref: &ResourceReference{Name: "my-project"}, wantErr: true},
+		{name: "neither", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateProject(tt.project, tt.ref); (err != nil) != tt.wantErr {
+				t.Errorf("validateProject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_isProjectChanged(t *testing.T) {
+	ref := &ResourceReference{Name: "my-project"}
+	tests := []struct {
+		name    string
+		old     string
+		project string
+		ref     *ResourceReference
+		want    bool
+	}{
+		{name: "unchanged", old: "my-project", project: "my-project"},
+		{name: "changed", old: "my-project", project: "other", want: true},
+		{name: "removed", old: "my-project", project: "", ref: ref, want: true},
+		{name: "set from projectRef", old: "", project: "my-project", ref: ref},
+		{name: "set to other than projectRef", old: "", project: "other", ref: ref, want: true},
+		{name: "set without projectRef", old: "", project: "my-project", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isProjectChanged(tt.old, tt.project, tt.ref); got != tt.want {
+				t.Errorf("isProjectChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_projectWebhook(t *testing.T) {
+	kafka := &Kafka{}
+	if err := kafka.ValidateCreate(); err == nil {
+		t.Error("ValidateCreate() without project and projectRef must fail")
+	}
+
+	old := &Kafka{}
+	old.Spec.ProjectRef = &ResourceReference{Name: "my-project"}
+	resolved := old.DeepCopy()
+	resolved.Spec.Project = "my-project"
+	if err := resolved.ValidateUpdate(old); err != nil {
+		t.Errorf("ValidateUpdate() setting project from projectRef error = %v", err)
+	}
+
+	changed := resolved.DeepCopy()
+	changed.Spec.Project = "other"
+	if err := changed.ValidateUpdate(resolved); err == nil {
+		t.Error("ValidateUpdate() changing project must fail")
+	}
+}
@@ -7,11 +7,16 @@ import (
 )
 
 // ConnectionPoolSpec defines the desired state of ConnectionPool
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type ConnectionPoolSpec struct {
 	// +kubebuilder:validation:MaxLength=63
 	// +kubebuilder:validation:Format="^[a-zA-Z0-9_-]*$"
 	// Target project.
-	Project string `json:"project"`
+	Project string `json:"project,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// ProjectRef reference to Project resource to use its name as Project automatically
+	ProjectRef *ResourceReference `json:"projectRef,omitempty"`
 
 	// +kubebuilder:validation:MaxLength=63
 	// Service name.
@@ -45,6 +50,12 @@ type ConnectionPoolSpec struct {
 type ConnectionPoolStatus struct {
 	// Conditions represent the latest available observations of an ConnectionPool state
 	Conditions []metav1.Condition `json:"conditions"`
+
+	// Set when the finalizer was removed by the timeout, the resource may still exist on Aiven side
+	Orphaned bool `json:"orphaned,omitempty"`
+
+	// Lifecycle phase of the resource, a summary of the conditions
+	Phase Phase `json:"phase,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -65,10 +76,33 @@ type ConnectionPool struct {
 	Status ConnectionPoolStatus `json:"status,omitempty"`
 }
 
+func (cp *ConnectionPool) GetConditions() *[]metav1.Condition {
+	return &cp.Status.Conditions
+}
+
 func (cp ConnectionPool) AuthSecretRef() *AuthSecretReference {
 	return cp.Spec.AuthSecretRef
 }
 
+func (cp *ConnectionPool) GetConnInfoSecretTarget() ConnInfoSecretTarget {
+	return cp.Spec.ConnInfoSecretTarget
+}
+
+func (cp *ConnectionPool) SetProject(name string) {
+	cp.Spec.Project = name
+}
+
+func (cp *ConnectionPool) GetProject() string {
+	return cp.Spec.Project
+}
+
+func (cp *ConnectionPool) GetRefs() []*ResourceReferenceObject {
+	if cp.Spec.ProjectRef == nil {
+		return nil
+	}
+	return []*ResourceReferenceObject{cp.Spec.ProjectRef.Project(cp.GetNamespace())}
+}
+
 // +kubebuilder:object:root=true
 
 // ConnectionPoolList contains a list of ConnectionPool
@@ -41,14 +41,18 @@ var _ webhook.Validator = &ConnectionPool{}
 func (r *ConnectionPool) ValidateCreate() error {
 	connectionpoollog.Info("validate create", "name", r.Name)
 
-	return nil
+	return validateProject(r.Spec.Project, r.Spec.ProjectRef)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *ConnectionPool) ValidateUpdate(old runtime.Object) error {
 	connectionpoollog.Info("validate update", "name", r.Name)
 
-	if r.Spec.Project != old.(*ConnectionPool).Spec.Project {
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
+	if isProjectChanged(old.(*ConnectionPool).Spec.Project, r.Spec.Project, r.Spec.ProjectRef) {
 		return errors.New("cannot update a ConnectionPool, project field is immutable and cannot be updated")
 	}
 
@@ -56,10 +60,6 @@ func (r *ConnectionPool) ValidateUpdate(old runtime.Object) error {
 		return errors.New("cannot update a ConnectionPool, serviceName field is immutable and cannot be updated")
 	}
 
-	if r.Spec.ConnInfoSecretTarget.Name != old.(*ConnectionPool).Spec.ConnInfoSecretTarget.Name {
-		return errors.New("cannot update a ConnectionPool, connInfoSecretTarget.name field is immutable and cannot be updated")
-	}
-
 	return nil
 }
 
@@ -7,11 +7,16 @@ import (
 )
 
 // DatabaseSpec defines the desired state of Database
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type DatabaseSpec struct {
 	// +kubebuilder:validation:MaxLength=63
 	// +kubebuilder:validation:Format="^[a-zA-Z0-9_-]*$"
 	// Project to link the database to
-	Project string `json:"project"`
+	Project string `json:"project,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// ProjectRef reference to Project resource to use its name as Project automatically
+	ProjectRef *ResourceReference `json:"projectRef,omitempty"`
 
 	// +kubebuilder:validation:MaxLength=63
 	// PostgreSQL service to link the database to
@@ -27,7 +32,8 @@ type DatabaseSpec struct {
 
 	// It is a Kubernetes side deletion protections, which prevents the database
 	// from being deleted by Kubernetes. It is recommended to enable this for any production
-	// databases containing critical data.
+	// databases containing critical data. Databases with data are not detected, so there is no deleteProtectionIfNotEmpty
+	// unlike KafkaTopic: Aiven API doesn't report database sizes
 	TerminationProtection *bool `json:"terminationProtection,omitempty"`
 
 	// Authentication reference to Aiven token in a secret
@@ -38,6 +44,12 @@ type DatabaseSpec struct {
 type DatabaseStatus struct {
 	// Conditions represent the latest available observations of an Database state
 	Conditions []metav1.Condition `json:"conditions"`
+
+	// Set when the finalizer was removed by the timeout, the resource may still exist on Aiven side
+	Orphaned bool `json:"orphaned,omitempty"`
+
+	// Lifecycle phase of the resource, a summary of the conditions
+	Phase Phase `json:"phase,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -54,10 +66,29 @@ type Database struct {
 	Status DatabaseStatus `json:"status,omitempty"`
 }
 
+func (db *Database) GetConditions() *[]metav1.Condition {
+	return &db.Status.Conditions
+}
+
 func (db Database) AuthSecretRef() *AuthSecretReference {
 	return db.Spec.AuthSecretRef
 }
 
+func (db *Database) SetProject(name string) {
+	db.Spec.Project = name
+}
+
+func (db *Database) GetProject() string {
+	return db.Spec.Project
+}
+
+func (db *Database) GetRefs() []*ResourceReferenceObject {
+	if db.Spec.ProjectRef == nil {
+		return nil
+	}
+	return []*ResourceReferenceObject{db.Spec.ProjectRef.Project(db.GetNamespace())}
+}
+
 // +kubebuilder:object:root=true
 
 // DatabaseList contains a list of Database
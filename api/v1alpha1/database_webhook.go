@@ -47,14 +47,18 @@ var _ webhook.Validator = &Database{}
 func (r *Database) ValidateCreate() error {
 	databaselog.Info("validate create", "name", r.Name)
 
-	return nil
+	return validateProject(r.Spec.Project, r.Spec.ProjectRef)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *Database) ValidateUpdate(old runtime.Object) error {
 	databaselog.Info("validate update", "name", r.Name)
 
-	if r.Spec.Project != old.(*Database).Spec.Project {
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
+	if isProjectChanged(old.(*Database).Spec.Project, r.Spec.Project, r.Spec.ProjectRef) {
 		return errors.New("cannot update a Database, project field is immutable and cannot be updated")
 	}
 
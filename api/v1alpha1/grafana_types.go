@@ -11,6 +11,7 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // GrafanaSpec defines the desired state of Grafana
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type GrafanaSpec struct {
 	ServiceCommonSpec `json:",inline"`
 
@@ -22,10 +23,17 @@ type GrafanaSpec struct {
 	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
 
 	// Information regarding secret creation
-	ConnInfoSecretTarget ConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+	ConnInfoSecretTarget ServiceConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+
+	// Information regarding config map creation. Gets non-sensitive connection info, like hosts and ports
+	ConnInfoConfigMapTarget *ConnInfoConfigMapTarget `json:"connInfoConfigMapTarget,omitempty"`
 
 	// Cassandra specific user configuration options
 	UserConfig *grafanauserconfig.GrafanaUserConfig `json:"userConfig,omitempty"`
+
+	// Adds the destination services of the metrics ServiceIntegrations in the namespace as Grafana datasources
+	// (dashboard integrations). A datasource is added when both Grafana and the destination service are running
+	MetricsDatasources *bool `json:"metricsDatasources,omitempty"`
 }
 
 // Grafana is the Schema for the grafanas API
@@ -43,14 +51,42 @@ type Grafana struct {
 	Status ServiceStatus `json:"status,omitempty"`
 }
 
+func (in *Grafana) GetConditions() *[]metav1.Condition {
+	return &in.Status.Conditions
+}
+
 func (in *Grafana) AuthSecretRef() *AuthSecretReference {
 	return in.Spec.AuthSecretRef
 }
 
+func (in *Grafana) GetConnInfoSecretTarget() ConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget.ConnInfoSecretTarget
+}
+
+func (in *Grafana) GetServiceConnInfoSecretTarget() ServiceConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget
+}
+
+func (in *Grafana) SetProject(name string) {
+	in.Spec.Project = name
+}
+
+func (in *Grafana) GetProject() string {
+	return in.Spec.Project
+}
+
+func (in *Grafana) GetConnInfoConfigMapTarget() *ConnInfoConfigMapTarget {
+	return in.Spec.ConnInfoConfigMapTarget
+}
+
 func (in *Grafana) GetRefs() []*ResourceReferenceObject {
 	return in.Spec.GetRefs(in.GetNamespace())
 }
 
+func (in *Grafana) GetCreationDelay() int {
+	return in.Spec.CreationDelay
+}
+
 //+kubebuilder:object:root=true
 
 // GrafanaList contains a list of Grafana
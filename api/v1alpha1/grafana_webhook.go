@@ -36,6 +36,10 @@ var _ webhook.Validator = &Grafana{}
 func (in *Grafana) ValidateCreate() error {
 	grafanalog.Info("validate create", "name", in.Name)
 
+	if err := validateProject(in.Spec.Project, in.Spec.ProjectRef); err != nil {
+		return err
+	}
+
 	return in.Spec.Validate()
 }
 
@@ -43,12 +47,12 @@ func (in *Grafana) ValidateCreate() error {
 func (in *Grafana) ValidateUpdate(old runtime.Object) error {
 	grafanalog.Info("validate update", "name", in.Name)
 
-	if in.Spec.Project != old.(*Grafana).Spec.Project {
-		return errors.New("cannot update a Grafana service, project field is immutable and cannot be updated")
+	if err := validateProject(in.Spec.Project, in.Spec.ProjectRef); err != nil {
+		return err
 	}
 
-	if in.Spec.ConnInfoSecretTarget.Name != old.(*Grafana).Spec.ConnInfoSecretTarget.Name {
-		return errors.New("cannot update a Grafana service, connInfoSecretTarget.name field is immutable and cannot be updated")
+	if isProjectChanged(old.(*Grafana).Spec.Project, in.Spec.Project, in.Spec.ProjectRef) {
+		return errors.New("cannot update a Grafana service, project field is immutable and cannot be updated")
 	}
 
 	return in.Spec.Validate()
@@ -9,6 +9,7 @@ import (
 )
 
 // KafkaSpec defines the desired state of Kafka
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type KafkaSpec struct {
 	ServiceCommonSpec `json:",inline"`
 
@@ -20,7 +21,10 @@ type KafkaSpec struct {
 	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
 
 	// Information regarding secret creation
-	ConnInfoSecretTarget ConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+	ConnInfoSecretTarget KafkaConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+
+	// Information regarding config map creation. Gets non-sensitive connection info, like hosts and ports
+	ConnInfoConfigMapTarget *ConnInfoConfigMapTarget `json:"connInfoConfigMapTarget,omitempty"`
 
 	// Switch the service to use Karapace for schema registry and REST proxy
 	Karapace *bool `json:"karapace,omitempty"`
@@ -45,14 +49,42 @@ type Kafka struct {
 	Status ServiceStatus `json:"status,omitempty"`
 }
 
+func (in *Kafka) GetConditions() *[]metav1.Condition {
+	return &in.Status.Conditions
+}
+
 func (in *Kafka) AuthSecretRef() *AuthSecretReference {
 	return in.Spec.AuthSecretRef
 }
 
+func (in *Kafka) GetConnInfoSecretTarget() ConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget.ConnInfoSecretTarget
+}
+
+func (in *Kafka) GetServiceConnInfoSecretTarget() ServiceConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget.ServiceConnInfoSecretTarget
+}
+
+func (in *Kafka) SetProject(name string) {
+	in.Spec.Project = name
+}
+
+func (in *Kafka) GetProject() string {
+	return in.Spec.Project
+}
+
+func (in *Kafka) GetConnInfoConfigMapTarget() *ConnInfoConfigMapTarget {
+	return in.Spec.ConnInfoConfigMapTarget
+}
+
 func (in *Kafka) GetRefs() []*ResourceReferenceObject {
 	return in.Spec.GetRefs(in.GetNamespace())
 }
 
+func (in *Kafka) GetCreationDelay() int {
+	return in.Spec.CreationDelay
+}
+
 // +kubebuilder:object:root=true
 
 // KafkaList contains a list of Kafka
@@ -37,6 +37,10 @@ var _ webhook.Validator = &Kafka{}
 func (r *Kafka) ValidateCreate() error {
 	kafkalog.Info("validate create", "name", r.Name)
 
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
 	return r.Spec.Validate()
 }
 
@@ -44,12 +48,12 @@ func (r *Kafka) ValidateCreate() error {
 func (r *Kafka) ValidateUpdate(old runtime.Object) error {
 	kafkalog.Info("validate update", "name", r.Name)
 
-	if r.Spec.Project != old.(*Kafka).Spec.Project {
-		return errors.New("cannot update a Kafka service, project field is immutable and cannot be updated")
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
 	}
 
-	if r.Spec.ConnInfoSecretTarget.Name != old.(*Kafka).Spec.ConnInfoSecretTarget.Name {
-		return errors.New("cannot update a Kafka service, connInfoSecretTarget.name field is immutable and cannot be updated")
+	if isProjectChanged(old.(*Kafka).Spec.Project, r.Spec.Project, r.Spec.ProjectRef) {
+		return errors.New("cannot update a Kafka service, project field is immutable and cannot be updated")
 	}
 
 	return r.Spec.Validate()
@@ -7,11 +7,16 @@ import (
 )
 
 // KafkaACLSpec defines the desired state of KafkaACL
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type KafkaACLSpec struct {
 	// +kubebuilder:validation:MaxLength=63
 	// +kubebuilder:validation:Format="^[a-zA-Z0-9_-]*$"
 	// Project to link the Kafka ACL to
-	Project string `json:"project"`
+	Project string `json:"project,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// ProjectRef reference to Project resource to use its name as Project automatically
+	ProjectRef *ResourceReference `json:"projectRef,omitempty"`
 
 	// +kubebuilder:validation:MaxLength=63
 	// Service to link the Kafka ACL to
@@ -36,6 +41,12 @@ type KafkaACLStatus struct {
 	// Conditions represent the latest available observations of an KafkaACL state
 	Conditions []metav1.Condition `json:"conditions"`
 
+	// Set when the finalizer was removed by the timeout, the resource may still exist on Aiven side
+	Orphaned bool `json:"orphaned,omitempty"`
+
+	// Lifecycle phase of the resource, a summary of the conditions
+	Phase Phase `json:"phase,omitempty"`
+
 	// Kafka ACL ID
 	ID string `json:"id"`
 }
@@ -57,10 +68,29 @@ type KafkaACL struct {
 	Status KafkaACLStatus `json:"status,omitempty"`
 }
 
+func (acl *KafkaACL) GetConditions() *[]metav1.Condition {
+	return &acl.Status.Conditions
+}
+
 func (acl KafkaACL) AuthSecretRef() *AuthSecretReference {
 	return acl.Spec.AuthSecretRef
 }
 
+func (acl *KafkaACL) SetProject(name string) {
+	acl.Spec.Project = name
+}
+
+func (acl *KafkaACL) GetProject() string {
+	return acl.Spec.Project
+}
+
+func (acl *KafkaACL) GetRefs() []*ResourceReferenceObject {
+	if acl.Spec.ProjectRef == nil {
+		return nil
+	}
+	return []*ResourceReferenceObject{acl.Spec.ProjectRef.Project(acl.GetNamespace())}
+}
+
 // +kubebuilder:object:root=true
 
 // KafkaACLList contains a list of KafkaACL
@@ -35,7 +35,7 @@ var _ webhook.Validator = &KafkaACL{}
 func (r *KafkaACL) ValidateCreate() error {
 	kafkaacllog.Info("validate create", "name", r.Name)
 
-	return nil
+	return validateProject(r.Spec.Project, r.Spec.ProjectRef)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -44,7 +44,7 @@ func (r *KafkaACL) ValidateUpdate(old runtime.Object) error {
 
 	// TODO: validate that the spec does not get updated; this will fail on the aiven api
 
-	return nil
+	return validateProject(r.Spec.Project, r.Spec.ProjectRef)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -9,6 +9,7 @@ import (
 )
 
 // KafkaConnectSpec defines the desired state of KafkaConnect
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type KafkaConnectSpec struct {
 	ServiceCommonSpec `json:",inline"`
 
@@ -32,14 +33,30 @@ type KafkaConnect struct {
 	Status ServiceStatus    `json:"status,omitempty"`
 }
 
+func (in *KafkaConnect) GetConditions() *[]metav1.Condition {
+	return &in.Status.Conditions
+}
+
 func (in *KafkaConnect) AuthSecretRef() *AuthSecretReference {
 	return in.Spec.AuthSecretRef
 }
 
+func (in *KafkaConnect) SetProject(name string) {
+	in.Spec.Project = name
+}
+
+func (in *KafkaConnect) GetProject() string {
+	return in.Spec.Project
+}
+
 func (in *KafkaConnect) GetRefs() []*ResourceReferenceObject {
 	return in.Spec.GetRefs(in.GetNamespace())
 }
 
+func (in *KafkaConnect) GetCreationDelay() int {
+	return in.Spec.CreationDelay
+}
+
 // +kubebuilder:object:root=true
 
 // KafkaConnectList contains a list of KafkaConnect
@@ -37,6 +37,10 @@ var _ webhook.Validator = &KafkaConnect{}
 func (r *KafkaConnect) ValidateCreate() error {
 	kafkaconnectlog.Info("validate create", "name", r.Name)
 
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
 	return r.Spec.Validate()
 }
 
@@ -44,7 +48,11 @@ func (r *KafkaConnect) ValidateCreate() error {
 func (r *KafkaConnect) ValidateUpdate(old runtime.Object) error {
 	kafkaconnectlog.Info("validate update", "name", r.Name)
 
-	if r.Spec.Project != old.(*KafkaConnect).Spec.Project {
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
+	if isProjectChanged(old.(*KafkaConnect).Spec.Project, r.Spec.Project, r.Spec.ProjectRef) {
 		return errors.New("cannot update a KafkaConnect service, project field is immutable and cannot be updated")
 	}
 
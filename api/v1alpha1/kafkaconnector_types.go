@@ -7,11 +7,16 @@ import (
 )
 
 // KafkaConnectorSpec defines the desired state of KafkaConnector
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type KafkaConnectorSpec struct {
 	// +kubebuilder:validation:MaxLength=63
 	// +kubebuilder:validation:Format="^[a-zA-Z0-9_-]*$"
 	// Target project.
-	Project string `json:"project"`
+	Project string `json:"project,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// ProjectRef reference to Project resource to use its name as Project automatically
+	ProjectRef *ResourceReference `json:"projectRef,omitempty"`
 
 	// +kubebuilder:validation:MaxLength=63
 	// Service name.
@@ -21,13 +26,119 @@ type KafkaConnectorSpec struct {
 	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
 
 	// +kubebuilder:validation:MaxLength=1024
-	// The Java class of the connector.
-	ConnectorClass string `json:"connectorClass"`
+	// The Java class of the connector. Required unless jdbcSink, s3Sink or opensearchSink is set
+	ConnectorClass string `json:"connectorClass,omitempty"`
 
 	// The connector specific configuration
 	// To build config values from secret the template function `{{ fromSecret "name" "key" }}`
 	// is provided when interpreting the keys
-	UserConfig map[string]string `json:"userConfig"`
+	UserConfig map[string]string `json:"userConfig,omitempty"`
+
+	// JDBC sink connector from Kafka topics to a PostgreSQL service.
+	// The connector class and the connection config are built from the reference,
+	// userConfig keys override the built config
+	JDBCSink *KafkaConnectorJDBCSink `json:"jdbcSink,omitempty"`
+
+	// Aiven S3 sink connector from Kafka topics to an S3 bucket.
+	// The connector class and the bucket config are built from the fields,
+	// userConfig keys override the built config
+	S3Sink *KafkaConnectorS3Sink `json:"s3Sink,omitempty"`
+
+	// Aiven OpenSearch sink connector from Kafka topics to an OpenSearch service.
+	// The connector class, the connection and the index config are built from the fields,
+	// userConfig keys override the built config
+	OpenSearchSink *KafkaConnectorOpenSearchSink `json:"opensearchSink,omitempty"`
+}
+
+// KafkaConnectorJDBCSink defines JDBC sink connector to a PostgreSQL service
+type KafkaConnectorJDBCSink struct {
+	// PostgreSQL resource to write to, the service must be in the same project
+	PostgreSQLRef ResourceReference `json:"postgresqlRef"`
+
+	// +kubebuilder:validation:MinItems=1
+	// Kafka topics to read from
+	Topics []string `json:"topics"`
+
+	// +kubebuilder:validation:Enum=insert;upsert;update
+	// The insertion mode, insert by default
+	InsertMode string `json:"insertMode,omitempty"`
+
+	// Primary key fields for upsert and update modes, the record key is used
+	PKFields []string `json:"pkFields,omitempty"`
+
+	// Creates destination tables if they are missing
+	AutoCreate bool `json:"autoCreate,omitempty"`
+}
+
+// KafkaConnectorS3Sink defines Aiven S3 sink connector
+type KafkaConnectorS3Sink struct {
+	// +kubebuilder:validation:MinItems=1
+	// Kafka topics to read from
+	Topics []string `json:"topics"`
+
+	// +kubebuilder:validation:MinLength=3
+	// +kubebuilder:validation:MaxLength=63
+	// S3 bucket name
+	BucketName string `json:"bucketName"`
+
+	// +kubebuilder:validation:MinLength=1
+	// S3 bucket region, for instance, eu-west-1
+	Region string `json:"region"`
+
+	// Object key prefix
+	Prefix string `json:"prefix,omitempty"`
+
+	// +kubebuilder:validation:Enum=csv;json;jsonl;avro;parquet
+	// Output files format, csv by default
+	OutputFormat string `json:"outputFormat,omitempty"`
+
+	// Secret with AWS credentials
+	CredentialsSecretRef AWSCredentialsSecretReference `json:"credentialsSecretRef"`
+
+	// Topic to send the records that failed to be written to.
+	// Failed records stop the connector if not set
+	DeadLetterQueueTopic string `json:"deadLetterQueueTopic,omitempty"`
+}
+
+// KafkaConnectorOpenSearchSink defines Aiven OpenSearch sink connector to an OpenSearch service
+type KafkaConnectorOpenSearchSink struct {
+	// OpenSearch resource to write to, the service must be in the same project
+	OpenSearchRef ResourceReference `json:"opensearchRef"`
+
+	// +kubebuilder:validation:MinItems=1
+	// Kafka topics to read from
+	Topics []string `json:"topics"`
+
+	// Index name pattern, "${topic}" is replaced with the topic name,
+	// "${timestamp}" with the record timestamp formatted with timestampFormat.
+	// The topic name is used as the index name by default
+	IndexPattern string `json:"indexPattern,omitempty"`
+
+	// Java date format of "${timestamp}" in indexPattern, yyyy.MM.dd by default
+	TimestampFormat string `json:"timestampFormat,omitempty"`
+
+	// Uses the record key as the document ID, so records with the same key update the document.
+	// Otherwise, the ID is built from the topic, partition and offset
+	DocumentIDFromKey bool `json:"documentIdFromKey,omitempty"`
+
+	// Topic to send the records that failed to be indexed to.
+	// Failed records stop the connector if not set
+	DeadLetterQueueTopic string `json:"deadLetterQueueTopic,omitempty"`
+}
+
+// AWSCredentialsSecretReference references a Secret containing "AWS_ACCESS_KEY_ID" and "AWS_SECRET_ACCESS_KEY" keys
+type AWSCredentialsSecretReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// KafkaConnectorSinkStatus describes the sink of jdbcSink, s3Sink and opensearchSink connectors
+type KafkaConnectorSinkStatus struct {
+	// Where the records are written to, for instance, s3://bucket/prefix
+	Destination string `json:"destination"`
+
+	// Topic where the records that failed to be written to go
+	DeadLetterQueueTopic string `json:"deadLetterQueueTopic,omitempty"`
 }
 
 // KafkaConnectorStatus defines the observed state of KafkaConnector
@@ -35,6 +146,12 @@ type KafkaConnectorStatus struct {
 	// Conditions represent the latest available observations of an kafka connector state
 	Conditions []metav1.Condition `json:"conditions"`
 
+	// Set when the finalizer was removed by the timeout, the resource may still exist on Aiven side
+	Orphaned bool `json:"orphaned,omitempty"`
+
+	// Lifecycle phase of the resource, a summary of the conditions
+	Phase Phase `json:"phase,omitempty"`
+
 	// Connector state
 	State string `json:"state"`
 
@@ -43,6 +160,9 @@ type KafkaConnectorStatus struct {
 
 	// TasksStatus contains metadata about the running tasks
 	TasksStatus KafkaConnectorTasksStatus `json:"tasksStatus"`
+
+	// Sink of jdbcSink, s3Sink and opensearchSink connectors
+	Sink *KafkaConnectorSinkStatus `json:"sink,omitempty"`
 }
 
 // KafkaConnectorPluginStatus describes the observed state of a Kafka Connector Plugin
@@ -84,10 +204,33 @@ type KafkaConnector struct {
 	Status KafkaConnectorStatus `json:"status,omitempty"`
 }
 
+func (kfk *KafkaConnector) GetConditions() *[]metav1.Condition {
+	return &kfk.Status.Conditions
+}
+
 func (kfk KafkaConnector) AuthSecretRef() *AuthSecretReference {
 	return kfk.Spec.AuthSecretRef
 }
 
+func (kfk *KafkaConnector) SetProject(name string) {
+	kfk.Spec.Project = name
+}
+
+func (kfk *KafkaConnector) GetProject() string {
+	return kfk.Spec.Project
+}
+
+func (kfk *KafkaConnector) GetRefs() []*ResourceReferenceObject {
+	var refs []*ResourceReferenceObject
+	if kfk.Spec.ProjectRef != nil {
+		refs = append(refs, kfk.Spec.ProjectRef.Project(kfk.GetNamespace()))
+	}
+	if kfk.Spec.JDBCSink != nil {
+		refs = append(refs, kfk.Spec.JDBCSink.PostgreSQLRef.PostgreSQL(kfk.GetNamespace()))
+	}
+	return refs
+}
+
 //+kubebuilder:object:root=true
 
 // KafkaConnectorList contains a list of KafkaConnector
@@ -3,6 +3,8 @@
 package v1alpha1
 
 import (
+	"errors"
+
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -35,13 +37,37 @@ var _ webhook.Validator = &KafkaConnector{}
 func (r *KafkaConnector) ValidateCreate() error {
 	kafkaconnectorlog.Info("validate create", "name", r.Name)
 
-	return nil
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
+	return r.Spec.validate()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *KafkaConnector) ValidateUpdate(old runtime.Object) error {
 	kafkaconnectorlog.Info("validate update", "name", r.Name)
 
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
+	return r.Spec.validate()
+}
+
+func (in *KafkaConnectorSpec) validate() error {
+	sinks := 0
+	for _, set := range []bool{in.JDBCSink != nil, in.S3Sink != nil, in.OpenSearchSink != nil} {
+		if set {
+			sinks++
+		}
+	}
+	if sinks > 1 {
+		return errors.New("only one of jdbcSink, s3Sink and opensearchSink can be set")
+	}
+	if in.ConnectorClass == "" && sinks == 0 {
+		return errors.New("connectorClass cannot be empty when jdbcSink, s3Sink or opensearchSink is not set")
+	}
 	return nil
 }
 
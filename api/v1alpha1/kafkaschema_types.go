@@ -7,11 +7,16 @@ import (
 )
 
 // KafkaSchemaSpec defines the desired state of KafkaSchema
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type KafkaSchemaSpec struct {
 	// +kubebuilder:validation:MaxLength=63
 	// +kubebuilder:validation:Format="^[a-zA-Z0-9_-]*$"
 	// Project to link the Kafka Schema to
-	Project string `json:"project"`
+	Project string `json:"project,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// ProjectRef reference to Project resource to use its name as Project automatically
+	ProjectRef *ResourceReference `json:"projectRef,omitempty"`
 
 	// +kubebuilder:validation:MaxLength=63
 	// Service to link the Kafka Schema to
@@ -37,6 +42,12 @@ type KafkaSchemaStatus struct {
 	// Conditions represent the latest available observations of an KafkaSchema state
 	Conditions []metav1.Condition `json:"conditions"`
 
+	// Set when the finalizer was removed by the timeout, the resource may still exist on Aiven side
+	Orphaned bool `json:"orphaned,omitempty"`
+
+	// Lifecycle phase of the resource, a summary of the conditions
+	Phase Phase `json:"phase,omitempty"`
+
 	// Kafka Schema configuration version
 	Version int `json:"version"`
 }
@@ -58,10 +69,29 @@ type KafkaSchema struct {
 	Status KafkaSchemaStatus `json:"status,omitempty"`
 }
 
+func (kfks *KafkaSchema) GetConditions() *[]metav1.Condition {
+	return &kfks.Status.Conditions
+}
+
 func (kfks KafkaSchema) AuthSecretRef() *AuthSecretReference {
 	return kfks.Spec.AuthSecretRef
 }
 
+func (kfks *KafkaSchema) SetProject(name string) {
+	kfks.Spec.Project = name
+}
+
+func (kfks *KafkaSchema) GetProject() string {
+	return kfks.Spec.Project
+}
+
+func (kfks *KafkaSchema) GetRefs() []*ResourceReferenceObject {
+	if kfks.Spec.ProjectRef == nil {
+		return nil
+	}
+	return []*ResourceReferenceObject{kfks.Spec.ProjectRef.Project(kfks.GetNamespace())}
+}
+
 // +kubebuilder:object:root=true
 
 // KafkaSchemaList contains a list of KafkaSchema
@@ -37,14 +37,18 @@ var _ webhook.Validator = &KafkaSchema{}
 func (r *KafkaSchema) ValidateCreate() error {
 	kafkaschemalog.Info("validate create", "name", r.Name)
 
-	return nil
+	return validateProject(r.Spec.Project, r.Spec.ProjectRef)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *KafkaSchema) ValidateUpdate(old runtime.Object) error {
 	kafkaschemalog.Info("validate update", "name", r.Name)
 
-	if r.Spec.Project != old.(*KafkaSchema).Spec.Project {
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
+	if isProjectChanged(old.(*KafkaSchema).Spec.Project, r.Spec.Project, r.Spec.ProjectRef) {
 		return errors.New("cannot update a KafkaSchema, project field is immutable and cannot be updated")
 	}
 
@@ -7,11 +7,16 @@ import (
 )
 
 // KafkaTopicSpec defines the desired state of KafkaTopic
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type KafkaTopicSpec struct {
 	// +kubebuilder:validation:MaxLength=63
 	// +kubebuilder:validation:Format="^[a-zA-Z0-9_-]*$"
 	// Target project.
-	Project string `json:"project"`
+	Project string `json:"project,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// ProjectRef reference to Project resource to use its name as Project automatically
+	ProjectRef *ResourceReference `json:"projectRef,omitempty"`
 
 	// +kubebuilder:validation:MaxLength=63
 	// Service name.
@@ -45,6 +50,10 @@ type KafkaTopicSpec struct {
 	// databases containing critical data.
 	TerminationProtection *bool `json:"termination_protection,omitempty"`
 
+	// Prevents the kafka topic from being deleted while it contains messages.
+	// Set the "controllers.aiven.io/force-delete" annotation to "true" to delete it anyway
+	DeleteProtectionIfNotEmpty *bool `json:"deleteProtectionIfNotEmpty,omitempty"`
+
 	// Authentication reference to Aiven token in a secret
 	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
 }
@@ -148,6 +157,12 @@ type KafkaTopicStatus struct {
 	// Conditions represent the latest available observations of an KafkaTopic state
 	Conditions []metav1.Condition `json:"conditions"`
 
+	// Set when the finalizer was removed by the timeout, the resource may still exist on Aiven side
+	Orphaned bool `json:"orphaned,omitempty"`
+
+	// Lifecycle phase of the resource, a summary of the conditions
+	Phase Phase `json:"phase,omitempty"`
+
 	// State represents the state of the kafka topic
 	State string `json:"state"`
 }
@@ -168,10 +183,29 @@ type KafkaTopic struct {
 	Status KafkaTopicStatus `json:"status,omitempty"`
 }
 
+func (t *KafkaTopic) GetConditions() *[]metav1.Condition {
+	return &t.Status.Conditions
+}
+
 func (t *KafkaTopic) AuthSecretRef() *AuthSecretReference {
 	return t.Spec.AuthSecretRef
 }
 
+func (t *KafkaTopic) SetProject(name string) {
+	t.Spec.Project = name
+}
+
+func (t *KafkaTopic) GetProject() string {
+	return t.Spec.Project
+}
+
+func (t *KafkaTopic) GetRefs() []*ResourceReferenceObject {
+	if t.Spec.ProjectRef == nil {
+		return nil
+	}
+	return []*ResourceReferenceObject{t.Spec.ProjectRef.Project(t.GetNamespace())}
+}
+
 // +kubebuilder:object:root=true
 
 // KafkaTopicList contains a list of KafkaTopic
@@ -37,14 +37,18 @@ var _ webhook.Validator = &KafkaTopic{}
 func (r *KafkaTopic) ValidateCreate() error {
 	kafkatopiclog.Info("validate create", "name", r.Name)
 
-	return nil
+	return validateProject(r.Spec.Project, r.Spec.ProjectRef)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *KafkaTopic) ValidateUpdate(old runtime.Object) error {
 	kafkatopiclog.Info("validate update", "name", r.Name)
 
-	if r.Spec.Project != old.(*KafkaTopic).Spec.Project {
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
+	if isProjectChanged(old.(*KafkaTopic).Spec.Project, r.Spec.Project, r.Spec.ProjectRef) {
 		return errors.New("cannot update a KafkaTopic, project field is immutable and cannot be updated")
 	}
 
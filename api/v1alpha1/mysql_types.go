@@ -9,6 +9,7 @@ import (
 )
 
 // MySQLSpec defines the desired state of MySQL
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type MySQLSpec struct {
 	ServiceCommonSpec `json:",inline"`
 
@@ -20,7 +21,10 @@ type MySQLSpec struct {
 	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
 
 	// Information regarding secret creation
-	ConnInfoSecretTarget ConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+	ConnInfoSecretTarget ServiceConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+
+	// Information regarding config map creation. Gets non-sensitive connection info, like hosts and ports
+	ConnInfoConfigMapTarget *ConnInfoConfigMapTarget `json:"connInfoConfigMapTarget,omitempty"`
 
 	// MySQL specific user configuration options
 	UserConfig *mysqluserconfig.MysqlUserConfig `json:"userConfig,omitempty"`
@@ -41,14 +45,42 @@ type MySQL struct {
 	Status ServiceStatus `json:"status,omitempty"`
 }
 
+func (in *MySQL) GetConditions() *[]metav1.Condition {
+	return &in.Status.Conditions
+}
+
 func (in *MySQL) AuthSecretRef() *AuthSecretReference {
 	return in.Spec.AuthSecretRef
 }
 
+func (in *MySQL) GetConnInfoSecretTarget() ConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget.ConnInfoSecretTarget
+}
+
+func (in *MySQL) GetServiceConnInfoSecretTarget() ServiceConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget
+}
+
+func (in *MySQL) SetProject(name string) {
+	in.Spec.Project = name
+}
+
+func (in *MySQL) GetProject() string {
+	return in.Spec.Project
+}
+
+func (in *MySQL) GetConnInfoConfigMapTarget() *ConnInfoConfigMapTarget {
+	return in.Spec.ConnInfoConfigMapTarget
+}
+
 func (in *MySQL) GetRefs() []*ResourceReferenceObject {
 	return in.Spec.GetRefs(in.GetNamespace())
 }
 
+func (in *MySQL) GetCreationDelay() int {
+	return in.Spec.CreationDelay
+}
+
 //+kubebuilder:object:root=true
 
 // MySQLList contains a list of MySQL
@@ -37,6 +37,10 @@ var _ webhook.Validator = &MySQL{}
 func (in *MySQL) ValidateCreate() error {
 	mysqllog.Info("validate create", "name", in.Name)
 
+	if err := validateProject(in.Spec.Project, in.Spec.ProjectRef); err != nil {
+		return err
+	}
+
 	return in.Spec.Validate()
 }
 
@@ -44,12 +48,12 @@ func (in *MySQL) ValidateCreate() error {
 func (in *MySQL) ValidateUpdate(old runtime.Object) error {
 	mysqllog.Info("validate update", "name", in.Name)
 
-	if in.Spec.Project != old.(*MySQL).Spec.Project {
-		return errors.New("cannot update a MySQL service, project field is immutable and cannot be updated")
+	if err := validateProject(in.Spec.Project, in.Spec.ProjectRef); err != nil {
+		return err
 	}
 
-	if in.Spec.ConnInfoSecretTarget.Name != old.(*MySQL).Spec.ConnInfoSecretTarget.Name {
-		return errors.New("cannot update a MySQL service, connInfoSecretTarget.name field is immutable and cannot be updated")
+	if isProjectChanged(old.(*MySQL).Spec.Project, in.Spec.Project, in.Spec.ProjectRef) {
+		return errors.New("cannot update a MySQL service, project field is immutable and cannot be updated")
 	}
 
 	return in.Spec.Validate()
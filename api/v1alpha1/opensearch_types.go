@@ -9,6 +9,7 @@ import (
 )
 
 // OpenSearchSpec defines the desired state of OpenSearch
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type OpenSearchSpec struct {
 	ServiceCommonSpec `json:",inline"`
 
@@ -20,7 +21,10 @@ type OpenSearchSpec struct {
 	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
 
 	// Information regarding secret creation
-	ConnInfoSecretTarget ConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+	ConnInfoSecretTarget ServiceConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+
+	// Information regarding config map creation. Gets non-sensitive connection info, like hosts and ports
+	ConnInfoConfigMapTarget *ConnInfoConfigMapTarget `json:"connInfoConfigMapTarget,omitempty"`
 
 	// OpenSearch specific user configuration options
 	UserConfig *opensearchuserconfig.OpensearchUserConfig `json:"userConfig,omitempty"`
@@ -38,6 +42,10 @@ type OpenSearch struct {
 	Status ServiceStatus  `json:"status,omitempty"`
 }
 
+func (in *OpenSearch) GetConditions() *[]metav1.Condition {
+	return &in.Status.Conditions
+}
+
 //+kubebuilder:object:root=true
 
 // OpenSearchList contains a list of OpenSearch
@@ -51,10 +59,34 @@ func (in *OpenSearch) AuthSecretRef() *AuthSecretReference {
 	return in.Spec.AuthSecretRef
 }
 
+func (in *OpenSearch) GetConnInfoSecretTarget() ConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget.ConnInfoSecretTarget
+}
+
+func (in *OpenSearch) GetServiceConnInfoSecretTarget() ServiceConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget
+}
+
+func (in *OpenSearch) SetProject(name string) {
+	in.Spec.Project = name
+}
+
+func (in *OpenSearch) GetProject() string {
+	return in.Spec.Project
+}
+
+func (in *OpenSearch) GetConnInfoConfigMapTarget() *ConnInfoConfigMapTarget {
+	return in.Spec.ConnInfoConfigMapTarget
+}
+
 func (in *OpenSearch) GetRefs() []*ResourceReferenceObject {
 	return in.Spec.GetRefs(in.GetNamespace())
 }
 
+func (in *OpenSearch) GetCreationDelay() int {
+	return in.Spec.CreationDelay
+}
+
 func init() {
 	SchemeBuilder.Register(&OpenSearch{}, &OpenSearchList{})
 }
@@ -37,6 +37,10 @@ var _ webhook.Validator = &OpenSearch{}
 func (r *OpenSearch) ValidateCreate() error {
 	opensearchlog.Info("validate create", "name", r.Name)
 
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
 	return r.Spec.Validate()
 }
 
@@ -44,12 +48,12 @@ func (r *OpenSearch) ValidateCreate() error {
 func (r *OpenSearch) ValidateUpdate(old runtime.Object) error {
 	opensearchlog.Info("validate update", "name", r.Name)
 
-	if r.Spec.Project != old.(*OpenSearch).Spec.Project {
-		return errors.New("cannot update a OpenSearch service, project field is immutable and cannot be updated")
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
 	}
 
-	if r.Spec.ConnInfoSecretTarget.Name != old.(*OpenSearch).Spec.ConnInfoSecretTarget.Name {
-		return errors.New("cannot update a OpenSearch service, connInfoSecretTarget.name field is immutable and cannot be updated")
+	if isProjectChanged(old.(*OpenSearch).Spec.Project, r.Spec.Project, r.Spec.ProjectRef) {
+		return errors.New("cannot update a OpenSearch service, project field is immutable and cannot be updated")
 	}
 
 	return r.Spec.Validate()
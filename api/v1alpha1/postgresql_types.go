@@ -9,6 +9,7 @@ import (
 )
 
 // PostgreSQLSpec defines the desired state of postgres instance
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type PostgreSQLSpec struct {
 	ServiceCommonSpec `json:",inline"`
 
@@ -20,7 +21,10 @@ type PostgreSQLSpec struct {
 	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
 
 	// Information regarding secret creation
-	ConnInfoSecretTarget ConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+	ConnInfoSecretTarget ServiceConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+
+	// Information regarding config map creation. Gets non-sensitive connection info, like hosts and ports
+	ConnInfoConfigMapTarget *ConnInfoConfigMapTarget `json:"connInfoConfigMapTarget,omitempty"`
 
 	// PostgreSQL specific user configuration options
 	UserConfig *pguserconfig.PgUserConfig `json:"userConfig,omitempty"`
@@ -43,14 +47,42 @@ type PostgreSQL struct {
 	Status ServiceStatus  `json:"status,omitempty"`
 }
 
+func (in *PostgreSQL) GetConditions() *[]metav1.Condition {
+	return &in.Status.Conditions
+}
+
 func (in *PostgreSQL) AuthSecretRef() *AuthSecretReference {
 	return in.Spec.AuthSecretRef
 }
 
+func (in *PostgreSQL) GetConnInfoSecretTarget() ConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget.ConnInfoSecretTarget
+}
+
+func (in *PostgreSQL) GetServiceConnInfoSecretTarget() ServiceConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget
+}
+
+func (in *PostgreSQL) SetProject(name string) {
+	in.Spec.Project = name
+}
+
+func (in *PostgreSQL) GetProject() string {
+	return in.Spec.Project
+}
+
+func (in *PostgreSQL) GetConnInfoConfigMapTarget() *ConnInfoConfigMapTarget {
+	return in.Spec.ConnInfoConfigMapTarget
+}
+
 func (in *PostgreSQL) GetRefs() []*ResourceReferenceObject {
 	return in.Spec.GetRefs(in.GetNamespace())
 }
 
+func (in *PostgreSQL) GetCreationDelay() int {
+	return in.Spec.CreationDelay
+}
+
 // +kubebuilder:object:root=true
 
 // PostgreSQLList contains a list of PostgreSQL instances
@@ -37,6 +37,10 @@ var _ webhook.Validator = &PostgreSQL{}
 func (r *PostgreSQL) ValidateCreate() error {
 	pglog.Info("validate create", "name", r.Name)
 
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
 	return r.Spec.Validate()
 }
 
@@ -44,12 +48,12 @@ func (r *PostgreSQL) ValidateCreate() error {
 func (r *PostgreSQL) ValidateUpdate(old runtime.Object) error {
 	pglog.Info("validate update", "name", r.Name)
 
-	if r.Spec.Project != old.(*PostgreSQL).Spec.Project {
-		return errors.New("cannot update a PostgreSQL service, project field is immutable and cannot be updated")
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
 	}
 
-	if r.Spec.ConnInfoSecretTarget.Name != old.(*PostgreSQL).Spec.ConnInfoSecretTarget.Name {
-		return errors.New("cannot update a PostgreSQL service, connInfoSecretTarget.name field is immutable and cannot be updated")
+	if isProjectChanged(old.(*PostgreSQL).Spec.Project, r.Spec.Project, r.Spec.ProjectRef) {
+		return errors.New("cannot update a PostgreSQL service, project field is immutable and cannot be updated")
 	}
 
 	return r.Spec.Validate()
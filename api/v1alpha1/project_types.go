@@ -69,6 +69,12 @@ type ProjectStatus struct {
 	// Conditions represent the latest available observations of an Project state
 	Conditions []metav1.Condition `json:"conditions"`
 
+	// Set when the finalizer was removed by the timeout, the resource may still exist on Aiven side
+	Orphaned bool `json:"orphaned,omitempty"`
+
+	// Lifecycle phase of the resource, a summary of the conditions
+	Phase Phase `json:"phase,omitempty"`
+
 	// +kubebuilder:validation:MaxLength=64
 	// EU VAT Identification Number
 	VatID string `json:"vatId,omitempty"`
@@ -98,10 +104,18 @@ type Project struct {
 	Status ProjectStatus `json:"status,omitempty"`
 }
 
+func (proj *Project) GetConditions() *[]metav1.Condition {
+	return &proj.Status.Conditions
+}
+
 func (proj Project) AuthSecretRef() *AuthSecretReference {
 	return proj.Spec.AuthSecretRef
 }
 
+func (proj *Project) GetConnInfoSecretTarget() ConnInfoSecretTarget {
+	return proj.Spec.ConnInfoSecretTarget
+}
+
 // +kubebuilder:object:root=true
 
 // ProjectList contains a list of Project
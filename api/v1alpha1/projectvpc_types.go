@@ -7,12 +7,17 @@ import (
 )
 
 // ProjectVPCSpec defines the desired state of ProjectVPC
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type ProjectVPCSpec struct {
 	// +kubebuilder:validation:MaxLength=63
 	// +kubebuilder:validation:Format="^[a-zA-Z0-9_-]*$"
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	// The project the VPC belongs to
-	Project string `json:"project"`
+	Project string `json:"project,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// ProjectRef reference to Project resource to use its name as Project automatically
+	ProjectRef *ResourceReference `json:"projectRef,omitempty"`
 
 	// +kubebuilder:validation:MaxLength=256
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
@@ -33,6 +38,12 @@ type ProjectVPCStatus struct {
 	// Conditions represent the latest available observations of an ProjectVPC state
 	Conditions []metav1.Condition `json:"conditions"`
 
+	// Set when the finalizer was removed by the timeout, the resource may still exist on Aiven side
+	Orphaned bool `json:"orphaned,omitempty"`
+
+	// Lifecycle phase of the resource, a summary of the conditions
+	Phase Phase `json:"phase,omitempty"`
+
 	// State of VPC
 	State string `json:"state"`
 
@@ -56,10 +67,29 @@ type ProjectVPC struct {
 	Status ProjectVPCStatus `json:"status,omitempty"`
 }
 
+func (pvpc *ProjectVPC) GetConditions() *[]metav1.Condition {
+	return &pvpc.Status.Conditions
+}
+
 func (pvpc ProjectVPC) AuthSecretRef() *AuthSecretReference {
 	return pvpc.Spec.AuthSecretRef
 }
 
+func (pvpc *ProjectVPC) SetProject(name string) {
+	pvpc.Spec.Project = name
+}
+
+func (pvpc *ProjectVPC) GetProject() string {
+	return pvpc.Spec.Project
+}
+
+func (pvpc *ProjectVPC) GetRefs() []*ResourceReferenceObject {
+	if pvpc.Spec.ProjectRef == nil {
+		return nil
+	}
+	return []*ResourceReferenceObject{pvpc.Spec.ProjectRef.Project(pvpc.GetNamespace())}
+}
+
 // +kubebuilder:object:root=true
 
 // ProjectVPCList contains a list of ProjectVPC
@@ -9,6 +9,7 @@ import (
 )
 
 // RedisSpec defines the desired state of Redis
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type RedisSpec struct {
 	ServiceCommonSpec `json:",inline"`
 
@@ -20,7 +21,10 @@ type RedisSpec struct {
 	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
 
 	// Information regarding secret creation
-	ConnInfoSecretTarget ConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+	ConnInfoSecretTarget ServiceConnInfoSecretTarget `json:"connInfoSecretTarget,omitempty"`
+
+	// Information regarding config map creation. Gets non-sensitive connection info, like hosts and ports
+	ConnInfoConfigMapTarget *ConnInfoConfigMapTarget `json:"connInfoConfigMapTarget,omitempty"`
 
 	// Redis specific user configuration options
 	UserConfig *redisuserconfig.RedisUserConfig `json:"userConfig,omitempty"`
@@ -39,14 +43,42 @@ type Redis struct {
 	Status ServiceStatus `json:"status,omitempty"`
 }
 
+func (in *Redis) GetConditions() *[]metav1.Condition {
+	return &in.Status.Conditions
+}
+
 func (in *Redis) AuthSecretRef() *AuthSecretReference {
 	return in.Spec.AuthSecretRef
 }
 
+func (in *Redis) GetConnInfoSecretTarget() ConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget.ConnInfoSecretTarget
+}
+
+func (in *Redis) GetServiceConnInfoSecretTarget() ServiceConnInfoSecretTarget {
+	return in.Spec.ConnInfoSecretTarget
+}
+
+func (in *Redis) SetProject(name string) {
+	in.Spec.Project = name
+}
+
+func (in *Redis) GetProject() string {
+	return in.Spec.Project
+}
+
+func (in *Redis) GetConnInfoConfigMapTarget() *ConnInfoConfigMapTarget {
+	return in.Spec.ConnInfoConfigMapTarget
+}
+
 func (in *Redis) GetRefs() []*ResourceReferenceObject {
 	return in.Spec.GetRefs(in.GetNamespace())
 }
 
+func (in *Redis) GetCreationDelay() int {
+	return in.Spec.CreationDelay
+}
+
 //+kubebuilder:object:root=true
 
 // RedisList contains a list of Redis
@@ -37,6 +37,10 @@ var _ webhook.Validator = &Redis{}
 func (r *Redis) ValidateCreate() error {
 	redislog.Info("validate create", "name", r.Name)
 
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
 	return r.Spec.Validate()
 }
 
@@ -44,12 +48,12 @@ func (r *Redis) ValidateCreate() error {
 func (r *Redis) ValidateUpdate(old runtime.Object) error {
 	redislog.Info("validate update", "name", r.Name)
 
-	if r.Spec.Project != old.(*Redis).Spec.Project {
-		return errors.New("cannot update a Redis service, project field is immutable and cannot be updated")
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
 	}
 
-	if r.Spec.ConnInfoSecretTarget.Name != old.(*Redis).Spec.ConnInfoSecretTarget.Name {
-		return errors.New("cannot update a Redis service, connInfoSecretTarget.name field is immutable and cannot be updated")
+	if isProjectChanged(old.(*Redis).Spec.Project, r.Spec.Project, r.Spec.ProjectRef) {
+		return errors.New("cannot update a Redis service, project field is immutable and cannot be updated")
 	}
 
 	return r.Spec.Validate()
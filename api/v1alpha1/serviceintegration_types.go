@@ -14,18 +14,25 @@ import (
 	kafkamirrormakeruserconfig "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/kafka_mirrormaker"
 	logsuserconfig "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/logs"
 	metricsintegration "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/metrics"
+	prometheususerconfig "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/prometheus"
+	rsysloguserconfig "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/rsyslog"
 )
 
 // ServiceIntegrationSpec defines the desired state of ServiceIntegration
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type ServiceIntegrationSpec struct {
 	// +kubebuilder:validation:MaxLength=63
 	// +kubebuilder:validation:Format="^[a-zA-Z0-9_-]*$"
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	// Project the integration belongs to
-	Project string `json:"project"`
+	Project string `json:"project,omitempty"`
 
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
-	// +kubebuilder:validation:Enum=datadog;kafka_logs;kafka_connect;metrics;dashboard;rsyslog;read_replica;schema_registry_proxy;signalfx;jolokia;internal_connectivity;external_google_cloud_logging;datasource;clickhouse_postgresql;clickhouse_kafka;logs;external_aws_cloudwatch_metrics
+	// ProjectRef reference to Project resource to use its name as Project automatically
+	ProjectRef *ResourceReference `json:"projectRef,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable, delete the integration and create a new one to change it"
+	// +kubebuilder:validation:Enum=datadog;kafka_logs;kafka_connect;metrics;dashboard;rsyslog;read_replica;schema_registry_proxy;signalfx;jolokia;internal_connectivity;external_google_cloud_logging;datasource;clickhouse_postgresql;clickhouse_kafka;logs;external_aws_cloudwatch_metrics;kafka_mirrormaker;prometheus
 	// Type of the service integration
 	IntegrationType string `json:"integrationType"`
 
@@ -37,6 +44,11 @@ type ServiceIntegrationSpec struct {
 	// Source service for the integration (if any)
 	SourceServiceName string `json:"sourceServiceName,omitempty"`
 
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// SourceServiceRef reference to a service resource to use its name as SourceServiceName automatically.
+	// The integration waits until the service is running
+	SourceServiceRef *ServiceReference `json:"sourceServiceRef,omitempty"`
+
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	// Destination endpoint for the integration (if any)
 	DestinationEndpointID string `json:"destinationEndpointId,omitempty"`
@@ -45,6 +57,11 @@ type ServiceIntegrationSpec struct {
 	// Destination service for the integration (if any)
 	DestinationServiceName string `json:"destinationServiceName,omitempty"`
 
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// DestinationServiceRef reference to a service resource to use its name as DestinationServiceName automatically.
+	// The integration waits until the service is running
+	DestinationServiceRef *ServiceReference `json:"destinationServiceRef,omitempty"`
+
 	// Datadog specific user configuration options
 	DatadogUserConfig *datadogintegration.DatadogUserConfig `json:"datadog,omitempty"`
 
@@ -72,17 +89,114 @@ type ServiceIntegrationSpec struct {
 	// External AWS CloudWatch Metrics integration Logs configuration values
 	ExternalAWSCloudwatchMetricsUserConfig *externalawscloudwatchmetricsuserconfig.ExternalAwsCloudwatchMetricsUserConfig `json:"external_aws_cloudwatch_metrics,omitempty"`
 
+	// Prometheus integration configuration values
+	PrometheusUserConfig *prometheususerconfig.PrometheusUserConfig `json:"prometheus,omitempty"`
+
+	// Rsyslog integration configuration values, the rsyslog server is configured in the endpoint
+	RsyslogUserConfig *rsysloguserconfig.RsyslogUserConfig `json:"rsyslog,omitempty"`
+
+	// ConfigMap with a base user config of the integration type in the "userConfig" key (YAML or JSON).
+	// User config fields of this resource are merged on top of it.
+	// The ConfigMap changes are applied automatically
+	TemplateRef *ServiceIntegrationTemplateReference `json:"templateRef,omitempty"`
+
+	// External Schema Registry endpoint for schema_registry_proxy integration type.
+	// The endpoint is created and deleted along with the integration, sourceServiceName must be a Kafka service
+	ExternalSchemaRegistry *ExternalSchemaRegistryEndpoint `json:"externalSchemaRegistry,omitempty"`
+
+	// Datadog endpoint for datadog integration type, the API key is read from the secret.
+	// The endpoint is created and deleted along with the integration, the key changes are applied automatically
+	DatadogEndpoint *DatadogEndpoint `json:"datadogEndpoint,omitempty"`
+
+	// Enables periodic data flow check, which sets the DataFlowing condition.
+	// Emits a warning event if the integration stays inactive longer than this duration, for instance, 30m
+	InactiveThreshold *metav1.Duration `json:"inactiveThreshold,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// Seconds to defer the first reconcile of a new integration, for instance, 1 lets the integrated services go first on mass apply
+	CreationDelay int `json:"creationDelay,omitempty"`
+
+	// Creates the integration without checking that the integrated services are running,
+	// Aiven may queue it. References to other resources are still checked. A warning event is emitted on every skip
+	SkipPreconditions *bool `json:"skipPreconditions,omitempty"`
+
 	// Authentication reference to Aiven token in a secret
 	AuthSecretRef *AuthSecretReference `json:"authSecretRef,omitempty"`
 }
 
+// ServiceIntegrationTemplateReference references a ConfigMap in the same namespace
+type ServiceIntegrationTemplateReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// ExternalSchemaRegistryEndpoint defines external_schema_registry integration endpoint
+type ExternalSchemaRegistryEndpoint struct {
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=2048
+	// Schema Registry URL
+	URL string `json:"url"`
+
+	// Secret with "username" and "password" keys for basic authentication. Authentication is disabled if not set
+	BasicAuthSecretRef *BasicAuthSecretReference `json:"basicAuthSecretRef,omitempty"`
+}
+
+// BasicAuthSecretReference references a Secret containing "username" and "password" keys
+type BasicAuthSecretReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// ServiceReference references a service resource, which name is the Aiven service name
+type ServiceReference struct {
+	// +kubebuilder:validation:Enum=Cassandra;Clickhouse;Grafana;Kafka;KafkaConnect;MySQL;OpenSearch;PostgreSQL;Redis
+	// Service resource kind
+	Kind string `json:"kind"`
+
+	ResourceReference `json:",inline"`
+}
+
+// DatadogEndpoint defines datadog integration endpoint
+type DatadogEndpoint struct {
+	// +kubebuilder:validation:Enum=datadoghq.com;datadoghq.eu;us3.datadoghq.com;us5.datadoghq.com;ddog-gov.com;ap1.datadoghq.com
+	// Datadog intake site, by default, is datadoghq.com
+	Site string `json:"site,omitempty"`
+
+	// Secret key with the Datadog API key
+	APIKeySecretRef DatadogAPIKeySecretReference `json:"apiKeySecretRef"`
+}
+
+// DatadogAPIKeySecretReference references a Secret key containing a Datadog API key
+type DatadogAPIKeySecretReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
 // ServiceIntegrationStatus defines the observed state of ServiceIntegration
 type ServiceIntegrationStatus struct {
 	// Conditions represent the latest available observations of an ServiceIntegration state
 	Conditions []metav1.Condition `json:"conditions"`
 
+	// Set when the finalizer was removed by the timeout, the resource may still exist on Aiven side
+	Orphaned bool `json:"orphaned,omitempty"`
+
+	// Lifecycle phase of the resource, a summary of the conditions
+	Phase Phase `json:"phase,omitempty"`
+
 	// Service integration ID
 	ID string `json:"id"`
+
+	// Integration endpoint ID created for the external schema registry or Datadog
+	EndpointID string `json:"endpointID,omitempty"`
+
+	// Kafka cluster alias of the kafka_mirrormaker integration
+	ClusterAlias string `json:"clusterAlias,omitempty"`
+
+	// PostgreSQL databases exposed in ClickHouse by the clickhouse_postgresql integration, in "database.schema" format
+	Databases []string `json:"databases,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -103,10 +217,53 @@ type ServiceIntegration struct {
 	Status ServiceIntegrationStatus `json:"status,omitempty"`
 }
 
+func (svcint *ServiceIntegration) GetConditions() *[]metav1.Condition {
+	return &svcint.Status.Conditions
+}
+
 func (svcint ServiceIntegration) AuthSecretRef() *AuthSecretReference {
 	return svcint.Spec.AuthSecretRef
 }
 
+func (svcint *ServiceIntegration) SetProject(name string) {
+	svcint.Spec.Project = name
+}
+
+func (svcint *ServiceIntegration) GetProject() string {
+	return svcint.Spec.Project
+}
+
+func (svcint *ServiceIntegration) GetRefs() (refs []*ResourceReferenceObject) {
+	if svcint.Spec.ProjectRef != nil {
+		refs = append(refs, svcint.Spec.ProjectRef.Project(svcint.GetNamespace()))
+	}
+	if svcint.Spec.SourceServiceRef != nil {
+		refs = append(refs, svcint.Spec.SourceServiceRef.ref(svcint.Spec.SourceServiceRef.Kind, svcint.GetNamespace()))
+	}
+	if svcint.Spec.DestinationServiceRef != nil {
+		refs = append(refs, svcint.Spec.DestinationServiceRef.ref(svcint.Spec.DestinationServiceRef.Kind, svcint.GetNamespace()))
+	}
+	return refs
+}
+
+// SetServiceNames sets source and destination service names from the service references
+func (svcint *ServiceIntegration) SetServiceNames() {
+	if svcint.Spec.SourceServiceRef != nil {
+		svcint.Spec.SourceServiceName = svcint.Spec.SourceServiceRef.Name
+	}
+	if svcint.Spec.DestinationServiceRef != nil {
+		svcint.Spec.DestinationServiceName = svcint.Spec.DestinationServiceRef.Name
+	}
+}
+
+func (svcint *ServiceIntegration) GetCreationDelay() int {
+	return svcint.Spec.CreationDelay
+}
+
+func (svcint *ServiceIntegration) GetSkipPreconditions() bool {
+	return svcint.Spec.SkipPreconditions != nil && *svcint.Spec.SkipPreconditions
+}
+
 // +kubebuilder:object:root=true
 
 // ServiceIntegrationList contains a list of ServiceIntegration
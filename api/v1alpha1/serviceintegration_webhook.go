@@ -4,6 +4,7 @@ package v1alpha1
 
 import (
 	"errors"
+	"fmt"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -27,6 +28,10 @@ var _ webhook.Defaulter = &ServiceIntegration{}
 // Default implements webhook.Defaulter so a webhook will be registered for the type
 func (r *ServiceIntegration) Default() {
 	serviceintegrationlog.Info("default", "name", r.Name)
+
+	if r.Spec.validateServiceRefs() == nil {
+		r.SetServiceNames()
+	}
 }
 
 //+kubebuilder:webhook:verbs=create;update,path=/validate-aiven-io-v1alpha1-serviceintegration,mutating=false,failurePolicy=fail,groups=aiven.io,resources=serviceintegrations,versions=v1alpha1,name=vserviceintegration.kb.io,sideEffects=none,admissionReviewVersions=v1
@@ -37,11 +42,27 @@ var _ webhook.Validator = &ServiceIntegration{}
 func (r *ServiceIntegration) ValidateCreate() error {
 	serviceintegrationlog.Info("validate create", "name", r.Name)
 
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
+	if err := r.Spec.validateServiceRefs(); err != nil {
+		return err
+	}
+
 	if (r.Spec.SourceServiceName == "" && r.Spec.DestinationServiceName == "") &&
 		(r.Spec.SourceEndpointID == "" && r.Spec.DestinationEndpointID == "") {
 		return errors.New("cannot create service integration when source and destination fields are empty")
 	}
 
+	if r.Spec.ExternalSchemaRegistry != nil {
+		return r.Spec.validateExternalSchemaRegistry()
+	}
+
+	if r.Spec.DatadogEndpoint != nil {
+		return r.Spec.validateDatadogEndpoint()
+	}
+
 	if r.Spec.SourceServiceName != "" && r.Spec.DestinationServiceName == "" {
 		return errors.New("destinationServiceName cannot be empty when sourceServiceName is set")
 	}
@@ -58,19 +79,31 @@ func (r *ServiceIntegration) ValidateCreate() error {
 		return errors.New("destinationEndpointID cannot be empty when sourceEndpointID is set")
 	}
 
-	return nil
+	return r.Spec.validateUserConfig()
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *ServiceIntegration) ValidateUpdate(old runtime.Object) error {
 	serviceintegrationlog.Info("validate update", "name", r.Name)
 
-	if r.Spec.Project != old.(*ServiceIntegration).Spec.Project {
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
+	if err := r.Spec.validateServiceRefs(); err != nil {
+		return err
+	}
+
+	if isProjectChanged(old.(*ServiceIntegration).Spec.Project, r.Spec.Project, r.Spec.ProjectRef) {
 		return errors.New("cannot update service integration, project field is idempotent")
 	}
 
-	if r.Spec.IntegrationType != old.(*ServiceIntegration).Spec.IntegrationType {
-		return errors.New("cannot update service integration, integrationType field is idempotent")
+	// Aiven can't change the type of an existing integration, updating it would apply the new user config to the old type
+	if oldType := old.(*ServiceIntegration).Spec.IntegrationType; r.Spec.IntegrationType != oldType {
+		return fmt.Errorf(
+			"cannot change service integration integrationType from %q to %q, the field is immutable: delete the integration and create a new one",
+			oldType, r.Spec.IntegrationType,
+		)
 	}
 
 	if r.Spec.SourceEndpointID != old.(*ServiceIntegration).Spec.SourceEndpointID {
@@ -89,9 +122,98 @@ func (r *ServiceIntegration) ValidateUpdate(old runtime.Object) error {
 		return errors.New("cannot update service integration, destinationServiceName field is idempotent")
 	}
 
+	if (r.Spec.ExternalSchemaRegistry == nil) != (old.(*ServiceIntegration).Spec.ExternalSchemaRegistry == nil) {
+		return errors.New("cannot update service integration, externalSchemaRegistry cannot be added or removed")
+	}
+
+	if r.Spec.ExternalSchemaRegistry != nil {
+		return r.Spec.validateExternalSchemaRegistry()
+	}
+
+	if (r.Spec.DatadogEndpoint == nil) != (old.(*ServiceIntegration).Spec.DatadogEndpoint == nil) {
+		return errors.New("cannot update service integration, datadogEndpoint cannot be added or removed")
+	}
+
+	if r.Spec.DatadogEndpoint != nil {
+		return r.Spec.validateDatadogEndpoint()
+	}
+
+	return r.Spec.validateUserConfig()
+}
+
+// validateServiceRefs checks that service names, if set, match the service references
+func (in *ServiceIntegrationSpec) validateServiceRefs() error {
+	if in.SourceServiceRef != nil && in.SourceServiceName != "" && in.SourceServiceName != in.SourceServiceRef.Name {
+		return errors.New("sourceServiceName and sourceServiceRef point to different services")
+	}
+	if in.DestinationServiceRef != nil && in.DestinationServiceName != "" && in.DestinationServiceName != in.DestinationServiceRef.Name {
+		return errors.New("destinationServiceName and destinationServiceRef point to different services")
+	}
+	return nil
+}
+
+// validateUserConfig checks that only the user config of the integration type is set.
+// Value ranges and enums are validated by the CRD schema
+func (in *ServiceIntegrationSpec) validateUserConfig() error {
+	configs := []struct {
+		integrationType string
+		isSet           bool
+	}{
+		{"datadog", in.DatadogUserConfig != nil},
+		{"kafka_connect", in.KafkaConnectUserConfig != nil},
+		{"kafka_logs", in.KafkaLogsUserConfig != nil},
+		{"metrics", in.MetricsUserConfig != nil},
+		{"clickhouse_postgresql", in.ClickhousePostgreSQLUserConfig != nil},
+		{"clickhouse_kafka", in.ClickhouseKafkaUserConfig != nil},
+		{"kafka_mirrormaker", in.KafkaMirrormakerUserConfig != nil},
+		{"logs", in.LogsUserConfig != nil},
+		{"external_aws_cloudwatch_metrics", in.ExternalAWSCloudwatchMetricsUserConfig != nil},
+		{"prometheus", in.PrometheusUserConfig != nil},
+		{"rsyslog", in.RsyslogUserConfig != nil},
+	}
+
+	for _, c := range configs {
+		if c.isSet && c.integrationType != in.IntegrationType {
+			return fmt.Errorf("%s user config cannot be used with %s integration type", c.integrationType, in.IntegrationType)
+		}
+	}
 	return nil
 }
 
+// validateExternalSchemaRegistry checks that the managed endpoint is the only destination of the integration
+func (in *ServiceIntegrationSpec) validateExternalSchemaRegistry() error {
+	if in.IntegrationType != "schema_registry_proxy" {
+		return errors.New("externalSchemaRegistry can be used only with schema_registry_proxy integration type")
+	}
+
+	if in.SourceServiceName == "" {
+		return errors.New("sourceServiceName cannot be empty when externalSchemaRegistry is set")
+	}
+
+	if in.DestinationServiceName != "" || in.DestinationEndpointID != "" || in.SourceEndpointID != "" {
+		return errors.New("endpoint fields and destinationServiceName cannot be set when externalSchemaRegistry is set")
+	}
+
+	return in.validateUserConfig()
+}
+
+// validateDatadogEndpoint checks that the managed endpoint is the only destination of the integration
+func (in *ServiceIntegrationSpec) validateDatadogEndpoint() error {
+	if in.IntegrationType != "datadog" {
+		return errors.New("datadogEndpoint can be used only with datadog integration type")
+	}
+
+	if in.SourceServiceName == "" {
+		return errors.New("sourceServiceName cannot be empty when datadogEndpoint is set")
+	}
+
+	if in.DestinationServiceName != "" || in.DestinationEndpointID != "" || in.SourceEndpointID != "" {
+		return errors.New("endpoint fields and destinationServiceName cannot be set when datadogEndpoint is set")
+	}
+
+	return in.validateUserConfig()
+}
+
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
 func (r *ServiceIntegration) ValidateDelete() error {
 	serviceintegrationlog.Info("validate delete", "name", r.Name)
@@ -0,0 +1,127 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	datadogintegration "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/datadog"
+	logsuserconfig "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/logs"
+	metricsintegration "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/metrics"
+	prometheususerconfig "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/prometheus"
+	rsysloguserconfig "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/rsyslog"
+)
+
+func Test_serviceIntegrationTypeImmutable(t *testing.T) {
+	old := &ServiceIntegration{Spec: ServiceIntegrationSpec{
+		Project:           "project",
+		IntegrationType:   "metrics",
+		SourceServiceName: "pg",
+	}}
+
+	changed := old.DeepCopy()
+	changed.Spec.IntegrationType = "logs"
+	err := changed.ValidateUpdate(old)
+	if err == nil || !strings.Contains(err.Error(), "delete the integration and create a new one") {
+		t.Errorf("ValidateUpdate() error = %v, want integrationType immutable error", err)
+	}
+
+	if err = old.DeepCopy().ValidateUpdate(old); err != nil {
+		t.Errorf("ValidateUpdate() unchanged error = %v", err)
+	}
+}
+
+func Test_serviceIntegrationValidateUserConfig(t *testing.T) {
+	cases := []struct {
+		name            string
+		integrationType string
+		setConfig       func(spec *ServiceIntegrationSpec)
+		wantErr         string
+	}{
+		{
+			name:            "no user config",
+			integrationType: "metrics",
+			setConfig:       func(spec *ServiceIntegrationSpec) {},
+		},
+		{
+			name:            "matching metrics config",
+			integrationType: "metrics",
+			setConfig: func(spec *ServiceIntegrationSpec) {
+				spec.MetricsUserConfig = &metricsintegration.MetricsUserConfig{}
+			},
+		},
+		{
+			name:            "matching prometheus config",
+			integrationType: "prometheus",
+			setConfig: func(spec *ServiceIntegrationSpec) {
+				spec.PrometheusUserConfig = &prometheususerconfig.PrometheusUserConfig{}
+			},
+		},
+		{
+			name:            "matching rsyslog config",
+			integrationType: "rsyslog",
+			setConfig: func(spec *ServiceIntegrationSpec) {
+				spec.RsyslogUserConfig = &rsysloguserconfig.RsyslogUserConfig{}
+			},
+		},
+		{
+			name:            "datadog config with metrics type",
+			integrationType: "metrics",
+			setConfig: func(spec *ServiceIntegrationSpec) {
+				spec.DatadogUserConfig = &datadogintegration.DatadogUserConfig{}
+			},
+			wantErr: "datadog user config cannot be used with metrics integration type",
+		},
+		{
+			name:            "prometheus config with logs type",
+			integrationType: "logs",
+			setConfig: func(spec *ServiceIntegrationSpec) {
+				spec.PrometheusUserConfig = &prometheususerconfig.PrometheusUserConfig{}
+			},
+			wantErr: "prometheus user config cannot be used with logs integration type",
+		},
+		{
+			name:            "rsyslog config with metrics type",
+			integrationType: "metrics",
+			setConfig: func(spec *ServiceIntegrationSpec) {
+				spec.RsyslogUserConfig = &rsysloguserconfig.RsyslogUserConfig{}
+			},
+			wantErr: "rsyslog user config cannot be used with metrics integration type",
+		},
+		{
+			name:            "matching and foreign configs",
+			integrationType: "logs",
+			setConfig: func(spec *ServiceIntegrationSpec) {
+				spec.LogsUserConfig = &logsuserconfig.LogsUserConfig{}
+				spec.MetricsUserConfig = &metricsintegration.MetricsUserConfig{}
+			},
+			wantErr: "metrics user config cannot be used with logs integration type",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			old := &ServiceIntegration{Spec: ServiceIntegrationSpec{
+				Project:                "project",
+				IntegrationType:        c.integrationType,
+				SourceServiceName:      "source",
+				DestinationServiceName: "destination",
+			}}
+			r := old.DeepCopy()
+			c.setConfig(&r.Spec)
+
+			for op, err := range map[string]error{
+				"ValidateCreate": r.ValidateCreate(),
+				"ValidateUpdate": r.ValidateUpdate(old),
+			} {
+				if c.wantErr == "" && err != nil {
+					t.Errorf("%s() error = %v, want nil", op, err)
+				}
+				if c.wantErr != "" && (err == nil || err.Error() != c.wantErr) {
+					t.Errorf("%s() error = %v, want %q", op, err, c.wantErr)
+				}
+			}
+		})
+	}
+}
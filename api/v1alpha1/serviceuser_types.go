@@ -7,11 +7,16 @@ import (
 )
 
 // ServiceUserSpec defines the desired state of ServiceUser
+// +kubebuilder:validation:XValidation:rule="has(self.project) || has(self.projectRef)",message="Either project or projectRef must be set"
 type ServiceUserSpec struct {
 	// +kubebuilder:validation:MaxLength=63
 	// +kubebuilder:validation:Format="^[a-zA-Z0-9_-]*$"
 	// Project to link the user to
-	Project string `json:"project"`
+	Project string `json:"project,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	// ProjectRef reference to Project resource to use its name as Project automatically
+	ProjectRef *ResourceReference `json:"projectRef,omitempty"`
 
 	// +kubebuilder:validation:MaxLength=63
 	// Service to link the user to
@@ -33,6 +38,12 @@ type ServiceUserStatus struct {
 	// Conditions represent the latest available observations of an ServiceUser state
 	Conditions []metav1.Condition `json:"conditions"`
 
+	// Set when the finalizer was removed by the timeout, the resource may still exist on Aiven side
+	Orphaned bool `json:"orphaned,omitempty"`
+
+	// Lifecycle phase of the resource, a summary of the conditions
+	Phase Phase `json:"phase,omitempty"`
+
 	// Type of the user account
 	Type string `json:"type,omitempty"`
 }
@@ -52,10 +63,33 @@ type ServiceUser struct {
 	Status ServiceUserStatus `json:"status,omitempty"`
 }
 
+func (svcusr *ServiceUser) GetConditions() *[]metav1.Condition {
+	return &svcusr.Status.Conditions
+}
+
 func (svcusr ServiceUser) AuthSecretRef() *AuthSecretReference {
 	return svcusr.Spec.AuthSecretRef
 }
 
+func (svcusr *ServiceUser) GetConnInfoSecretTarget() ConnInfoSecretTarget {
+	return svcusr.Spec.ConnInfoSecretTarget
+}
+
+func (svcusr *ServiceUser) SetProject(name string) {
+	svcusr.Spec.Project = name
+}
+
+func (svcusr *ServiceUser) GetProject() string {
+	return svcusr.Spec.Project
+}
+
+func (svcusr *ServiceUser) GetRefs() []*ResourceReferenceObject {
+	if svcusr.Spec.ProjectRef == nil {
+		return nil
+	}
+	return []*ResourceReferenceObject{svcusr.Spec.ProjectRef.Project(svcusr.GetNamespace())}
+}
+
 // +kubebuilder:object:root=true
 
 // ServiceUserList contains a list of ServiceUser
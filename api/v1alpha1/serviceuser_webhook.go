@@ -38,14 +38,18 @@ var _ webhook.Validator = &ServiceUser{}
 func (r *ServiceUser) ValidateCreate() error {
 	serviceuserlog.Info("validate create", "name", r.Name)
 
-	return nil
+	return validateProject(r.Spec.Project, r.Spec.ProjectRef)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *ServiceUser) ValidateUpdate(old runtime.Object) error {
 	serviceuserlog.Info("validate update", "name", r.Name)
 
-	if r.Spec.Project != old.(*ServiceUser).Spec.Project {
+	if err := validateProject(r.Spec.Project, r.Spec.ProjectRef); err != nil {
+		return err
+	}
+
+	if isProjectChanged(old.(*ServiceUser).Spec.Project, r.Spec.Project, r.Spec.ProjectRef) {
 		return errors.New("cannot update a Service User, project field is immutable and cannot be updated")
 	}
 
@@ -53,10 +57,6 @@ func (r *ServiceUser) ValidateUpdate(old runtime.Object) error {
 		return errors.New("cannot update a Service User, serviceName field is immutable and cannot be updated")
 	}
 
-	if r.Spec.ConnInfoSecretTarget.Name != old.(*ServiceUser).Spec.ConnInfoSecretTarget.Name {
-		return errors.New("cannot update a ServiceUser, connInfoSecretTarget.name field is immutable and cannot be updated")
-	}
-
 	return nil
 }
 
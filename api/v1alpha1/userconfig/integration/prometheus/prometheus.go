@@ -0,0 +1,67 @@
+// Code generated by user config generator. DO NOT EDIT.
+// +kubebuilder:object:generate=true
+
+package prometheususerconfig
+
+// Configuration options for Telegraf MySQL input plugin
+type Telegraf struct {
+	// Gather metrics from PERFORMANCE_SCHEMA.EVENT_WAITS
+	GatherEventWaits *bool `groups:"create,update" json:"gather_event_waits,omitempty"`
+
+	// gather metrics from PERFORMANCE_SCHEMA.FILE_SUMMARY_BY_EVENT_NAME
+	GatherFileEventsStats *bool `groups:"create,update" json:"gather_file_events_stats,omitempty"`
+
+	// Gather metrics from PERFORMANCE_SCHEMA.TABLE_IO_WAITS_SUMMARY_BY_INDEX_USAGE
+	GatherIndexIoWaits *bool `groups:"create,update" json:"gather_index_io_waits,omitempty"`
+
+	// Gather auto_increment columns and max values from information schema
+	GatherInfoSchemaAutoInc *bool `groups:"create,update" json:"gather_info_schema_auto_inc,omitempty"`
+
+	// Gather metrics from INFORMATION_SCHEMA.INNODB_METRICS
+	GatherInnodbMetrics *bool `groups:"create,update" json:"gather_innodb_metrics,omitempty"`
+
+	// Gather metrics from PERFORMANCE_SCHEMA.EVENTS_STATEMENTS_SUMMARY_BY_DIGEST
+	GatherPerfEventsStatements *bool `groups:"create,update" json:"gather_perf_events_statements,omitempty"`
+
+	// Gather thread state counts from INFORMATION_SCHEMA.PROCESSLIST
+	GatherProcessList *bool `groups:"create,update" json:"gather_process_list,omitempty"`
+
+	// Gather metrics from SHOW SLAVE STATUS command output
+	GatherSlaveStatus *bool `groups:"create,update" json:"gather_slave_status,omitempty"`
+
+	// Gather metrics from PERFORMANCE_SCHEMA.TABLE_IO_WAITS_SUMMARY_BY_TABLE
+	GatherTableIoWaits *bool `groups:"create,update" json:"gather_table_io_waits,omitempty"`
+
+	// Gather metrics from PERFORMANCE_SCHEMA.TABLE_LOCK_WAITS
+	GatherTableLockWaits *bool `groups:"create,update" json:"gather_table_lock_waits,omitempty"`
+
+	// Gather metrics from INFORMATION_SCHEMA.TABLES
+	GatherTableSchema *bool `groups:"create,update" json:"gather_table_schema,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2048
+	// Truncates digest text from perf_events_statements into this many characters
+	PerfEventsStatementsDigestTextLimit *int `groups:"create,update" json:"perf_events_statements_digest_text_limit,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=4000
+	// Limits metrics from perf_events_statements
+	PerfEventsStatementsLimit *int `groups:"create,update" json:"perf_events_statements_limit,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2592000
+	// Only include perf_events_statements whose last seen is less than this many seconds
+	PerfEventsStatementsTimeLimit *int `groups:"create,update" json:"perf_events_statements_time_limit,omitempty"`
+}
+
+// Configuration options for metrics where source service is MySQL
+type SourceMysql struct {
+	// Configuration options for Telegraf MySQL input plugin
+	Telegraf *Telegraf `groups:"create,update" json:"telegraf,omitempty"`
+}
+
+// Integration user config
+type PrometheusUserConfig struct {
+	// Configuration options for metrics where source service is MySQL
+	SourceMysql *SourceMysql `groups:"create,update" json:"source_mysql,omitempty"`
+}
@@ -0,0 +1,7 @@
+// Code generated by user config generator. DO NOT EDIT.
+// +kubebuilder:object:generate=true
+
+package rsysloguserconfig
+
+// Integration user config
+type RsyslogUserConfig struct{}
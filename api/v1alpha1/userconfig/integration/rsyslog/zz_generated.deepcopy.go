@@ -0,0 +1,25 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package rsysloguserconfig
+
+import ()
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RsyslogUserConfig) DeepCopyInto(out *RsyslogUserConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RsyslogUserConfig.
+func (in *RsyslogUserConfig) DeepCopy() *RsyslogUserConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RsyslogUserConfig)
+	in.DeepCopyInto(out)
+	return out
+}
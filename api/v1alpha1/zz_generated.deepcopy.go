@@ -17,6 +17,8 @@ import (
 	kafka_mirrormaker "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/kafka_mirrormaker"
 	logs "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/logs"
 	metrics "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/metrics"
+	prometheus "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/prometheus"
+	rsyslog "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/rsyslog"
 	cassandra "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/service/cassandra"
 	clickhouse "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/service/clickhouse"
 	grafana "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/service/grafana"
@@ -30,6 +32,148 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSCredentialsSecretReference) DeepCopyInto(out *AWSCredentialsSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSCredentialsSecretReference.
+func (in *AWSCredentialsSecretReference) DeepCopy() *AWSCredentialsSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSCredentialsSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountAuthentication) DeepCopyInto(out *AccountAuthentication) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountAuthentication.
+func (in *AccountAuthentication) DeepCopy() *AccountAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccountAuthentication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountAuthenticationList) DeepCopyInto(out *AccountAuthenticationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AccountAuthentication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountAuthenticationList.
+func (in *AccountAuthenticationList) DeepCopy() *AccountAuthenticationList {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountAuthenticationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccountAuthenticationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountAuthenticationSAML) DeepCopyInto(out *AccountAuthenticationSAML) {
+	*out = *in
+	out.CertificateSecretRef = in.CertificateSecretRef
+	if in.FieldMapping != nil {
+		in, out := &in.FieldMapping, &out.FieldMapping
+		*out = new(SAMLFieldMapping)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountAuthenticationSAML.
+func (in *AccountAuthenticationSAML) DeepCopy() *AccountAuthenticationSAML {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountAuthenticationSAML)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountAuthenticationSpec) DeepCopyInto(out *AccountAuthenticationSpec) {
+	*out = *in
+	if in.SAML != nil {
+		in, out := &in.SAML, &out.SAML
+		*out = new(AccountAuthenticationSAML)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthSecretRef != nil {
+		in, out := &in.AuthSecretRef, &out.AuthSecretRef
+		*out = new(AuthSecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountAuthenticationSpec.
+func (in *AccountAuthenticationSpec) DeepCopy() *AccountAuthenticationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountAuthenticationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountAuthenticationStatus) DeepCopyInto(out *AccountAuthenticationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountAuthenticationStatus.
+func (in *AccountAuthenticationStatus) DeepCopy() *AccountAuthenticationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountAuthenticationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthSecretReference) DeepCopyInto(out *AuthSecretReference) {
 	*out = *in
@@ -45,6 +189,21 @@ func (in *AuthSecretReference) DeepCopy() *AuthSecretReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BasicAuthSecretReference) DeepCopyInto(out *BasicAuthSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasicAuthSecretReference.
+func (in *BasicAuthSecretReference) DeepCopy() *BasicAuthSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(BasicAuthSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cassandra) DeepCopyInto(out *Cassandra) {
 	*out = *in
@@ -113,7 +272,12 @@ func (in *CassandraSpec) DeepCopyInto(out *CassandraSpec) {
 		*out = new(AuthSecretReference)
 		**out = **in
 	}
-	out.ConnInfoSecretTarget = in.ConnInfoSecretTarget
+	in.ConnInfoSecretTarget.DeepCopyInto(&out.ConnInfoSecretTarget)
+	if in.ConnInfoConfigMapTarget != nil {
+		in, out := &in.ConnInfoConfigMapTarget, &out.ConnInfoConfigMapTarget
+		*out = new(ConnInfoConfigMapTarget)
+		**out = **in
+	}
 	if in.UserConfig != nil {
 		in, out := &in.UserConfig, &out.UserConfig
 		*out = new(cassandra.CassandraUserConfig)
@@ -199,7 +363,12 @@ func (in *ClickhouseSpec) DeepCopyInto(out *ClickhouseSpec) {
 		*out = new(AuthSecretReference)
 		**out = **in
 	}
-	out.ConnInfoSecretTarget = in.ConnInfoSecretTarget
+	in.ConnInfoSecretTarget.DeepCopyInto(&out.ConnInfoSecretTarget)
+	if in.ConnInfoConfigMapTarget != nil {
+		in, out := &in.ConnInfoConfigMapTarget, &out.ConnInfoConfigMapTarget
+		*out = new(ConnInfoConfigMapTarget)
+		**out = **in
+	}
 	if in.UserConfig != nil {
 		in, out := &in.UserConfig, &out.UserConfig
 		*out = new(clickhouse.ClickhouseUserConfig)
@@ -279,7 +448,12 @@ func (in *ClickhouseUserList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClickhouseUserSpec) DeepCopyInto(out *ClickhouseUserSpec) {
 	*out = *in
-	out.ConnInfoSecretTarget = in.ConnInfoSecretTarget
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(ResourceReference)
+		**out = **in
+	}
+	in.ConnInfoSecretTarget.DeepCopyInto(&out.ConnInfoSecretTarget)
 	if in.AuthSecretRef != nil {
 		in, out := &in.AuthSecretRef, &out.AuthSecretRef
 		*out = new(AuthSecretReference)
@@ -319,9 +493,41 @@ func (in *ClickhouseUserStatus) DeepCopy() *ClickhouseUserStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnInfoConfigMapTarget) DeepCopyInto(out *ConnInfoConfigMapTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnInfoConfigMapTarget.
+func (in *ConnInfoConfigMapTarget) DeepCopy() *ConnInfoConfigMapTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnInfoConfigMapTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnInfoSecretTarget) DeepCopyInto(out *ConnInfoSecretTarget) {
 	*out = *in
+	if in.KeyEncodings != nil {
+		in, out := &in.KeyEncodings, &out.KeyEncodings
+		*out = make([]SecretKeyEncoding, len(*in))
+		copy(*out, *in)
+	}
+	if in.KeyNames != nil {
+		in, out := &in.KeyNames, &out.KeyNames
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PreviousCredentialsGracePeriod != nil {
+		in, out := &in.PreviousCredentialsGracePeriod, &out.PreviousCredentialsGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnInfoSecretTarget.
@@ -396,7 +602,12 @@ func (in *ConnectionPoolList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectionPoolSpec) DeepCopyInto(out *ConnectionPoolSpec) {
 	*out = *in
-	out.ConnInfoSecretTarget = in.ConnInfoSecretTarget
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(ResourceReference)
+		**out = **in
+	}
+	in.ConnInfoSecretTarget.DeepCopyInto(&out.ConnInfoSecretTarget)
 	if in.AuthSecretRef != nil {
 		in, out := &in.AuthSecretRef, &out.AuthSecretRef
 		*out = new(AuthSecretReference)
@@ -498,6 +709,11 @@ func (in *DatabaseList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatabaseSpec) DeepCopyInto(out *DatabaseSpec) {
 	*out = *in
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(ResourceReference)
+		**out = **in
+	}
 	if in.TerminationProtection != nil {
 		in, out := &in.TerminationProtection, &out.TerminationProtection
 		*out = new(bool)
@@ -542,6 +758,72 @@ func (in *DatabaseStatus) DeepCopy() *DatabaseStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAPIKeySecretReference) DeepCopyInto(out *DatadogAPIKeySecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAPIKeySecretReference.
+func (in *DatadogAPIKeySecretReference) DeepCopy() *DatadogAPIKeySecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAPIKeySecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogEndpoint) DeepCopyInto(out *DatadogEndpoint) {
+	*out = *in
+	out.APIKeySecretRef = in.APIKeySecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogEndpoint.
+func (in *DatadogEndpoint) DeepCopy() *DatadogEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunChange) DeepCopyInto(out *DryRunChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunChange.
+func (in *DryRunChange) DeepCopy() *DryRunChange {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalSchemaRegistryEndpoint) DeepCopyInto(out *ExternalSchemaRegistryEndpoint) {
+	*out = *in
+	if in.BasicAuthSecretRef != nil {
+		in, out := &in.BasicAuthSecretRef, &out.BasicAuthSecretRef
+		*out = new(BasicAuthSecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalSchemaRegistryEndpoint.
+func (in *ExternalSchemaRegistryEndpoint) DeepCopy() *ExternalSchemaRegistryEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalSchemaRegistryEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Grafana) DeepCopyInto(out *Grafana) {
 	*out = *in
@@ -610,12 +892,22 @@ func (in *GrafanaSpec) DeepCopyInto(out *GrafanaSpec) {
 		*out = new(AuthSecretReference)
 		**out = **in
 	}
-	out.ConnInfoSecretTarget = in.ConnInfoSecretTarget
+	in.ConnInfoSecretTarget.DeepCopyInto(&out.ConnInfoSecretTarget)
+	if in.ConnInfoConfigMapTarget != nil {
+		in, out := &in.ConnInfoConfigMapTarget, &out.ConnInfoConfigMapTarget
+		*out = new(ConnInfoConfigMapTarget)
+		**out = **in
+	}
 	if in.UserConfig != nil {
 		in, out := &in.UserConfig, &out.UserConfig
 		*out = new(grafana.GrafanaUserConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MetricsDatasources != nil {
+		in, out := &in.MetricsDatasources, &out.MetricsDatasources
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSpec.
@@ -628,6 +920,21 @@ func (in *GrafanaSpec) DeepCopy() *GrafanaSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPFilterConfigMapReference) DeepCopyInto(out *IPFilterConfigMapReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPFilterConfigMapReference.
+func (in *IPFilterConfigMapReference) DeepCopy() *IPFilterConfigMapReference {
+	if in == nil {
+		return nil
+	}
+	out := new(IPFilterConfigMapReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Kafka) DeepCopyInto(out *Kafka) {
 	*out = *in
@@ -717,6 +1024,11 @@ func (in *KafkaACLList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaACLSpec) DeepCopyInto(out *KafkaACLSpec) {
 	*out = *in
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(ResourceReference)
+		**out = **in
+	}
 	if in.AuthSecretRef != nil {
 		in, out := &in.AuthSecretRef, &out.AuthSecretRef
 		*out = new(AuthSecretReference)
@@ -756,6 +1068,27 @@ func (in *KafkaACLStatus) DeepCopy() *KafkaACLStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaConnInfoSecretTarget) DeepCopyInto(out *KafkaConnInfoSecretTarget) {
+	*out = *in
+	in.ServiceConnInfoSecretTarget.DeepCopyInto(&out.ServiceConnInfoSecretTarget)
+	if in.KafkaConnect != nil {
+		in, out := &in.KafkaConnect, &out.KafkaConnect
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaConnInfoSecretTarget.
+func (in *KafkaConnInfoSecretTarget) DeepCopy() *KafkaConnInfoSecretTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaConnInfoSecretTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaConnect) DeepCopyInto(out *KafkaConnect) {
 	*out = *in
@@ -868,6 +1201,32 @@ func (in *KafkaConnector) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaConnectorJDBCSink) DeepCopyInto(out *KafkaConnectorJDBCSink) {
+	*out = *in
+	out.PostgreSQLRef = in.PostgreSQLRef
+	if in.Topics != nil {
+		in, out := &in.Topics, &out.Topics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PKFields != nil {
+		in, out := &in.PKFields, &out.PKFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaConnectorJDBCSink.
+func (in *KafkaConnectorJDBCSink) DeepCopy() *KafkaConnectorJDBCSink {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaConnectorJDBCSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaConnectorList) DeepCopyInto(out *KafkaConnectorList) {
 	*out = *in
@@ -900,17 +1259,74 @@ func (in *KafkaConnectorList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaConnectorOpenSearchSink) DeepCopyInto(out *KafkaConnectorOpenSearchSink) {
+	*out = *in
+	out.OpenSearchRef = in.OpenSearchRef
+	if in.Topics != nil {
+		in, out := &in.Topics, &out.Topics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaConnectorOpenSearchSink.
+func (in *KafkaConnectorOpenSearchSink) DeepCopy() *KafkaConnectorOpenSearchSink {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaConnectorOpenSearchSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaConnectorPluginStatus) DeepCopyInto(out *KafkaConnectorPluginStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaConnectorPluginStatus.
-func (in *KafkaConnectorPluginStatus) DeepCopy() *KafkaConnectorPluginStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaConnectorPluginStatus.
+func (in *KafkaConnectorPluginStatus) DeepCopy() *KafkaConnectorPluginStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaConnectorPluginStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaConnectorS3Sink) DeepCopyInto(out *KafkaConnectorS3Sink) {
+	*out = *in
+	if in.Topics != nil {
+		in, out := &in.Topics, &out.Topics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaConnectorS3Sink.
+func (in *KafkaConnectorS3Sink) DeepCopy() *KafkaConnectorS3Sink {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaConnectorS3Sink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaConnectorSinkStatus) DeepCopyInto(out *KafkaConnectorSinkStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaConnectorSinkStatus.
+func (in *KafkaConnectorSinkStatus) DeepCopy() *KafkaConnectorSinkStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(KafkaConnectorPluginStatus)
+	out := new(KafkaConnectorSinkStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -918,6 +1334,11 @@ func (in *KafkaConnectorPluginStatus) DeepCopy() *KafkaConnectorPluginStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaConnectorSpec) DeepCopyInto(out *KafkaConnectorSpec) {
 	*out = *in
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(ResourceReference)
+		**out = **in
+	}
 	if in.AuthSecretRef != nil {
 		in, out := &in.AuthSecretRef, &out.AuthSecretRef
 		*out = new(AuthSecretReference)
@@ -930,6 +1351,21 @@ func (in *KafkaConnectorSpec) DeepCopyInto(out *KafkaConnectorSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.JDBCSink != nil {
+		in, out := &in.JDBCSink, &out.JDBCSink
+		*out = new(KafkaConnectorJDBCSink)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3Sink != nil {
+		in, out := &in.S3Sink, &out.S3Sink
+		*out = new(KafkaConnectorS3Sink)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpenSearchSink != nil {
+		in, out := &in.OpenSearchSink, &out.OpenSearchSink
+		*out = new(KafkaConnectorOpenSearchSink)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaConnectorSpec.
@@ -954,6 +1390,11 @@ func (in *KafkaConnectorStatus) DeepCopyInto(out *KafkaConnectorStatus) {
 	}
 	out.PluginStatus = in.PluginStatus
 	out.TasksStatus = in.TasksStatus
+	if in.Sink != nil {
+		in, out := &in.Sink, &out.Sink
+		*out = new(KafkaConnectorSinkStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaConnectorStatus.
@@ -1075,6 +1516,11 @@ func (in *KafkaSchemaList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaSchemaSpec) DeepCopyInto(out *KafkaSchemaSpec) {
 	*out = *in
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(ResourceReference)
+		**out = **in
+	}
 	if in.AuthSecretRef != nil {
 		in, out := &in.AuthSecretRef, &out.AuthSecretRef
 		*out = new(AuthSecretReference)
@@ -1123,7 +1569,12 @@ func (in *KafkaSpec) DeepCopyInto(out *KafkaSpec) {
 		*out = new(AuthSecretReference)
 		**out = **in
 	}
-	out.ConnInfoSecretTarget = in.ConnInfoSecretTarget
+	in.ConnInfoSecretTarget.DeepCopyInto(&out.ConnInfoSecretTarget)
+	if in.ConnInfoConfigMapTarget != nil {
+		in, out := &in.ConnInfoConfigMapTarget, &out.ConnInfoConfigMapTarget
+		*out = new(ConnInfoConfigMapTarget)
+		**out = **in
+	}
 	if in.Karapace != nil {
 		in, out := &in.Karapace, &out.Karapace
 		*out = new(bool)
@@ -1323,6 +1774,11 @@ func (in *KafkaTopicList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaTopicSpec) DeepCopyInto(out *KafkaTopicSpec) {
 	*out = *in
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(ResourceReference)
+		**out = **in
+	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make([]KafkaTopicTag, len(*in))
@@ -1334,6 +1790,11 @@ func (in *KafkaTopicSpec) DeepCopyInto(out *KafkaTopicSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DeleteProtectionIfNotEmpty != nil {
+		in, out := &in.DeleteProtectionIfNotEmpty, &out.DeleteProtectionIfNotEmpty
+		*out = new(bool)
+		**out = **in
+	}
 	if in.AuthSecretRef != nil {
 		in, out := &in.AuthSecretRef, &out.AuthSecretRef
 		*out = new(AuthSecretReference)
@@ -1388,6 +1849,22 @@ func (in *KafkaTopicTag) DeepCopy() *KafkaTopicTag {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceAllowedWindow) DeepCopyInto(out *MaintenanceAllowedWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceAllowedWindow.
+func (in *MaintenanceAllowedWindow) DeepCopy() *MaintenanceAllowedWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceAllowedWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MySQL) DeepCopyInto(out *MySQL) {
 	*out = *in
@@ -1456,7 +1933,12 @@ func (in *MySQLSpec) DeepCopyInto(out *MySQLSpec) {
 		*out = new(AuthSecretReference)
 		**out = **in
 	}
-	out.ConnInfoSecretTarget = in.ConnInfoSecretTarget
+	in.ConnInfoSecretTarget.DeepCopyInto(&out.ConnInfoSecretTarget)
+	if in.ConnInfoConfigMapTarget != nil {
+		in, out := &in.ConnInfoConfigMapTarget, &out.ConnInfoConfigMapTarget
+		*out = new(ConnInfoConfigMapTarget)
+		**out = **in
+	}
 	if in.UserConfig != nil {
 		in, out := &in.UserConfig, &out.UserConfig
 		*out = new(mysql.MysqlUserConfig)
@@ -1542,7 +2024,12 @@ func (in *OpenSearchSpec) DeepCopyInto(out *OpenSearchSpec) {
 		*out = new(AuthSecretReference)
 		**out = **in
 	}
-	out.ConnInfoSecretTarget = in.ConnInfoSecretTarget
+	in.ConnInfoSecretTarget.DeepCopyInto(&out.ConnInfoSecretTarget)
+	if in.ConnInfoConfigMapTarget != nil {
+		in, out := &in.ConnInfoConfigMapTarget, &out.ConnInfoConfigMapTarget
+		*out = new(ConnInfoConfigMapTarget)
+		**out = **in
+	}
 	if in.UserConfig != nil {
 		in, out := &in.UserConfig, &out.UserConfig
 		*out = new(opensearch.OpensearchUserConfig)
@@ -1628,7 +2115,12 @@ func (in *PostgreSQLSpec) DeepCopyInto(out *PostgreSQLSpec) {
 		*out = new(AuthSecretReference)
 		**out = **in
 	}
-	out.ConnInfoSecretTarget = in.ConnInfoSecretTarget
+	in.ConnInfoSecretTarget.DeepCopyInto(&out.ConnInfoSecretTarget)
+	if in.ConnInfoConfigMapTarget != nil {
+		in, out := &in.ConnInfoConfigMapTarget, &out.ConnInfoConfigMapTarget
+		*out = new(ConnInfoConfigMapTarget)
+		**out = **in
+	}
 	if in.UserConfig != nil {
 		in, out := &in.UserConfig, &out.UserConfig
 		*out = new(pg.PgUserConfig)
@@ -1718,7 +2210,7 @@ func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	out.ConnInfoSecretTarget = in.ConnInfoSecretTarget
+	in.ConnInfoSecretTarget.DeepCopyInto(&out.ConnInfoSecretTarget)
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make(map[string]string, len(*in))
@@ -1827,6 +2319,11 @@ func (in *ProjectVPCList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectVPCSpec) DeepCopyInto(out *ProjectVPCSpec) {
 	*out = *in
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(ResourceReference)
+		**out = **in
+	}
 	if in.AuthSecretRef != nil {
 		in, out := &in.AuthSecretRef, &out.AuthSecretRef
 		*out = new(AuthSecretReference)
@@ -1934,7 +2431,12 @@ func (in *RedisSpec) DeepCopyInto(out *RedisSpec) {
 		*out = new(AuthSecretReference)
 		**out = **in
 	}
-	out.ConnInfoSecretTarget = in.ConnInfoSecretTarget
+	in.ConnInfoSecretTarget.DeepCopyInto(&out.ConnInfoSecretTarget)
+	if in.ConnInfoConfigMapTarget != nil {
+		in, out := &in.ConnInfoConfigMapTarget, &out.ConnInfoConfigMapTarget
+		*out = new(ConnInfoConfigMapTarget)
+		**out = **in
+	}
 	if in.UserConfig != nil {
 		in, out := &in.UserConfig, &out.UserConfig
 		*out = new(redis.RedisUserConfig)
@@ -1984,19 +2486,79 @@ func (in *ResourceReferenceObject) DeepCopy() *ResourceReferenceObject {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLCertificateSecretReference) DeepCopyInto(out *SAMLCertificateSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLCertificateSecretReference.
+func (in *SAMLCertificateSecretReference) DeepCopy() *SAMLCertificateSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SAMLCertificateSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLFieldMapping) DeepCopyInto(out *SAMLFieldMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLFieldMapping.
+func (in *SAMLFieldMapping) DeepCopy() *SAMLFieldMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(SAMLFieldMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyEncoding) DeepCopyInto(out *SecretKeyEncoding) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyEncoding.
+func (in *SecretKeyEncoding) DeepCopy() *SecretKeyEncoding {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyEncoding)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceCommonSpec) DeepCopyInto(out *ServiceCommonSpec) {
 	*out = *in
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(ResourceReference)
+		**out = **in
+	}
 	if in.ProjectVPCRef != nil {
 		in, out := &in.ProjectVPCRef, &out.ProjectVPCRef
 		*out = new(ResourceReference)
 		**out = **in
 	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(ServiceMaintenance)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TerminationProtection != nil {
 		in, out := &in.TerminationProtection, &out.TerminationProtection
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ClearTerminationProtectionOnDelete != nil {
+		in, out := &in.ClearTerminationProtectionOnDelete, &out.ClearTerminationProtectionOnDelete
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make(map[string]string, len(*in))
@@ -2004,6 +2566,26 @@ func (in *ServiceCommonSpec) DeepCopyInto(out *ServiceCommonSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.PartialUserConfigUpdate != nil {
+		in, out := &in.PartialUserConfigUpdate, &out.PartialUserConfigUpdate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UnsetRemovedUserConfig != nil {
+		in, out := &in.UnsetRemovedUserConfig, &out.UnsetRemovedUserConfig
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Powered != nil {
+		in, out := &in.Powered, &out.Powered
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ServiceSnapshot != nil {
+		in, out := &in.ServiceSnapshot, &out.ServiceSnapshot
+		*out = new(bool)
+		**out = **in
+	}
 	if in.ServiceIntegrations != nil {
 		in, out := &in.ServiceIntegrations, &out.ServiceIntegrations
 		*out = make([]*ServiceIntegrationItem, len(*in))
@@ -2015,6 +2597,16 @@ func (in *ServiceCommonSpec) DeepCopyInto(out *ServiceCommonSpec) {
 			}
 		}
 	}
+	if in.IPFilterConfigMapRef != nil {
+		in, out := &in.IPFilterConfigMapRef, &out.IPFilterConfigMapRef
+		*out = new(IPFilterConfigMapReference)
+		**out = **in
+	}
+	if in.ForkFrom != nil {
+		in, out := &in.ForkFrom, &out.ForkFrom
+		*out = new(ServiceForkSource)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceCommonSpec.
@@ -2027,6 +2619,82 @@ func (in *ServiceCommonSpec) DeepCopy() *ServiceCommonSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceConnInfoSecretTarget) DeepCopyInto(out *ServiceConnInfoSecretTarget) {
+	*out = *in
+	in.ConnInfoSecretTarget.DeepCopyInto(&out.ConnInfoSecretTarget)
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PrometheusScrapeConfig != nil {
+		in, out := &in.PrometheusScrapeConfig, &out.PrometheusScrapeConfig
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceConnInfoSecretTarget.
+func (in *ServiceConnInfoSecretTarget) DeepCopy() *ServiceConnInfoSecretTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceConnInfoSecretTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceConnectionPool) DeepCopyInto(out *ServiceConnectionPool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceConnectionPool.
+func (in *ServiceConnectionPool) DeepCopy() *ServiceConnectionPool {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceConnectionPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceDryRunPlan) DeepCopyInto(out *ServiceDryRunPlan) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]DryRunChange, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceDryRunPlan.
+func (in *ServiceDryRunPlan) DeepCopy() *ServiceDryRunPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceDryRunPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceForkSource) DeepCopyInto(out *ServiceForkSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceForkSource.
+func (in *ServiceForkSource) DeepCopy() *ServiceForkSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceForkSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceIntegration) DeepCopyInto(out *ServiceIntegration) {
 	*out = *in
@@ -2104,6 +2772,21 @@ func (in *ServiceIntegrationList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceIntegrationSpec) DeepCopyInto(out *ServiceIntegrationSpec) {
 	*out = *in
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(ResourceReference)
+		**out = **in
+	}
+	if in.SourceServiceRef != nil {
+		in, out := &in.SourceServiceRef, &out.SourceServiceRef
+		*out = new(ServiceReference)
+		**out = **in
+	}
+	if in.DestinationServiceRef != nil {
+		in, out := &in.DestinationServiceRef, &out.DestinationServiceRef
+		*out = new(ServiceReference)
+		**out = **in
+	}
 	if in.DatadogUserConfig != nil {
 		in, out := &in.DatadogUserConfig, &out.DatadogUserConfig
 		*out = new(datadog.DatadogUserConfig)
@@ -2149,6 +2832,41 @@ func (in *ServiceIntegrationSpec) DeepCopyInto(out *ServiceIntegrationSpec) {
 		*out = new(external_aws_cloudwatch_metrics.ExternalAwsCloudwatchMetricsUserConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PrometheusUserConfig != nil {
+		in, out := &in.PrometheusUserConfig, &out.PrometheusUserConfig
+		*out = new(prometheus.PrometheusUserConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RsyslogUserConfig != nil {
+		in, out := &in.RsyslogUserConfig, &out.RsyslogUserConfig
+		*out = new(rsyslog.RsyslogUserConfig)
+		**out = **in
+	}
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(ServiceIntegrationTemplateReference)
+		**out = **in
+	}
+	if in.ExternalSchemaRegistry != nil {
+		in, out := &in.ExternalSchemaRegistry, &out.ExternalSchemaRegistry
+		*out = new(ExternalSchemaRegistryEndpoint)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatadogEndpoint != nil {
+		in, out := &in.DatadogEndpoint, &out.DatadogEndpoint
+		*out = new(DatadogEndpoint)
+		**out = **in
+	}
+	if in.InactiveThreshold != nil {
+		in, out := &in.InactiveThreshold, &out.InactiveThreshold
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SkipPreconditions != nil {
+		in, out := &in.SkipPreconditions, &out.SkipPreconditions
+		*out = new(bool)
+		**out = **in
+	}
 	if in.AuthSecretRef != nil {
 		in, out := &in.AuthSecretRef, &out.AuthSecretRef
 		*out = new(AuthSecretReference)
@@ -2176,6 +2894,11 @@ func (in *ServiceIntegrationStatus) DeepCopyInto(out *ServiceIntegrationStatus)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceIntegrationStatus.
@@ -2188,6 +2911,100 @@ func (in *ServiceIntegrationStatus) DeepCopy() *ServiceIntegrationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceIntegrationTemplateReference) DeepCopyInto(out *ServiceIntegrationTemplateReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceIntegrationTemplateReference.
+func (in *ServiceIntegrationTemplateReference) DeepCopy() *ServiceIntegrationTemplateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceIntegrationTemplateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMaintenance) DeepCopyInto(out *ServiceMaintenance) {
+	*out = *in
+	if in.AutoApply != nil {
+		in, out := &in.AutoApply, &out.AutoApply
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowedWindows != nil {
+		in, out := &in.AllowedWindows, &out.AllowedWindows
+		*out = make([]MaintenanceAllowedWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMaintenance.
+func (in *ServiceMaintenance) DeepCopy() *ServiceMaintenance {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMaintenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMaintenanceStatus) DeepCopyInto(out *ServiceMaintenanceStatus) {
+	*out = *in
+	if in.NextWindow != nil {
+		in, out := &in.NextWindow, &out.NextWindow
+		*out = (*in).DeepCopy()
+	}
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMaintenanceStatus.
+func (in *ServiceMaintenanceStatus) DeepCopy() *ServiceMaintenanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMaintenanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServicePlanDetails) DeepCopyInto(out *ServicePlanDetails) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServicePlanDetails.
+func (in *ServicePlanDetails) DeepCopy() *ServicePlanDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(ServicePlanDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceReference) DeepCopyInto(out *ServiceReference) {
+	*out = *in
+	out.ResourceReference = in.ResourceReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceReference.
+func (in *ServiceReference) DeepCopy() *ServiceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceStatus) DeepCopyInto(out *ServiceStatus) {
 	*out = *in
@@ -2198,6 +3015,31 @@ func (in *ServiceStatus) DeepCopyInto(out *ServiceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ServiceSnapshot != nil {
+		in, out := &in.ServiceSnapshot, &out.ServiceSnapshot
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DryRunPlan != nil {
+		in, out := &in.DryRunPlan, &out.DryRunPlan
+		*out = new(ServiceDryRunPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PlanDetails != nil {
+		in, out := &in.PlanDetails, &out.PlanDetails
+		*out = new(ServicePlanDetails)
+		**out = **in
+	}
+	if in.ConnectionPools != nil {
+		in, out := &in.ConnectionPools, &out.ConnectionPools
+		*out = make([]ServiceConnectionPool, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(ServiceMaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceStatus.
@@ -2272,7 +3114,12 @@ func (in *ServiceUserList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceUserSpec) DeepCopyInto(out *ServiceUserSpec) {
 	*out = *in
-	out.ConnInfoSecretTarget = in.ConnInfoSecretTarget
+	if in.ProjectRef != nil {
+		in, out := &in.ProjectRef, &out.ProjectRef
+		*out = new(ResourceReference)
+		**out = **in
+	}
+	in.ConnInfoSecretTarget.DeepCopyInto(&out.ConnInfoSecretTarget)
 	if in.AuthSecretRef != nil {
 		in, out := &in.AuthSecretRef, &out.AuthSecretRef
 		*out = new(AuthSecretReference)
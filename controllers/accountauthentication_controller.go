@@ -0,0 +1,241 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/aiven/aiven-go-client"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+// AccountAuthenticationReconciler reconciles a AccountAuthentication object
+type AccountAuthenticationReconciler struct {
+	Controller
+}
+
+// AccountAuthenticationHandler reads the SAML certificate from the secret
+type AccountAuthenticationHandler struct {
+	k8s client.Client
+}
+
+// +kubebuilder:rbac:groups=aiven.io,resources=accountauthentications,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=aiven.io,resources=accountauthentications/status,verbs=get;update;patch
+
+func (r *AccountAuthenticationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return r.reconcileInstance(ctx, req, AccountAuthenticationHandler{k8s: r.Client}, &v1alpha1.AccountAuthentication{})
+}
+
+func (r *AccountAuthenticationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.AccountAuthentication{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.authenticationsForSecret)).
+		Complete(r)
+}
+
+// authenticationsForSecret returns authentication methods which read the SAML certificate from the secret,
+// so the rotated certificate is applied
+func (r *AccountAuthenticationReconciler) authenticationsForSecret(secret client.Object) []reconcile.Request {
+	list := &v1alpha1.AccountAuthenticationList{}
+	if err := r.List(context.Background(), list, client.InNamespace(secret.GetNamespace())); err != nil {
+		r.Log.Error(err, "unable to list account authentications")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, auth := range list.Items {
+		if auth.Spec.SAML != nil && auth.Spec.SAML.CertificateSecretRef.Name == secret.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&auth)})
+		}
+	}
+	return requests
+}
+
+func (h AccountAuthenticationHandler) createOrUpdate(avn AivenClient, i client.Object, _ []client.Object) error {
+	auth, err := h.convert(i)
+	if err != nil {
+		return err
+	}
+
+	if auth.Spec.SAML == nil {
+		return fmt.Errorf("saml is required for the %q type", auth.Spec.Type)
+	}
+
+	cert, err := h.getSAMLCertificate(auth)
+	if err != nil {
+		return err
+	}
+
+	// Methods are account-wide, so the method is looked up by the stored ID only,
+	// a same-named method of another namespace is never adopted
+	saml := auth.Spec.SAML
+	var rsp *aiven.AccountAuthenticationResponse
+	if auth.Status.ID == "" {
+		rsp, err = avn.AccountAuthentications().Create(auth.Spec.AccountID, aiven.AccountAuthenticationMethodCreate{
+			AuthenticationMethodName: auth.Name,
+			AuthenticationMethodType: auth.Spec.Type,
+			AutoJoinTeamID:           auth.Spec.AutoJoinTeamID,
+			SAMLCertificate:          cert,
+			SAMLDigestAlgorithm:      saml.DigestAlgorithm,
+			SAMLEntityID:             saml.EntityID,
+			SAMLFieldMapping:         samlFieldMapping(saml.FieldMapping),
+			SAMLIdpLoginAllowed:      saml.IdpLoginAllowed,
+			SAMLIdpURL:               saml.IdpURL,
+			SAMLSignatureAlgorithm:   saml.SignatureAlgorithm,
+			SAMLVariant:              saml.Variant,
+		})
+	} else {
+		rsp, err = avn.AccountAuthentications().Update(auth.Spec.AccountID, auth.Status.ID, aiven.AccountAuthenticationMethodUpdate{
+			AuthenticationMethodName: auth.Name,
+			AutoJoinTeamID:           auth.Spec.AutoJoinTeamID,
+			SAMLCertificate:          cert,
+			SAMLDigestAlgorithm:      saml.DigestAlgorithm,
+			SAMLEntity:               saml.EntityID,
+			SAMLFieldMapping:         samlFieldMapping(saml.FieldMapping),
+			SAMLIdpLoginAllowed:      saml.IdpLoginAllowed,
+			SAMLIdpURL:               saml.IdpURL,
+			SAMLSignatureAlgorithm:   saml.SignatureAlgorithm,
+			SAMLVariant:              saml.Variant,
+		})
+		if isNotChangedError(err) {
+			rsp, err = avn.AccountAuthentications().Get(auth.Spec.AccountID, auth.Status.ID)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	setAccountAuthenticationStatus(auth, &rsp.AuthenticationMethod)
+
+	meta.SetStatusCondition(&auth.Status.Conditions,
+		getInitializedCondition("Created",
+			"Instance was created or update on Aiven side"))
+
+	meta.SetStatusCondition(&auth.Status.Conditions,
+		getRunningCondition(metav1.ConditionUnknown, "Created",
+			"Instance was created or update on Aiven side, status remains unknown"))
+
+	metav1.SetMetaDataAnnotation(&auth.ObjectMeta,
+		processedGenerationAnnotation, strconv.FormatInt(auth.GetGeneration(), formatIntBaseDecimal))
+
+	metav1.SetMetaDataAnnotation(&auth.ObjectMeta, samlCertificateHashAnnotation, hashValue(cert))
+	return nil
+}
+
+func (h AccountAuthenticationHandler) delete(avn AivenClient, i client.Object) (bool, error) {
+	auth, err := h.convert(i)
+	if err != nil {
+		return false, err
+	}
+
+	if auth.Status.ID == "" {
+		return true, nil
+	}
+
+	err = avn.AccountAuthentications().Delete(auth.Spec.AccountID, auth.Status.ID)
+	if err != nil && !aiven.IsNotFound(err) {
+		return false, err
+	}
+	return true, nil
+}
+
+func (h AccountAuthenticationHandler) get(avn AivenClient, i client.Object) (*corev1.Secret, error) {
+	auth, err := h.convert(i)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := avn.AccountAuthentications().Get(auth.Spec.AccountID, auth.Status.ID)
+	if err != nil {
+		return nil, err
+	}
+	setAccountAuthenticationStatus(auth, &rsp.AuthenticationMethod)
+
+	meta.SetStatusCondition(&auth.Status.Conditions,
+		getRunningCondition(metav1.ConditionTrue, "CheckRunning",
+			"Instance is running on Aiven side"))
+
+	metav1.SetMetaDataAnnotation(&auth.ObjectMeta, instanceIsRunningAnnotation, "true")
+	return nil, nil
+}
+
+// isOutdated returns true if the certificate has changed in the secret, the generation doesn't change then
+func (h AccountAuthenticationHandler) isOutdated(i client.Object) (bool, error) {
+	auth, err := h.convert(i)
+	if err != nil {
+		return false, err
+	}
+	if auth.Spec.SAML == nil {
+		return false, nil
+	}
+
+	cert, err := h.getSAMLCertificate(auth)
+	if err != nil {
+		return false, err
+	}
+	return auth.GetAnnotations()[samlCertificateHashAnnotation] != hashValue(cert), nil
+}
+
+func (h AccountAuthenticationHandler) checkPreconditions(_ AivenClient, _ client.Object) (bool, error) {
+	return true, nil
+}
+
+func (h AccountAuthenticationHandler) convert(i client.Object) (*v1alpha1.AccountAuthentication, error) {
+	auth, ok := i.(*v1alpha1.AccountAuthentication)
+	if !ok {
+		return nil, fmt.Errorf("cannot convert object to AccountAuthentication")
+	}
+
+	return auth, nil
+}
+
+// getSAMLCertificate returns the identity provider certificate from the secret
+func (h AccountAuthenticationHandler) getSAMLCertificate(auth *v1alpha1.AccountAuthentication) (string, error) {
+	ref := auth.Spec.SAML.CertificateSecretRef
+	secret := &corev1.Secret{}
+	err := h.k8s.Get(context.Background(), types.NamespacedName{Namespace: auth.Namespace, Name: ref.Name}, secret)
+	if err != nil {
+		return "", fmt.Errorf("cannot get saml certificate secret: %w", err)
+	}
+
+	cert := string(secret.Data[ref.Key])
+	if cert == "" {
+		return "", fmt.Errorf("saml certificate secret %q has no %q key", ref.Name, ref.Key)
+	}
+	return cert, nil
+}
+
+// setAccountAuthenticationStatus surfaces the method state, the enabled flag is managed in Aiven Console
+func setAccountAuthenticationStatus(auth *v1alpha1.AccountAuthentication, m *aiven.AccountAuthenticationMethod) {
+	auth.Status.ID = m.AuthenticationMethodID
+	auth.Status.State = m.State
+	auth.Status.Enabled = m.AuthenticationMethodEnabled
+	auth.Status.SAMLAcsURL = m.SAMLAcsURL
+	auth.Status.SAMLMetadataURL = m.SAMLMetadataURL
+}
+
+func samlFieldMapping(m *v1alpha1.SAMLFieldMapping) *aiven.SAMLFieldMapping {
+	if m == nil {
+		return nil
+	}
+	return &aiven.SAMLFieldMapping{
+		Email:     m.Email,
+		FirstName: m.FirstName,
+		Identity:  m.Identity,
+		LastName:  m.LastName,
+		RealName:  m.RealName,
+	}
+}
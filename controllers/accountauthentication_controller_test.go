@@ -0,0 +1,107 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aiven/aiven-go-client"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+func Test_accountAuthenticationCreateOrUpdate(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "saml", Namespace: "default"},
+		Data:       map[string][]byte{"cert": []byte("PEM")},
+	}
+	h := AccountAuthenticationHandler{k8s: fake.NewClientBuilder().WithObjects(secret).Build()}
+	auth := &v1alpha1.AccountAuthentication{
+		ObjectMeta: metav1.ObjectMeta{Name: "sso", Namespace: "default"},
+		Spec: v1alpha1.AccountAuthenticationSpec{
+			AccountID: "account",
+			Type:      "saml",
+			SAML: &v1alpha1.AccountAuthenticationSAML{
+				EntityID:             "entity",
+				IdpURL:               "https://idp",
+				CertificateSecretRef: v1alpha1.SAMLCertificateSecretReference{Name: "saml", Key: "cert"},
+			},
+		},
+	}
+
+	var created, updated int
+	method := aiven.AccountAuthenticationMethod{AuthenticationMethodID: "am1", State: "active", SAMLAcsURL: "https://acs"}
+	// Methods are not listed, a same-named method of another namespace must not be adopted
+	avn := &mockAivenClient{accountAuthentications: &mockAccountAuthentications{
+		CreateFunc: func(accountID string, req aiven.AccountAuthenticationMethodCreate) (*aiven.AccountAuthenticationResponse, error) {
+			created++
+			if req.SAMLCertificate != "PEM" || req.AuthenticationMethodName != "sso" {
+				t.Errorf("unexpected create request %+v", req)
+			}
+			return &aiven.AccountAuthenticationResponse{AuthenticationMethod: method}, nil
+		},
+		UpdateFunc: func(accountID, authID string, req aiven.AccountAuthenticationMethodUpdate) (*aiven.AccountAuthenticationResponse, error) {
+			updated++
+			if authID != "am1" || req.SAMLCertificate != "PEM" {
+				t.Errorf("unexpected update request %s %+v", authID, req)
+			}
+			return &aiven.AccountAuthenticationResponse{AuthenticationMethod: method}, nil
+		},
+	}}
+
+	for i := 0; i < 2; i++ {
+		if err := h.createOrUpdate(avn, auth, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if created != 1 || updated != 1 {
+		t.Errorf("created %d, updated %d times, want once each", created, updated)
+	}
+	if auth.Status.ID != "am1" || auth.Status.SAMLAcsURL != "https://acs" {
+		t.Errorf("unexpected status %+v", auth.Status)
+	}
+	if auth.Annotations[samlCertificateHashAnnotation] != hashValue("PEM") {
+		t.Error("certificate hash annotation is not set")
+	}
+
+	// The certificate is rotated in the secret
+	if outdated, err := h.isOutdated(auth); err != nil || outdated {
+		t.Errorf("isOutdated() = %t, %v, want up to date", outdated, err)
+	}
+	secret.Data["cert"] = []byte("NEW")
+	if err := h.k8s.Update(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+	if outdated, err := h.isOutdated(auth); err != nil || !outdated {
+		t.Errorf("isOutdated() = %t, %v, want outdated after the certificate rotation", outdated, err)
+	}
+}
+
+func Test_authenticationsForSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	newAuth := func(namespace, name, secret string) *v1alpha1.AccountAuthentication {
+		auth := &v1alpha1.AccountAuthentication{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		auth.Spec.SAML = &v1alpha1.AccountAuthenticationSAML{CertificateSecretRef: v1alpha1.SAMLCertificateSecretReference{Name: secret, Key: "cert"}}
+		return auth
+	}
+	k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newAuth("default", "sso", "saml"),
+		newAuth("default", "other", "other"),
+		newAuth("team", "sso", "saml"),
+	).Build()
+	r := &AccountAuthenticationReconciler{Controller: Controller{Client: k8s, Log: logr.Discard()}}
+
+	got := r.authenticationsForSecret(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "saml", Namespace: "default"}})
+	if len(got) != 1 || got[0].Namespace != "default" || got[0].Name != "sso" {
+		t.Errorf("authenticationsForSecret() = %v, want default/sso only", got)
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/aiven/aiven-go-client"
+)
+
+// AivenClient is the part of Aiven API used by the controllers.
+// It is implemented by the aiven-go-client wrapper and by mocks in tests
+type AivenClient interface {
+	Services() ServicesAPI
+	ServiceIntegrations() ServiceIntegrationsAPI
+	ServiceIntegrationEndpoints() ServiceIntegrationEndpointsAPI
+	ServiceUsers() ServiceUsersAPI
+	ServiceTypes() ServiceTypesAPI
+	ServiceTags() ServiceTagsAPI
+	Databases() DatabasesAPI
+	ConnectionPools() ConnectionPoolsAPI
+	KafkaTopics() KafkaTopicsAPI
+	KafkaACLs() KafkaACLsAPI
+	KafkaSubjectSchemas() KafkaSubjectSchemasAPI
+	KafkaConnectors() KafkaConnectorsAPI
+	ClickhouseUser() ClickhouseUserAPI
+	Projects() ProjectsAPI
+	VPCs() VPCsAPI
+	CA() CAAPI
+	Cards() CardsAPI
+	AccountAuthentications() AccountAuthenticationsAPI
+
+	// RawGet calls API path which is not covered by aiven-go-client and decodes the response into v
+	RawGet(path string, v interface{}) error
+
+	// RawPut sends the body to API path which is not covered by aiven-go-client, the response is decoded into v if not nil
+	RawPut(path string, body, v interface{}) error
+}
+
+type ServicesAPI interface {
+	Get(project, service string) (*aiven.Service, error)
+	Create(project string, req aiven.CreateServiceRequest) (*aiven.Service, error)
+	Update(project, service string, req aiven.UpdateServiceRequest) (*aiven.Service, error)
+	Delete(project, service string) error
+}
+
+type ServiceIntegrationsAPI interface {
+	List(project, service string) ([]*aiven.ServiceIntegration, error)
+	Get(project, integrationID string) (*aiven.ServiceIntegration, error)
+	Create(project string, req aiven.CreateServiceIntegrationRequest) (*aiven.ServiceIntegration, error)
+	Update(project, integrationID string, req aiven.UpdateServiceIntegrationRequest) (*aiven.ServiceIntegration, error)
+	Delete(project, integrationID string) error
+}
+
+type ServiceIntegrationEndpointsAPI interface {
+	List(project string) ([]*aiven.ServiceIntegrationEndpoint, error)
+	Get(project, endpointID string) (*aiven.ServiceIntegrationEndpoint, error)
+	Create(project string, req aiven.CreateServiceIntegrationEndpointRequest) (*aiven.ServiceIntegrationEndpoint, error)
+	Update(project, endpointID string, req aiven.UpdateServiceIntegrationEndpointRequest) (*aiven.ServiceIntegrationEndpoint, error)
+	Delete(project, endpointID string) error
+}
+
+type ServiceUsersAPI interface {
+	Get(project, service, username string) (*aiven.ServiceUser, error)
+	Create(project, service string, req aiven.CreateServiceUserRequest) (*aiven.ServiceUser, error)
+	Delete(project, service, username string) error
+}
+
+type ServiceTypesAPI interface {
+	GetPlanPricing(project, serviceType, servicePlan, cloudName string) (*aiven.GetServicePlanPricingResponse, error)
+}
+
+type ServiceTagsAPI interface {
+	Get(project, service string) (*aiven.ServiceTagsResponse, error)
+	Set(project, service string, req aiven.ServiceTagsRequest) (*aiven.ServiceTagsResponse, error)
+}
+
+type DatabasesAPI interface {
+	Get(project, service, database string) (*aiven.Database, error)
+	Create(project, service string, req aiven.CreateDatabaseRequest) (*aiven.Database, error)
+	Delete(project, service, database string) error
+}
+
+type ConnectionPoolsAPI interface {
+	Get(project, service, pool string) (*aiven.ConnectionPool, error)
+	Create(project, service string, req aiven.CreateConnectionPoolRequest) (*aiven.ConnectionPool, error)
+	Update(project, service, pool string, req aiven.UpdateConnectionPoolRequest) (*aiven.ConnectionPool, error)
+	Delete(project, service, pool string) error
+}
+
+type KafkaTopicsAPI interface {
+	Get(project, service, topic string) (*aiven.KafkaTopic, error)
+	Create(project, service string, req aiven.CreateKafkaTopicRequest) error
+	Update(project, service, topic string, req aiven.UpdateKafkaTopicRequest) error
+	Delete(project, service, topic string) error
+}
+
+type KafkaACLsAPI interface {
+	List(project, service string) ([]*aiven.KafkaACL, error)
+	Get(project, service, aclID string) (*aiven.KafkaACL, error)
+	Create(project, service string, req aiven.CreateKafkaACLRequest) (*aiven.KafkaACL, error)
+	Delete(project, service, aclID string) error
+}
+
+type KafkaSubjectSchemasAPI interface {
+	GetVersions(project, service, name string) (*aiven.KafkaSchemaSubjectVersionsResponse, error)
+	Add(project, service, name string, subject aiven.KafkaSchemaSubject) (*aiven.KafkaSchemaSubjectResponse, error)
+	UpdateConfiguration(project, service, name, compatibility string) (*aiven.KafkaSchemaConfigUpdateResponse, error)
+	Delete(project, service, name string, versions ...int) error
+}
+
+type KafkaConnectorsAPI interface {
+	Status(project, service, name string) (*aiven.KafkaConnectorStatusResponse, error)
+	GetByName(project, service, name string) (*aiven.KafkaConnector, error)
+	Create(project, service string, c aiven.KafkaConnectorConfig) error
+	Update(project, service, name string, c aiven.KafkaConnectorConfig) (*aiven.KafkaConnectorResponse, error)
+	Delete(project, service, name string) error
+}
+
+type ClickhouseUserAPI interface {
+	List(project, service string) (*aiven.ListClickhouseUserResponse, error)
+	Create(project, service, name string) (*aiven.ClickhouseUserResponse, error)
+	ResetPassword(project, service, uuid, password string) (string, error)
+	Delete(project, service, uuid string) error
+}
+
+type ProjectsAPI interface {
+	Get(project string) (*aiven.Project, error)
+	Create(req aiven.CreateProjectRequest) (*aiven.Project, error)
+	Update(project string, req aiven.UpdateProjectRequest) (*aiven.Project, error)
+	Delete(project string) error
+	ServiceTypes(project string) (map[string]aiven.ServiceType, error)
+}
+
+type VPCsAPI interface {
+	List(project string) ([]*aiven.VPC, error)
+	Create(project string, req aiven.CreateVPCRequest) (*aiven.VPC, error)
+	Delete(project, vpcID string) error
+}
+
+type CAAPI interface {
+	Get(project string) (string, error)
+}
+
+type CardsAPI interface {
+	Get(cardID string) (*aiven.Card, error)
+}
+
+type AccountAuthenticationsAPI interface {
+	List(accountID string) (*aiven.AccountAuthenticationListResponse, error)
+	Get(accountID, authID string) (*aiven.AccountAuthenticationResponse, error)
+	Create(accountID string, req aiven.AccountAuthenticationMethodCreate) (*aiven.AccountAuthenticationResponse, error)
+	Update(accountID, authID string, req aiven.AccountAuthenticationMethodUpdate) (*aiven.AccountAuthenticationResponse, error)
+	Delete(accountID, authID string) error
+}
+
+// goClient implements AivenClient with aiven-go-client
+type goClient struct {
+	c *aiven.Client
+}
+
+// newGoClient wraps aiven-go-client
+func newGoClient(c *aiven.Client) AivenClient {
+	return &goClient{c: c}
+}
+
+func (g *goClient) Services() ServicesAPI                       { return g.c.Services }
+func (g *goClient) ServiceIntegrations() ServiceIntegrationsAPI { return g.c.ServiceIntegrations }
+func (g *goClient) ServiceIntegrationEndpoints() ServiceIntegrationEndpointsAPI {
+	return g.c.ServiceIntegrationEndpoints
+}
+func (g *goClient) ServiceUsers() ServiceUsersAPI               { return g.c.ServiceUsers }
+func (g *goClient) ServiceTypes() ServiceTypesAPI               { return g.c.ServiceTypes }
+func (g *goClient) ServiceTags() ServiceTagsAPI                 { return g.c.ServiceTags }
+func (g *goClient) Databases() DatabasesAPI                     { return g.c.Databases }
+func (g *goClient) ConnectionPools() ConnectionPoolsAPI         { return g.c.ConnectionPools }
+func (g *goClient) KafkaTopics() KafkaTopicsAPI                 { return g.c.KafkaTopics }
+func (g *goClient) KafkaACLs() KafkaACLsAPI                     { return g.c.KafkaACLs }
+func (g *goClient) KafkaSubjectSchemas() KafkaSubjectSchemasAPI { return g.c.KafkaSubjectSchemas }
+func (g *goClient) KafkaConnectors() KafkaConnectorsAPI         { return g.c.KafkaConnectors }
+func (g *goClient) ClickhouseUser() ClickhouseUserAPI           { return g.c.ClickhouseUser }
+func (g *goClient) Projects() ProjectsAPI                       { return g.c.Projects }
+func (g *goClient) VPCs() VPCsAPI                               { return g.c.VPCs }
+func (g *goClient) CA() CAAPI                                   { return g.c.CA }
+func (g *goClient) Cards() CardsAPI                             { return g.c.CardsHandler }
+func (g *goClient) AccountAuthentications() AccountAuthenticationsAPI {
+	return g.c.AccountAuthentications
+}
+
+// RawGet calls Aiven API path with the client credentials and decodes the response into v
+func (g *goClient) RawGet(path string, v interface{}) error {
+	return g.rawRequest(http.MethodGet, path, nil, v)
+}
+
+// RawPut sends the body as JSON to Aiven API path with the client credentials
+func (g *goClient) RawPut(path string, body, v interface{}) error {
+	return g.rawRequest(http.MethodPut, path, body, v)
+}
+
+func (g *goClient) rawRequest(method, path string, body, v interface{}) error {
+	apiURL := "https://api.aiven.io"
+	if u, ok := os.LookupEnv("AIVEN_WEB_URL"); ok {
+		apiURL = u
+	}
+
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, apiURL+path, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", g.c.UserAgent)
+	req.Header.Set("Authorization", "aivenv1 "+g.c.APIKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	rsp, err := g.c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	b, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return err
+	}
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		return aiven.Error{Message: string(b), Status: rsp.StatusCode}
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(b, v)
+}
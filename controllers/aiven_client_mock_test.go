@@ -0,0 +1,458 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/aiven/aiven-go-client"
+)
+
+// mockAivenClient implements AivenClient with the given APIs.
+// Methods without a function set panic, so unexpected calls fail the test
+type mockAivenClient struct {
+	services                    ServicesAPI
+	serviceIntegrations         ServiceIntegrationsAPI
+	serviceIntegrationEndpoints ServiceIntegrationEndpointsAPI
+	serviceUsers                ServiceUsersAPI
+	serviceTypes                ServiceTypesAPI
+	serviceTags                 ServiceTagsAPI
+	databases                   DatabasesAPI
+	connectionPools             ConnectionPoolsAPI
+	kafkaTopics                 KafkaTopicsAPI
+	kafkaACLs                   KafkaACLsAPI
+	kafkaSubjectSchemas         KafkaSubjectSchemasAPI
+	kafkaConnectors             KafkaConnectorsAPI
+	clickhouseUser              ClickhouseUserAPI
+	projects                    ProjectsAPI
+	vpcs                        VPCsAPI
+	ca                          CAAPI
+	cards                       CardsAPI
+	accountAuthentications      AccountAuthenticationsAPI
+	rawGet                      func(path string, v interface{}) error
+	rawPut                      func(path string, body, v interface{}) error
+}
+
+func (m *mockAivenClient) Services() ServicesAPI                       { return m.services }
+func (m *mockAivenClient) ServiceIntegrations() ServiceIntegrationsAPI { return m.serviceIntegrations }
+func (m *mockAivenClient) ServiceIntegrationEndpoints() ServiceIntegrationEndpointsAPI {
+	return m.serviceIntegrationEndpoints
+}
+func (m *mockAivenClient) ServiceUsers() ServiceUsersAPI               { return m.serviceUsers }
+func (m *mockAivenClient) ServiceTypes() ServiceTypesAPI               { return m.serviceTypes }
+func (m *mockAivenClient) ServiceTags() ServiceTagsAPI                 { return m.serviceTags }
+func (m *mockAivenClient) Databases() DatabasesAPI                     { return m.databases }
+func (m *mockAivenClient) ConnectionPools() ConnectionPoolsAPI         { return m.connectionPools }
+func (m *mockAivenClient) KafkaTopics() KafkaTopicsAPI                 { return m.kafkaTopics }
+func (m *mockAivenClient) KafkaACLs() KafkaACLsAPI                     { return m.kafkaACLs }
+func (m *mockAivenClient) KafkaSubjectSchemas() KafkaSubjectSchemasAPI { return m.kafkaSubjectSchemas }
+func (m *mockAivenClient) KafkaConnectors() KafkaConnectorsAPI         { return m.kafkaConnectors }
+func (m *mockAivenClient) ClickhouseUser() ClickhouseUserAPI           { return m.clickhouseUser }
+func (m *mockAivenClient) Projects() ProjectsAPI                       { return m.projects }
+func (m *mockAivenClient) VPCs() VPCsAPI                               { return m.vpcs }
+func (m *mockAivenClient) CA() CAAPI                                   { return m.ca }
+func (m *mockAivenClient) Cards() CardsAPI                             { return m.cards }
+func (m *mockAivenClient) AccountAuthentications() AccountAuthenticationsAPI {
+	return m.accountAuthentications
+}
+func (m *mockAivenClient) RawGet(path string, v interface{}) error { return m.rawGet(path, v) }
+
+// rawGetService serves the raw service request with the services mock, other paths return nothing
+func rawGetService(services *mockServices) func(path string, v interface{}) error {
+	return func(path string, v interface{}) error {
+		parts := strings.Split(path, "/")
+		if len(parts) != 6 || parts[2] != "project" || parts[4] != "service" {
+			return nil
+		}
+		s, err := services.Get(parts[3], parts[5])
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(map[string]interface{}{"service": s})
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(b, v)
+	}
+}
+
+func (m *mockAivenClient) RawPut(path string, body, v interface{}) error {
+	return m.rawPut(path, body, v)
+}
+
+type mockServices struct {
+	GetFunc    func(project, service string) (*aiven.Service, error)
+	CreateFunc func(project string, req aiven.CreateServiceRequest) (*aiven.Service, error)
+	UpdateFunc func(project, service string, req aiven.UpdateServiceRequest) (*aiven.Service, error)
+	DeleteFunc func(project, service string) error
+}
+
+func (m *mockServices) Get(project, service string) (*aiven.Service, error) {
+	return m.GetFunc(project, service)
+}
+
+func (m *mockServices) Create(project string, req aiven.CreateServiceRequest) (*aiven.Service, error) {
+	return m.CreateFunc(project, req)
+}
+
+func (m *mockServices) Update(project, service string, req aiven.UpdateServiceRequest) (*aiven.Service, error) {
+	return m.UpdateFunc(project, service, req)
+}
+
+func (m *mockServices) Delete(project, service string) error {
+	return m.DeleteFunc(project, service)
+}
+
+type mockServiceIntegrations struct {
+	ListFunc   func(project, service string) ([]*aiven.ServiceIntegration, error)
+	GetFunc    func(project, integrationID string) (*aiven.ServiceIntegration, error)
+	CreateFunc func(project string, req aiven.CreateServiceIntegrationRequest) (*aiven.ServiceIntegration, error)
+	UpdateFunc func(project, integrationID string, req aiven.UpdateServiceIntegrationRequest) (*aiven.ServiceIntegration, error)
+	DeleteFunc func(project, integrationID string) error
+}
+
+func (m *mockServiceIntegrations) List(project, service string) ([]*aiven.ServiceIntegration, error) {
+	return m.ListFunc(project, service)
+}
+
+func (m *mockServiceIntegrations) Get(project, integrationID string) (*aiven.ServiceIntegration, error) {
+	return m.GetFunc(project, integrationID)
+}
+
+func (m *mockServiceIntegrations) Create(project string, req aiven.CreateServiceIntegrationRequest) (*aiven.ServiceIntegration, error) {
+	return m.CreateFunc(project, req)
+}
+
+func (m *mockServiceIntegrations) Update(project, integrationID string, req aiven.UpdateServiceIntegrationRequest) (*aiven.ServiceIntegration, error) {
+	return m.UpdateFunc(project, integrationID, req)
+}
+
+func (m *mockServiceIntegrations) Delete(project, integrationID string) error {
+	return m.DeleteFunc(project, integrationID)
+}
+
+type mockServiceIntegrationEndpoints struct {
+	ListFunc   func(project string) ([]*aiven.ServiceIntegrationEndpoint, error)
+	GetFunc    func(project, endpointID string) (*aiven.ServiceIntegrationEndpoint, error)
+	CreateFunc func(project string, req aiven.CreateServiceIntegrationEndpointRequest) (*aiven.ServiceIntegrationEndpoint, error)
+	UpdateFunc func(project, endpointID string, req aiven.UpdateServiceIntegrationEndpointRequest) (*aiven.ServiceIntegrationEndpoint, error)
+	DeleteFunc func(project, endpointID string) error
+}
+
+func (m *mockServiceIntegrationEndpoints) List(project string) ([]*aiven.ServiceIntegrationEndpoint, error) {
+	return m.ListFunc(project)
+}
+
+func (m *mockServiceIntegrationEndpoints) Get(project, endpointID string) (*aiven.ServiceIntegrationEndpoint, error) {
+	return m.GetFunc(project, endpointID)
+}
+
+func (m *mockServiceIntegrationEndpoints) Create(project string, req aiven.CreateServiceIntegrationEndpointRequest) (*aiven.ServiceIntegrationEndpoint, error) {
+	return m.CreateFunc(project, req)
+}
+
+func (m *mockServiceIntegrationEndpoints) Update(project, endpointID string, req aiven.UpdateServiceIntegrationEndpointRequest) (*aiven.ServiceIntegrationEndpoint, error) {
+	return m.UpdateFunc(project, endpointID, req)
+}
+
+func (m *mockServiceIntegrationEndpoints) Delete(project, endpointID string) error {
+	return m.DeleteFunc(project, endpointID)
+}
+
+type mockServiceUsers struct {
+	GetFunc    func(project, service, username string) (*aiven.ServiceUser, error)
+	CreateFunc func(project, service string, req aiven.CreateServiceUserRequest) (*aiven.ServiceUser, error)
+	DeleteFunc func(project, service, username string) error
+}
+
+func (m *mockServiceUsers) Get(project, service, username string) (*aiven.ServiceUser, error) {
+	return m.GetFunc(project, service, username)
+}
+
+func (m *mockServiceUsers) Create(project, service string, req aiven.CreateServiceUserRequest) (*aiven.ServiceUser, error) {
+	return m.CreateFunc(project, service, req)
+}
+
+func (m *mockServiceUsers) Delete(project, service, username string) error {
+	return m.DeleteFunc(project, service, username)
+}
+
+type mockServiceTypes struct {
+	GetPlanPricingFunc func(project, serviceType, servicePlan, cloudName string) (*aiven.GetServicePlanPricingResponse, error)
+}
+
+func (m *mockServiceTypes) GetPlanPricing(project, serviceType, servicePlan, cloudName string) (*aiven.GetServicePlanPricingResponse, error) {
+	return m.GetPlanPricingFunc(project, serviceType, servicePlan, cloudName)
+}
+
+type mockServiceTags struct {
+	GetFunc func(project, service string) (*aiven.ServiceTagsResponse, error)
+	SetFunc func(project, service string, req aiven.ServiceTagsRequest) (*aiven.ServiceTagsResponse, error)
+}
+
+func (m *mockServiceTags) Get(project, service string) (*aiven.ServiceTagsResponse, error) {
+	return m.GetFunc(project, service)
+}
+
+func (m *mockServiceTags) Set(project, service string, req aiven.ServiceTagsRequest) (*aiven.ServiceTagsResponse, error) {
+	return m.SetFunc(project, service, req)
+}
+
+type mockDatabases struct {
+	GetFunc    func(project, service, database string) (*aiven.Database, error)
+	CreateFunc func(project, service string, req aiven.CreateDatabaseRequest) (*aiven.Database, error)
+	DeleteFunc func(project, service, database string) error
+}
+
+func (m *mockDatabases) Get(project, service, database string) (*aiven.Database, error) {
+	return m.GetFunc(project, service, database)
+}
+
+func (m *mockDatabases) Create(project, service string, req aiven.CreateDatabaseRequest) (*aiven.Database, error) {
+	return m.CreateFunc(project, service, req)
+}
+
+func (m *mockDatabases) Delete(project, service, database string) error {
+	return m.DeleteFunc(project, service, database)
+}
+
+type mockConnectionPools struct {
+	GetFunc    func(project, service, pool string) (*aiven.ConnectionPool, error)
+	CreateFunc func(project, service string, req aiven.CreateConnectionPoolRequest) (*aiven.ConnectionPool, error)
+	UpdateFunc func(project, service, pool string, req aiven.UpdateConnectionPoolRequest) (*aiven.ConnectionPool, error)
+	DeleteFunc func(project, service, pool string) error
+}
+
+func (m *mockConnectionPools) Get(project, service, pool string) (*aiven.ConnectionPool, error) {
+	return m.GetFunc(project, service, pool)
+}
+
+func (m *mockConnectionPools) Create(project, service string, req aiven.CreateConnectionPoolRequest) (*aiven.ConnectionPool, error) {
+	return m.CreateFunc(project, service, req)
+}
+
+func (m *mockConnectionPools) Update(project, service, pool string, req aiven.UpdateConnectionPoolRequest) (*aiven.ConnectionPool, error) {
+	return m.UpdateFunc(project, service, pool, req)
+}
+
+func (m *mockConnectionPools) Delete(project, service, pool string) error {
+	return m.DeleteFunc(project, service, pool)
+}
+
+type mockKafkaTopics struct {
+	GetFunc    func(project, service, topic string) (*aiven.KafkaTopic, error)
+	CreateFunc func(project, service string, req aiven.CreateKafkaTopicRequest) error
+	UpdateFunc func(project, service, topic string, req aiven.UpdateKafkaTopicRequest) error
+	DeleteFunc func(project, service, topic string) error
+}
+
+func (m *mockKafkaTopics) Get(project, service, topic string) (*aiven.KafkaTopic, error) {
+	return m.GetFunc(project, service, topic)
+}
+
+func (m *mockKafkaTopics) Create(project, service string, req aiven.CreateKafkaTopicRequest) error {
+	return m.CreateFunc(project, service, req)
+}
+
+func (m *mockKafkaTopics) Update(project, service, topic string, req aiven.UpdateKafkaTopicRequest) error {
+	return m.UpdateFunc(project, service, topic, req)
+}
+
+func (m *mockKafkaTopics) Delete(project, service, topic string) error {
+	return m.DeleteFunc(project, service, topic)
+}
+
+type mockKafkaACLs struct {
+	ListFunc   func(project, service string) ([]*aiven.KafkaACL, error)
+	GetFunc    func(project, service, aclID string) (*aiven.KafkaACL, error)
+	CreateFunc func(project, service string, req aiven.CreateKafkaACLRequest) (*aiven.KafkaACL, error)
+	DeleteFunc func(project, service, aclID string) error
+}
+
+func (m *mockKafkaACLs) List(project, service string) ([]*aiven.KafkaACL, error) {
+	return m.ListFunc(project, service)
+}
+
+func (m *mockKafkaACLs) Get(project, service, aclID string) (*aiven.KafkaACL, error) {
+	return m.GetFunc(project, service, aclID)
+}
+
+func (m *mockKafkaACLs) Create(project, service string, req aiven.CreateKafkaACLRequest) (*aiven.KafkaACL, error) {
+	return m.CreateFunc(project, service, req)
+}
+
+func (m *mockKafkaACLs) Delete(project, service, aclID string) error {
+	return m.DeleteFunc(project, service, aclID)
+}
+
+type mockKafkaSubjectSchemas struct {
+	GetVersionsFunc         func(project, service, name string) (*aiven.KafkaSchemaSubjectVersionsResponse, error)
+	AddFunc                 func(project, service, name string, subject aiven.KafkaSchemaSubject) (*aiven.KafkaSchemaSubjectResponse, error)
+	UpdateConfigurationFunc func(project, service, name, compatibility string) (*aiven.KafkaSchemaConfigUpdateResponse, error)
+	DeleteFunc              func(project, service, name string, versions ...int) error
+}
+
+func (m *mockKafkaSubjectSchemas) GetVersions(project, service, name string) (*aiven.KafkaSchemaSubjectVersionsResponse, error) {
+	return m.GetVersionsFunc(project, service, name)
+}
+
+func (m *mockKafkaSubjectSchemas) Add(project, service, name string, subject aiven.KafkaSchemaSubject) (*aiven.KafkaSchemaSubjectResponse, error) {
+	return m.AddFunc(project, service, name, subject)
+}
+
+func (m *mockKafkaSubjectSchemas) UpdateConfiguration(project, service, name, compatibility string) (*aiven.KafkaSchemaConfigUpdateResponse, error) {
+	return m.UpdateConfigurationFunc(project, service, name, compatibility)
+}
+
+func (m *mockKafkaSubjectSchemas) Delete(project, service, name string, versions ...int) error {
+	return m.DeleteFunc(project, service, name, versions...)
+}
+
+type mockKafkaConnectors struct {
+	StatusFunc    func(project, service, name string) (*aiven.KafkaConnectorStatusResponse, error)
+	GetByNameFunc func(project, service, name string) (*aiven.KafkaConnector, error)
+	CreateFunc    func(project, service string, c aiven.KafkaConnectorConfig) error
+	UpdateFunc    func(project, service, name string, c aiven.KafkaConnectorConfig) (*aiven.KafkaConnectorResponse, error)
+	DeleteFunc    func(project, service, name string) error
+}
+
+func (m *mockKafkaConnectors) Status(project, service, name string) (*aiven.KafkaConnectorStatusResponse, error) {
+	return m.StatusFunc(project, service, name)
+}
+
+func (m *mockKafkaConnectors) GetByName(project, service, name string) (*aiven.KafkaConnector, error) {
+	return m.GetByNameFunc(project, service, name)
+}
+
+func (m *mockKafkaConnectors) Create(project, service string, c aiven.KafkaConnectorConfig) error {
+	return m.CreateFunc(project, service, c)
+}
+
+func (m *mockKafkaConnectors) Update(project, service, name string, c aiven.KafkaConnectorConfig) (*aiven.KafkaConnectorResponse, error) {
+	return m.UpdateFunc(project, service, name, c)
+}
+
+func (m *mockKafkaConnectors) Delete(project, service, name string) error {
+	return m.DeleteFunc(project, service, name)
+}
+
+type mockClickhouseUser struct {
+	ListFunc          func(project, service string) (*aiven.ListClickhouseUserResponse, error)
+	CreateFunc        func(project, service, name string) (*aiven.ClickhouseUserResponse, error)
+	ResetPasswordFunc func(project, service, uuid, password string) (string, error)
+	DeleteFunc        func(project, service, uuid string) error
+}
+
+func (m *mockClickhouseUser) List(project, service string) (*aiven.ListClickhouseUserResponse, error) {
+	return m.ListFunc(project, service)
+}
+
+func (m *mockClickhouseUser) Create(project, service, name string) (*aiven.ClickhouseUserResponse, error) {
+	return m.CreateFunc(project, service, name)
+}
+
+func (m *mockClickhouseUser) ResetPassword(project, service, uuid, password string) (string, error) {
+	return m.ResetPasswordFunc(project, service, uuid, password)
+}
+
+func (m *mockClickhouseUser) Delete(project, service, uuid string) error {
+	return m.DeleteFunc(project, service, uuid)
+}
+
+type mockProjects struct {
+	GetFunc          func(project string) (*aiven.Project, error)
+	CreateFunc       func(req aiven.CreateProjectRequest) (*aiven.Project, error)
+	UpdateFunc       func(project string, req aiven.UpdateProjectRequest) (*aiven.Project, error)
+	DeleteFunc       func(project string) error
+	ServiceTypesFunc func(project string) (map[string]aiven.ServiceType, error)
+}
+
+func (m *mockProjects) Get(project string) (*aiven.Project, error) {
+	return m.GetFunc(project)
+}
+
+func (m *mockProjects) Create(req aiven.CreateProjectRequest) (*aiven.Project, error) {
+	return m.CreateFunc(req)
+}
+
+func (m *mockProjects) Update(project string, req aiven.UpdateProjectRequest) (*aiven.Project, error) {
+	return m.UpdateFunc(project, req)
+}
+
+func (m *mockProjects) Delete(project string) error {
+	return m.DeleteFunc(project)
+}
+
+func (m *mockProjects) ServiceTypes(project string) (map[string]aiven.ServiceType, error) {
+	return m.ServiceTypesFunc(project)
+}
+
+type mockVPCs struct {
+	ListFunc   func(project string) ([]*aiven.VPC, error)
+	CreateFunc func(project string, req aiven.CreateVPCRequest) (*aiven.VPC, error)
+	DeleteFunc func(project, vpcID string) error
+}
+
+func (m *mockVPCs) List(project string) ([]*aiven.VPC, error) {
+	return m.ListFunc(project)
+}
+
+func (m *mockVPCs) Create(project string, req aiven.CreateVPCRequest) (*aiven.VPC, error) {
+	return m.CreateFunc(project, req)
+}
+
+func (m *mockVPCs) Delete(project, vpcID string) error {
+	return m.DeleteFunc(project, vpcID)
+}
+
+type mockCA struct {
+	GetFunc func(project string) (string, error)
+}
+
+func (m *mockCA) Get(project string) (string, error) {
+	return m.GetFunc(project)
+}
+
+type mockCards struct {
+	GetFunc func(cardID string) (*aiven.Card, error)
+}
+
+func (m *mockCards) Get(cardID string) (*aiven.Card, error) {
+	return m.GetFunc(cardID)
+}
+
+type mockAccountAuthentications struct {
+	ListFunc   func(accountID string) (*aiven.AccountAuthenticationListResponse, error)
+	GetFunc    func(accountID, authID string) (*aiven.AccountAuthenticationResponse, error)
+	CreateFunc func(accountID string, req aiven.AccountAuthenticationMethodCreate) (*aiven.AccountAuthenticationResponse, error)
+	UpdateFunc func(accountID, authID string, req aiven.AccountAuthenticationMethodUpdate) (*aiven.AccountAuthenticationResponse, error)
+	DeleteFunc func(accountID, authID string) error
+}
+
+func (m *mockAccountAuthentications) List(accountID string) (*aiven.AccountAuthenticationListResponse, error) {
+	return m.ListFunc(accountID)
+}
+
+func (m *mockAccountAuthentications) Get(accountID, authID string) (*aiven.AccountAuthenticationResponse, error) {
+	return m.GetFunc(accountID, authID)
+}
+
+func (m *mockAccountAuthentications) Create(accountID string, req aiven.AccountAuthenticationMethodCreate) (*aiven.AccountAuthenticationResponse, error) {
+	return m.CreateFunc(accountID, req)
+}
+
+func (m *mockAccountAuthentications) Update(accountID, authID string, req aiven.AccountAuthenticationMethodUpdate) (*aiven.AccountAuthenticationResponse, error) {
+	return m.UpdateFunc(accountID, authID, req)
+}
+
+func (m *mockAccountAuthentications) Delete(accountID, authID string) error {
+	return m.DeleteFunc(accountID, authID)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// apiThrottleMinCalls the error rate is not evaluated on fewer calls within the window,
+// so a single failed call of an idle operator doesn't throttle it
+const apiThrottleMinCalls = 10
+
+// apiCall an observed Aiven API response
+type apiCall struct {
+	at     time.Time
+	failed bool
+}
+
+// apiThrottle slows down all reconciles while Aiven API responds with 5xx and 429 errors,
+// so the operator doesn't add load to Aiven API while it is degraded.
+// The throttle is shared by all controllers
+type apiThrottle struct {
+	// threshold the error rate in (0, 1] the throttle turns on at
+	threshold float64
+
+	// window the error rate is calculated over
+	window time.Duration
+
+	// factor requeue intervals are multiplied by while throttled
+	factor int
+
+	// now returns the current time, time.Now if nil
+	now func() time.Time
+
+	mu        sync.Mutex
+	calls     []apiCall
+	throttled bool
+}
+
+// newAPIThrottle returns nil, which never throttles, if the threshold or the window is not set
+func newAPIThrottle(threshold float64, window time.Duration, factor int) *apiThrottle {
+	if threshold <= 0 || window <= 0 {
+		return nil
+	}
+	if threshold > 1 {
+		threshold = 1
+	}
+	if factor < 1 {
+		factor = 1
+	}
+	apiThrottled.Set(0)
+	return &apiThrottle{threshold: threshold, window: window, factor: factor, now: time.Now}
+}
+
+// observe records the response status code
+func (t *apiThrottle) observe(status int) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.calls = append(t.calls, apiCall{at: now, failed: status >= http.StatusInternalServerError || status == http.StatusTooManyRequests})
+	t.update(now)
+}
+
+// isThrottled returns true while the error rate within the window is over the threshold
+func (t *apiThrottle) isThrottled() bool {
+	if t == nil {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.update(t.now())
+	return t.throttled
+}
+
+// update drops the calls out of the window and recalculates the state, must be called with the lock held.
+// The state recovers as the failed calls leave the window
+func (t *apiThrottle) update(now time.Time) {
+	start := 0
+	for start < len(t.calls) && now.Sub(t.calls[start].at) >= t.window {
+		start++
+	}
+	t.calls = t.calls[start:]
+
+	failed := 0
+	for _, c := range t.calls {
+		if c.failed {
+			failed++
+		}
+	}
+	throttled := len(t.calls) >= apiThrottleMinCalls && float64(failed)/float64(len(t.calls)) >= t.threshold
+	if throttled != t.throttled {
+		t.throttled = throttled
+		v := 0.0
+		if throttled {
+			v = 1
+		}
+		apiThrottled.Set(v)
+	}
+}
+
+// slowDown multiplies the requeue interval by the factor while throttled.
+// Immediate requeues are delayed too, errors are backed off by the controller rate limiter
+func (t *apiThrottle) slowDown(res ctrl.Result) ctrl.Result {
+	if !t.isThrottled() || (!res.Requeue && res.RequeueAfter == 0) {
+		return res
+	}
+
+	after := res.RequeueAfter
+	if after == 0 {
+		after = requeueTimeout
+	}
+	return ctrl.Result{Requeue: true, RequeueAfter: after * time.Duration(t.factor)}
+}
+
+// withAPIThrottle reports Aiven API responses of the client to the throttle.
+// Only aiven-go-client is supported, other clients are returned as is
+func withAPIThrottle(avn AivenClient, t *apiThrottle) AivenClient {
+	g, ok := avn.(*goClient)
+	if !ok || t == nil {
+		return avn
+	}
+
+	next := g.c.Client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	g.c.Client.Transport = &throttleTransport{next: next, t: t}
+	return avn
+}
+
+// throttleTransport observes the response status codes, transport errors are not Aiven API errors
+type throttleTransport struct {
+	next http.RoundTripper
+	t    *apiThrottle
+}
+
+func (t *throttleTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	rsp, err := t.next.RoundTrip(r)
+	if err == nil {
+		t.t.observe(rsp.StatusCode)
+	}
+	return rsp, err
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func Test_apiThrottle(t *testing.T) {
+	now := time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)
+	th := newAPIThrottle(0.5, time.Minute, 4)
+	th.now = func() time.Time { return now }
+
+	// Too few calls to evaluate the rate
+	for i := 0; i < apiThrottleMinCalls-1; i++ {
+		th.observe(http.StatusServiceUnavailable)
+	}
+	if th.isThrottled() {
+		t.Fatal("must not throttle on fewer calls than the minimum")
+	}
+
+	th.observe(http.StatusTooManyRequests)
+	if !th.isThrottled() || testutil.ToFloat64(apiThrottled) != 1 {
+		t.Fatal("must throttle when the error rate is over the threshold")
+	}
+	res := th.slowDown(ctrl.Result{RequeueAfter: time.Minute})
+	if res.RequeueAfter != 4*time.Minute {
+		t.Errorf("requeue after = %s, want 4m", res.RequeueAfter)
+	}
+	if res = th.slowDown(ctrl.Result{}); res.Requeue || res.RequeueAfter != 0 {
+		t.Errorf("no requeue must stay as is, got %+v", res)
+	}
+
+	// Successful calls bring the rate under the threshold
+	for i := 0; i < apiThrottleMinCalls+1; i++ {
+		th.observe(http.StatusOK)
+	}
+	if th.isThrottled() {
+		t.Error("must not throttle when the error rate is under the threshold")
+	}
+
+	// The failed calls leave the window
+	for i := 0; i < 3*apiThrottleMinCalls; i++ {
+		th.observe(http.StatusBadGateway)
+	}
+	if !th.isThrottled() {
+		t.Fatal("must throttle again")
+	}
+	now = now.Add(time.Minute)
+	if th.isThrottled() || testutil.ToFloat64(apiThrottled) != 0 {
+		t.Error("must recover when the failed calls leave the window")
+	}
+
+	// Disabled
+	var disabled *apiThrottle
+	disabled.observe(http.StatusInternalServerError)
+	if res = disabled.slowDown(ctrl.Result{RequeueAfter: time.Minute}); res.RequeueAfter != time.Minute {
+		t.Errorf("disabled throttle must not change the result, got %+v", res)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// auditEvents lifecycle events that are written to the audit log
+var auditEvents = map[string]bool{
+	eventCreatedAtAiven:                true,
+	eventUpdatedAtAiven:                true,
+	eventUnableToCreateOrUpdateAtAiven: true,
+	eventSuccessfullyDeletedAtAiven:    true,
+	eventUnableToDeleteAtAiven:         true,
+	eventUnableToDelete:                true,
+	eventUnableToWaitForPreconditions:  true,
+}
+
+// auditRecorder records events as usual and additionally writes a structured log line
+// for lifecycle events, so operator actions can be audited without events collection tooling
+type auditRecorder struct {
+	record.EventRecorder
+
+	// scheme resolves the kind, the client doesn't set TypeMeta of typed objects
+	scheme *runtime.Scheme
+	log    logr.Logger
+}
+
+func newAuditRecorder(rec record.EventRecorder, scheme *runtime.Scheme, log logr.Logger) record.EventRecorder {
+	return &auditRecorder{EventRecorder: rec, scheme: scheme, log: log}
+}
+
+func (r *auditRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.EventRecorder.Event(object, eventtype, reason, message)
+	r.audit(object, eventtype, reason, message)
+}
+
+func (r *auditRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	r.audit(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *auditRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	r.audit(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *auditRecorder) audit(object runtime.Object, eventtype, reason, message string) {
+	if !auditEvents[reason] {
+		return
+	}
+
+	kind := object.GetObjectKind().GroupVersionKind().Kind
+	if gvk, err := apiutil.GVKForObject(object, r.scheme); err == nil {
+		kind = gvk.Kind
+	}
+
+	values := []interface{}{
+		"kind", kind,
+		"type", eventtype,
+		"reason", reason,
+		"message", message,
+	}
+
+	if o, err := meta.Accessor(object); err == nil {
+		values = append(values,
+			"namespace", o.GetNamespace(),
+			"name", o.GetName(),
+			"uid", o.GetUID(),
+			"generation", o.GetGeneration(),
+		)
+	}
+
+	r.log.Info("audit", values...)
+}
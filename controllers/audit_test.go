@@ -0,0 +1,41 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+func Test_auditRecorder(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	var logs []string
+	log := funcr.New(func(prefix, args string) { logs = append(logs, args) }, funcr.Options{})
+	rec := newAuditRecorder(record.NewFakeRecorder(10), scheme, log)
+
+	// Typed objects read by the client have no TypeMeta
+	pg := &v1alpha1.PostgreSQL{ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "default", UID: "uid"}}
+	rec.Event(pg, corev1.EventTypeNormal, eventCreatedAtAiven, "instance was created")
+	rec.Event(pg, corev1.EventTypeNormal, "NotAudited", "skipped")
+
+	if len(logs) != 1 {
+		t.Fatalf("logs = %v, want the lifecycle event only", logs)
+	}
+	for _, want := range []string{`"kind"="PostgreSQL"`, `"reason"="CreatedAtAiven"`, `"name"="pg"`, `"uid"="uid"`} {
+		if !strings.Contains(logs[0], want) {
+			t.Errorf("audit log %s, want %s", logs[0], want)
+		}
+	}
+}
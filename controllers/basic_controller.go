@@ -2,8 +2,11 @@ package controllers
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -13,11 +16,18 @@ import (
 	"github.com/liip/sheriff"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
@@ -29,6 +39,9 @@ const formatIntBaseDecimal = 10
 // requeueTimeout sets timeout to requeue controller
 const requeueTimeout = 10 * time.Second
 
+// accountSuspendedRequeueTimeout every call fails while the account is suspended, hence the long interval
+const accountSuspendedRequeueTimeout = 30 * time.Minute
+
 var errNoTokenProvided = fmt.Errorf("authSecretReference is not set and no default token provided")
 
 type (
@@ -40,6 +53,49 @@ type (
 		Scheme       *runtime.Scheme
 		Recorder     record.EventRecorder
 		DefaultToken string
+
+		// ClientTimeout Aiven API HTTP client timeout, no timeout if zero
+		ClientTimeout time.Duration
+
+		// newAivenClient creates Aiven clients, newTokenClient if nil
+		newAivenClient AivenClientFactory
+
+		// preconditions backs off requeue of instances which preconditions are not met
+		preconditions *preconditionBackoff
+
+		// requeue backs off requeue of instances which are not running yet
+		requeue *requeueBackoff
+
+		// protectedNamespaces selects namespaces which instances are never deleted on Aiven side
+		protectedNamespaces labels.Selector
+
+		// cache keeps get results of running instances
+		cache *getCache
+
+		// scrapeConfigs is true if the Prometheus Operator ScrapeConfig CRD is installed
+		scrapeConfigs bool
+
+		// maxStatusSize larger statuses are trimmed, bytes. Disabled if zero
+		maxStatusSize int
+
+		// finalizerTimeout the finalizer is removed when the deletion on Aiven side doesn't succeed in time.
+		// Disabled if zero
+		finalizerTimeout time.Duration
+
+		// adoptSecrets overwrites existing secrets which are not owned by the instance
+		adoptSecrets bool
+
+		// authTokens the authSecretRef file and environment variable sources
+		authTokens AuthTokenSources
+
+		// gcOrphanedEndpoints deletes the integration endpoints which no ServiceIntegration references
+		gcOrphanedEndpoints bool
+
+		// userConfigSchemas validates service user configs against Aiven schemas. Disabled if nil
+		userConfigSchemas *userConfigSchemas
+
+		// apiThrottle slows down requeues while Aiven API error rate is high. Disabled if nil
+		apiThrottle *apiThrottle
 	}
 
 	// Handlers represents Aiven API handlers
@@ -47,20 +103,38 @@ type (
 	// of the Aiven services lifecycle.
 	Handlers interface {
 		// create or updates an instance on the Aiven side.
-		createOrUpdate(*aiven.Client, client.Object, []client.Object) error
+		createOrUpdate(AivenClient, client.Object, []client.Object) error
 
 		// delete removes an instance on Aiven side.
 		// If an object is already deleted and cannot be found, it should not be an error. For other deletion
 		// errors, return an error.
-		delete(*aiven.Client, client.Object) (bool, error)
+		delete(AivenClient, client.Object) (bool, error)
 
 		// get retrieve an object and a secret (for example, connection credentials) that is generated on the
 		// fly based on data from Aiven API.  When not applicable to service, it should return nil.
-		get(*aiven.Client, client.Object) (*corev1.Secret, error)
+		get(AivenClient, client.Object) (*corev1.Secret, error)
 
 		// checkPreconditions check whether all preconditions for creating (or updating) the resource are in place.
 		// For example, it is applicable when a service needs to be running before this resource can be created.
-		checkPreconditions(*aiven.Client, client.Object) (bool, error)
+		checkPreconditions(AivenClient, client.Object) (bool, error)
+	}
+
+	// outdatedHandler is implemented by handlers which read external inputs, like ConfigMaps.
+	// The input changes don't change the object generation, hence the instance is updated when isOutdated is true
+	outdatedHandler interface {
+		isOutdated(client.Object) (bool, error)
+	}
+
+	// planHandler is implemented by handlers which can compute the changes createOrUpdate would apply.
+	// The plan is stored in the instance status
+	planHandler interface {
+		plan(AivenClient, client.Object) ([]fieldDiff, error)
+	}
+
+	// scheduledHandler is implemented by handlers which do scheduled work on running instances,
+	// like applying maintenance in a window. Zero nextCheck means no requeue
+	scheduledHandler interface {
+		nextCheck(client.Object, time.Time) time.Duration
 	}
 
 	aivenManagedObject interface {
@@ -75,11 +149,69 @@ type (
 
 		GetRefs() []*v1alpha1.ResourceReferenceObject
 	}
+
+	// connInfoSecretTargetObject returns connection info secret settings
+	connInfoSecretTargetObject interface {
+		client.Object
+
+		GetConnInfoSecretTarget() v1alpha1.ConnInfoSecretTarget
+	}
+
+	// serviceConnInfoSecretTargetObject returns service connection info secret settings
+	serviceConnInfoSecretTargetObject interface {
+		client.Object
+
+		GetServiceConnInfoSecretTarget() v1alpha1.ServiceConnInfoSecretTarget
+	}
+
+	// projectObject is an object which project can be set by the Project reference
+	projectObject interface {
+		client.Object
+
+		GetProject() string
+		SetProject(string)
+	}
+
+	// serviceRefsObject sets service names from service references
+	serviceRefsObject interface {
+		client.Object
+
+		SetServiceNames()
+	}
+
+	// conditionsObject returns status conditions
+	conditionsObject interface {
+		client.Object
+
+		GetConditions() *[]metav1.Condition
+	}
+
+	// creationDelayObject returns seconds to defer the first reconcile of a new object
+	creationDelayObject interface {
+		client.Object
+
+		GetCreationDelay() int
+	}
+
+	// skipPreconditionsObject returns true if the handler preconditions must not be checked
+	skipPreconditionsObject interface {
+		client.Object
+
+		GetSkipPreconditions() bool
+	}
+
+	// connInfoConfigMapObject returns config map target to copy non-sensitive connection info to
+	connInfoConfigMapObject interface {
+		client.Object
+
+		GetConnInfoConfigMapTarget() *v1alpha1.ConnInfoConfigMapTarget
+	}
 )
 
 const (
 	// Lifecycle event types we expose to the user
 	eventUnableToGetAuthSecret              = "UnableToGetAuthSecret"
+	eventUnableToReadAuthToken              = "UnableToReadAuthToken"
 	eventUnableToCreateClient               = "UnableToCreateClient"
 	eventReconciliationStarted              = "ReconcilationStarted"
 	eventTryingToDeleteAtAiven              = "TryingToDeleteAtAiven"
@@ -92,17 +224,37 @@ const (
 	eventWaitingForPreconditions            = "WaitingForPreconditions"
 	eventUnableToWaitForPreconditions       = "UnableToWaitForPreconditions"
 	eventPreconditionsAreMet                = "PreconditionsAreMet"
+	eventPreconditionsAreNotMet             = "PreconditionsAreNotMet"
 	eventUnableToCreateOrUpdateAtAiven      = "UnableToCreateOrUpdateAtAiven"
 	eventCreateOrUpdatedAtAiven             = "CreateOrUpdatedAtAiven"
-	eventCreatedOrUpdatedAtAiven            = "CreatedOrUpdatedAtAiven"
+	eventCreatedAtAiven                     = "CreatedAtAiven"
+	eventUpdatedAtAiven                     = "UpdatedAtAiven"
 	eventWaitingForTheInstanceToBeRunning   = "WaitingForInstanceToBeRunning"
 	eventUnableToWaitForInstanceToBeRunning = "UnableToWaitForInstanceToBeRunning"
 	eventInstanceIsRunning                  = "InstanceIsRunning"
+	eventSecretEmissionDeferred             = "SecretEmissionDeferred"
+	eventWaitingForDependents               = "WaitingForDependents"
+	eventWaitingForSecret                   = "WaitingForSecret"
+	eventPreconditionsSkipped               = "PreconditionsSkipped"
+	eventWaitingForDeletedService           = "WaitingForDeletedService"
+	eventWaitingForIntegratedServices       = "WaitingForIntegratedServices"
+	eventStatusTrimmed                      = "StatusTrimmed"
+	eventFinalizerTimedOut                  = "FinalizerTimedOut"
+	eventOrphaned                           = "Orphaned"
+	eventGenerationProcessed                = "GenerationProcessed"
+	eventSecretNotOwned                     = "SecretNotOwned"
+	eventUnknownSecretKey                   = "UnknownSecretKey"
+	eventAccountSuspended                   = "AccountSuspended"
+	eventProjectNotFound                    = "ProjectNotFound"
+	eventDryRunPlan                         = "DryRunPlan"
 )
 
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=scrapeconfigs,verbs=get;list;watch;create;update;patch;delete
 
 func (c *Controller) reconcileInstance(ctx context.Context, req ctrl.Request, h Handlers, o aivenManagedObject) (ctrl.Result, error) {
 	if err := c.Get(ctx, req.NamespacedName, o); err != nil {
@@ -116,31 +268,94 @@ func (c *Controller) reconcileInstance(ctx context.Context, req ctrl.Request, h
 	var clientAuthSecret *corev1.Secret
 	if len(c.DefaultToken) > 0 {
 		token = c.DefaultToken
-	} else if auth := o.AuthSecretRef(); auth != nil {
-		clientAuthSecret = &corev1.Secret{}
-		if err := c.Get(ctx, types.NamespacedName{Name: auth.Name, Namespace: req.Namespace}, clientAuthSecret); err != nil {
-			c.Recorder.Eventf(o, corev1.EventTypeWarning, eventUnableToGetAuthSecret, err.Error())
-			return ctrl.Result{}, fmt.Errorf("cannot get secret %q: %w", auth.Name, err)
-		}
-		token = string(clientAuthSecret.Data[auth.Key])
 	} else {
-		return ctrl.Result{}, errNoTokenProvided
+		var err error
+		token, clientAuthSecret, err = resolveAuthToken(ctx, c.Client, o, c.authTokens)
+		if err != nil {
+			c.Recorder.Event(o, corev1.EventTypeWarning, authTokenEventReason(err), err.Error())
+			return ctrl.Result{}, err
+		}
 	}
 
-	avn, err := aiven.NewTokenClient(token, operatorUserAgent)
+	avn, err := c.aivenClient(token)
 	if err != nil {
 		c.Recorder.Event(o, corev1.EventTypeWarning, eventUnableToCreateClient, err.Error())
 		return ctrl.Result{}, fmt.Errorf("cannot initialize aiven client: %w", err)
 	}
+	avn = withAPIThrottle(avn, c.apiThrottle)
+	if isDebugEnabled(o) {
+		instanceLogger = instanceLogger.WithValues("debug", true)
+		avn = withDebugLog(avn, instanceLogger)
+	}
+
+	kind := "Unknown"
+	if gvk, err := apiutil.GVKForObject(o, c.Scheme); err == nil {
+		kind = gvk.Kind
+	}
+
+	helper := instanceReconcilerHelper{
+		kind: kind,
+		avn:  avn,
+		k8s:  c.Client,
+		h:    h,
+		log:  instanceLogger,
+		s:    clientAuthSecret,
+		rec:  c.Recorder,
+		pb:   c.preconditions,
+		rb:   c.requeue,
+		pns:  c.protectedNamespaces,
+		gc:   c.cache,
+		sc:   c.scrapeConfigs,
+		ms:   c.maxStatusSize,
+		ft:   c.finalizerTimeout,
+		as:   c.adoptSecrets,
+		us:   c.userConfigSchemas,
+	}
+	res, err := helper.reconcileInstance(ctx, o)
 
-	return instanceReconcilerHelper{
-		avn: avn,
-		k8s: c.Client,
-		h:   h,
-		log: instanceLogger,
-		s:   clientAuthSecret,
-		rec: c.Recorder,
-	}.reconcileInstance(ctx, o)
+	// Slow Aiven API is not a permanent failure
+	if isTimeoutError(err) {
+		instanceLogger.Info("aiven api call timed out, triggering requeue", "error", err.Error())
+		return ctrl.Result{Requeue: true, RequeueAfter: requeueTimeout}, nil
+	}
+
+	// Suspended account fails every call until it is active again, no point to retry often
+	if isAccountSuspendedError(err) {
+		instanceLogger.Info("aiven account is suspended, backing off", "error", err.Error(), "after", accountSuspendedRequeueTimeout)
+		c.Recorder.Event(o, corev1.EventTypeWarning, eventAccountSuspended, err.Error())
+		return ctrl.Result{RequeueAfter: accountSuspendedRequeueTimeout}, helper.setAccountSuspended(ctx, o, err.Error())
+	}
+	if err == nil {
+		err = client.IgnoreNotFound(helper.setAccountSuspended(ctx, o, ""))
+	}
+
+	// Degraded Aiven API gets less load until it recovers
+	if slowed := c.apiThrottle.slowDown(res); slowed != res {
+		instanceLogger.Info("aiven api error rate is high, slowing down requeue", "after", slowed.RequeueAfter)
+		return slowed, err
+	}
+	return res, err
+}
+
+// aivenClient returns the client authorized with the token
+func (c *Controller) aivenClient(token string) (AivenClient, error) {
+	if c.newAivenClient != nil {
+		return c.newAivenClient(token)
+	}
+	return newTokenClient(token, c.ClientTimeout)
+}
+
+// objectAivenClient returns Aiven client authorized with the default token, or with the object token
+func (c *Controller) objectAivenClient(ctx context.Context, o aivenManagedObject) (AivenClient, error) {
+	token := c.DefaultToken
+	if token == "" {
+		var err error
+		token, _, err = resolveAuthToken(ctx, c.Client, o, c.authTokens)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c.aivenClient(token)
 }
 
 // a helper that closes over all instance specific fields
@@ -148,8 +363,11 @@ func (c *Controller) reconcileInstance(ctx context.Context, req ctrl.Request, h
 type instanceReconcilerHelper struct {
 	k8s client.Client
 
+	// kind, instance kind for metrics
+	kind string
+
 	// avn, Aiven client that is authorized with the instance token
-	avn *aiven.Client
+	avn AivenClient
 
 	// h, instance specific handler implementation
 	h Handlers
@@ -162,19 +380,59 @@ type instanceReconcilerHelper struct {
 
 	// rec, recorder to record events for the object
 	rec record.EventRecorder
+
+	// pb, precondition backoff shared by all instances of the controller
+	pb *preconditionBackoff
+
+	// rb, backoff of the instances waiting to be running, the attempts are stored in the instances
+	rb *requeueBackoff
+
+	// pns, selector of deletion-protected namespaces, nil if disabled
+	pns labels.Selector
+
+	// gc, get results cache shared by all instances of the controller
+	gc *getCache
+
+	// sc, true if the Prometheus Operator ScrapeConfig CRD is installed
+	sc bool
+
+	// ms, maximum status size in bytes, disabled if zero
+	ms int
+
+	// ft, finalizer timeout, disabled if zero
+	ft time.Duration
+
+	// as, adopt secrets, overwrites existing secrets which are not owned by the instance
+	as bool
+
+	// us, user config schemas shared by all controllers, validation is disabled if nil
+	us *userConfigSchemas
 }
 
-func (i instanceReconcilerHelper) reconcileInstance(ctx context.Context, o client.Object) (ctrl.Result, error) {
+func (i instanceReconcilerHelper) reconcileInstance(ctx context.Context, o client.Object) (res ctrl.Result, err error) {
+	defer func() {
+		observeReconcileResult(i.kind, res, err)
+	}()
+
 	i.log.Info("reconciling instance")
 	i.rec.Event(o, corev1.EventTypeNormal, eventReconciliationStarted, "starting reconciliation")
 
 	if isMarkedForDeletion(o) {
+		i.pb.reset(client.ObjectKeyFromObject(o))
+		i.gc.invalidate(o)
+		notRunning.set(i.kind, client.ObjectKeyFromObject(o), false)
 		if controllerutil.ContainsFinalizer(o, instanceDeletionFinalizer) {
 			return i.finalize(ctx, o)
 		}
 		return ctrl.Result{}, nil
 	}
 
+	// New instances with creation delay go after the others on mass apply
+	if after := creationDelay(o, time.Now()); after > 0 {
+		i.log.Info("deferring instance creation", "after", after)
+		return ctrl.Result{RequeueAfter: after}, nil
+	}
+
 	// Add finalizers to an instance and associated secret, only if they haven't
 	// been added in the previous reconciliation loops
 	if i.s != nil {
@@ -199,31 +457,102 @@ func (i instanceReconcilerHelper) reconcileInstance(ctx context.Context, o clien
 	refs, err := i.getObjectRefs(ctx, o)
 	if err != nil {
 		i.log.Info(fmt.Sprintf("one or more references can't be found yet: %s", err))
-		return ctrl.Result{Requeue: true, RequeueAfter: requeueTimeout}, nil
+		err = i.setDependenciesNotReady(ctx, o, "ReferenceNotFound", err.Error())
+		return ctrl.Result{Requeue: true, RequeueAfter: requeueTimeout}, err
+	}
+
+	if err := resolveProject(o, refs); err != nil {
+		i.log.Info(err.Error())
+		return ctrl.Result{}, i.setDependenciesNotReady(ctx, o, "InvalidProject", err.Error())
+	}
+
+	// Service resource name is the Aiven service name
+	if so, ok := o.(serviceRefsObject); ok {
+		so.SetServiceNames()
+	}
+
+	// Instances which are not created yet wait for the dependencies
+	if o.GetAnnotations()[processedGenerationAnnotation] == "" {
+		if _, err := setStatusPhase(o, v1alpha1.PhasePending); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
 	requeue, err := i.checkPreconditions(ctx, o, refs)
 	if requeue {
+		attempt, after := i.pb.next(client.ObjectKeyFromObject(o), preconditionsReason(o), time.Now())
+		i.rec.Eventf(o, corev1.EventTypeNormal, eventPreconditionsAreNotMet,
+			"preconditions are not met (attempt %d), next check in %s", attempt, after)
+
 		// It must be possible to return requeue and error by design.
 		// By the time this comment created, there is no such case in checkPreconditions()
-		return ctrl.Result{Requeue: true, RequeueAfter: requeueTimeout}, err
+		return ctrl.Result{Requeue: true, RequeueAfter: after}, err
 	}
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if waited, reason, ok := i.pb.met(client.ObjectKeyFromObject(o), time.Now()); ok {
+		preconditionWaitSeconds.WithLabelValues(i.kind, reason).Observe(waited.Seconds())
+	}
+
+	outdated, err := i.isOutdated(o)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if outdated && isDryRun(o) {
+		if err = i.dryRun(ctx, o); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// The state and secret of the existing instance are kept up to date
+		if o.GetAnnotations()[processedGenerationAnnotation] == "" {
+			return ctrl.Result{}, nil
+		}
+		outdated = false
+	}
+
+	if outdated {
+		// Any processed generation means the instance exists on Aiven side
+		exists := o.GetAnnotations()[processedGenerationAnnotation] != ""
+
+		// Saved with the status once the instance state is checked
+		phase := v1alpha1.PhaseCreating
+		if exists {
+			phase = v1alpha1.PhaseUpdating
+		}
+		if _, err := setStatusPhase(o, phase); err != nil {
+			return ctrl.Result{}, err
+		}
 
-	if !isAlreadyProcessed(o) {
 		i.rec.Event(o, corev1.EventTypeNormal, eventCreateOrUpdatedAtAiven, "about to create instance at aiven")
-		if err := i.createOrUpdateInstance(o, refs); err != nil {
+		err := i.createOrUpdateInstance(o, refs)
+		if errors.Is(err, errServiceNameReserved) {
+			i.rec.Event(o, corev1.EventTypeNormal, eventWaitingForDeletedService, err.Error())
+			return ctrl.Result{Requeue: true, RequeueAfter: requeueTimeout}, nil
+		}
+		if errors.Is(err, errIntegrationNotReady) {
+			i.rec.Event(o, corev1.EventTypeNormal, eventWaitingForIntegratedServices, err.Error())
+			return ctrl.Result{Requeue: true, RequeueAfter: requeueTimeout}, nil
+		}
+		if err != nil {
 			i.rec.Event(o, corev1.EventTypeWarning, eventUnableToCreateOrUpdateAtAiven, err.Error())
-			return ctrl.Result{}, fmt.Errorf("unable to create or update instance at aiven: %w", err)
+			err = fmt.Errorf("unable to create or update instance at aiven: %w", err)
+			return ctrl.Result{}, multierror.Append(err, i.setPhase(ctx, o, v1alpha1.PhaseFailed)).ErrorOrNil()
 		}
 
-		i.rec.Event(o, corev1.EventTypeNormal, eventCreatedOrUpdatedAtAiven, "instance was created at aiven but may not be running yet")
+		if exists {
+			i.rec.Event(o, corev1.EventTypeNormal, eventUpdatedAtAiven, "instance was updated at aiven but may not be running yet")
+		} else {
+			i.rec.Event(o, corev1.EventTypeNormal, eventCreatedAtAiven, "instance was created at aiven but may not be running yet")
+		}
 	}
 
 	i.rec.Event(o, corev1.EventTypeNormal, eventWaitingForTheInstanceToBeRunning, "waiting for the instance to be running")
-	isRunning, err := i.updateInstanceStateAndSecretUntilRunning(ctx, o)
+	isRunning, rotationDue, err := i.updateInstanceStateAndSecretUntilRunning(ctx, o)
+	if err == nil {
+		notRunning.set(i.kind, client.ObjectKeyFromObject(o), !isRunning)
+	}
 	if err != nil {
 		if aiven.IsNotFound(err) {
 			return ctrl.Result{
@@ -232,24 +561,59 @@ func (i instanceReconcilerHelper) reconcileInstance(ctx context.Context, o clien
 			}, nil
 		}
 
+		// The integration recreated on get waits for the services the same way
+		if errors.Is(err, errIntegrationNotReady) {
+			i.rec.Event(o, corev1.EventTypeNormal, eventWaitingForIntegratedServices, err.Error())
+			return ctrl.Result{Requeue: true, RequeueAfter: requeueTimeout}, nil
+		}
+
 		i.rec.Event(o, corev1.EventTypeWarning, eventUnableToWaitForInstanceToBeRunning, err.Error())
 		return ctrl.Result{}, fmt.Errorf("unable to wait until instance is running: %w", err)
 	}
 
+	if !isRunning && isPoweredOff(o) {
+		i.log.Info("instance is powered off, waiting for the spec change")
+		return ctrl.Result{}, nil
+	}
+
 	if !isRunning {
-		i.log.Info("instance is not yet running, triggering requeue")
+		after := i.rb.after(requeueAttempt(o))
+		i.log.Info("instance is not yet running, triggering requeue", "after", after)
 		return ctrl.Result{
 			Requeue:      true,
-			RequeueAfter: requeueTimeout,
+			RequeueAfter: after,
 		}, nil
 	}
 
 	i.rec.Event(o, corev1.EventTypeNormal, eventInstanceIsRunning, "instance is in a RUNNING state")
 	i.log.Info("instance was successfully reconciled")
 
+	// The secret CA bundle collapses to the new CA once the rotation is over
+	after := rotationDue
+	if h, ok := i.h.(scheduledHandler); ok {
+		if next := h.nextCheck(o, time.Now()); next > 0 && (after == 0 || next < after) {
+			after = next
+		}
+	}
+	if after > 0 {
+		i.log.Info("instance has scheduled work, triggering requeue", "after", after)
+		return ctrl.Result{RequeueAfter: after}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
+// isOutdated returns true if the generation is not processed or the handler external inputs have changed
+func (i instanceReconcilerHelper) isOutdated(o client.Object) (bool, error) {
+	if !isAlreadyProcessed(o) {
+		return true, nil
+	}
+	if h, ok := i.h.(outdatedHandler); ok {
+		return h.isOutdated(o)
+	}
+	return false, nil
+}
+
 func (i instanceReconcilerHelper) checkPreconditions(ctx context.Context, o client.Object, refs []client.Object) (bool, error) {
 	i.rec.Event(o, corev1.EventTypeNormal, eventWaitingForPreconditions, "waiting for preconditions of the instance")
 
@@ -258,13 +622,45 @@ func (i instanceReconcilerHelper) checkPreconditions(ctx context.Context, o clie
 		for _, r := range refs {
 			if !(isAlreadyProcessed(r) && IsAlreadyRunning(r)) {
 				i.log.Info("references are in progress")
-				return true, nil
+				kind := "Reference"
+				if gvk, err := apiutil.GVKForObject(r, i.k8s.Scheme()); err == nil {
+					kind = gvk.Kind
+				}
+				msg := fmt.Sprintf("%s %s is not running yet", kind, r.GetName())
+				return true, i.setDependenciesNotReady(ctx, o, kind+"NotReady", msg)
 			}
 		}
 		i.log.Info("all references are good")
 	}
 
+	if s, ok := o.(skipPreconditionsObject); ok && s.GetSkipPreconditions() {
+		i.rec.Event(o, corev1.EventTypeWarning, eventPreconditionsSkipped, "preconditions are skipped, proceeding to create or update")
+		if c, ok := o.(conditionsObject); ok {
+			meta.SetStatusCondition(c.GetConditions(), getDependenciesReadyCondition(metav1.ConditionUnknown, "PreconditionsSkipped", "preconditions are not checked"))
+		}
+		return false, nil
+	}
+
+	err := checkProjectExists(i.avn, o, refs)
+	if errors.Is(err, errProjectNotFound) {
+		i.rec.Event(o, corev1.EventTypeWarning, eventProjectNotFound, err.Error())
+		return true, i.setDependenciesNotReady(ctx, o, "ProjectNotFound", err.Error())
+	}
+	if err != nil {
+		i.rec.Event(o, corev1.EventTypeWarning, eventUnableToWaitForPreconditions, err.Error())
+		return false, fmt.Errorf("unable to wait for preconditions: %w", err)
+	}
+
 	check, err := i.h.checkPreconditions(i.avn, o)
+	if errors.Is(err, errSecretNotReady) {
+		i.rec.Event(o, corev1.EventTypeWarning, eventWaitingForSecret, err.Error())
+		return true, i.setDependenciesNotReady(ctx, o, "SecretNotReady", err.Error())
+	}
+	if errors.Is(err, errServiceNotFound) {
+		// Most likely the name is wrong, waits in case the service is created outside the operator
+		i.rec.Event(o, corev1.EventTypeWarning, eventWaitingForPreconditions, err.Error())
+		return true, i.setDependenciesNotReady(ctx, o, "ServiceNotFound", err.Error())
+	}
 	if err != nil {
 		i.rec.Event(o, corev1.EventTypeWarning, eventUnableToWaitForPreconditions, err.Error())
 		return false, fmt.Errorf("unable to wait for preconditions: %w", err)
@@ -272,13 +668,188 @@ func (i instanceReconcilerHelper) checkPreconditions(ctx context.Context, o clie
 
 	if !check {
 		i.log.Info("preconditions are not met, requeue")
-		return true, nil
+		return true, i.setDependenciesNotReady(ctx, o, "PreconditionsNotMet", "dependencies on Aiven side are not ready yet")
 	}
 
 	i.rec.Event(o, corev1.EventTypeNormal, eventPreconditionsAreMet, "preconditions are met, proceeding to create or update")
+
+	// Saved along with the instance state
+	if c, ok := o.(conditionsObject); ok {
+		meta.SetStatusCondition(c.GetConditions(), getDependenciesReadyCondition(metav1.ConditionTrue, "DependenciesReady", "all dependencies are ready"))
+	}
 	return false, nil
 }
 
+// setAccountSuspended sets the AccountSuspended condition with the message, removes it if the message is empty
+func (i instanceReconcilerHelper) setAccountSuspended(ctx context.Context, o client.Object, message string) error {
+	c, ok := o.(conditionsObject)
+	if !ok {
+		return nil
+	}
+
+	current := meta.FindStatusCondition(*c.GetConditions(), conditionTypeAccountSuspended)
+	switch {
+	case message == "" && current == nil:
+		return nil
+	case message == "":
+		meta.RemoveStatusCondition(c.GetConditions(), conditionTypeAccountSuspended)
+	case current != nil && current.Message == message:
+		return nil
+	default:
+		meta.SetStatusCondition(c.GetConditions(), getAccountSuspendedCondition(message))
+	}
+	return i.updateStatus(ctx, o)
+}
+
+// setSecretEmitted sets the SecretEmitted condition, returns false if it hasn't changed.
+// Objects without conditions always report a change
+func setSecretEmitted(o client.Object, status metav1.ConditionStatus, reason, message string) bool {
+	c, ok := o.(conditionsObject)
+	if !ok {
+		return true
+	}
+
+	want := getSecretEmittedCondition(status, reason, message)
+	if current := meta.FindStatusCondition(*c.GetConditions(), want.Type); current != nil &&
+		current.Status == want.Status && current.Reason == want.Reason {
+		return false
+	}
+	meta.SetStatusCondition(c.GetConditions(), want)
+	return true
+}
+
+// isPoweredOff returns true if the instance is powered off on purpose, it isn't requeued until the spec changes
+func isPoweredOff(o client.Object) bool {
+	c, ok := o.(conditionsObject)
+	if !ok {
+		return false
+	}
+	current := meta.FindStatusCondition(*c.GetConditions(), conditionTypeRunning)
+	return current != nil && current.Status == metav1.ConditionFalse && current.Reason == reasonPoweredOff
+}
+
+// preconditionsReason returns the reason the dependencies are not ready
+func preconditionsReason(o client.Object) string {
+	if c, ok := o.(conditionsObject); ok {
+		if current := meta.FindStatusCondition(*c.GetConditions(), conditionTypeDependenciesReady); current != nil {
+			return current.Reason
+		}
+	}
+	return "Unknown"
+}
+
+// setDependenciesNotReady sets DependenciesReady condition to False,
+// saves the status right away, because the instance is requeued
+func (i instanceReconcilerHelper) setDependenciesNotReady(ctx context.Context, o client.Object, reason, message string) error {
+	c, ok := o.(conditionsObject)
+	if !ok {
+		return nil
+	}
+
+	want := getDependenciesReadyCondition(metav1.ConditionFalse, reason, message)
+	if current := meta.FindStatusCondition(*c.GetConditions(), want.Type); current != nil &&
+		current.Status == want.Status && current.Reason == want.Reason && current.Message == want.Message {
+		return nil
+	}
+
+	meta.SetStatusCondition(c.GetConditions(), want)
+	return i.updateStatus(ctx, o)
+}
+
+// updateStatus trims the status to fit the size limit and saves it
+func (i instanceReconcilerHelper) updateStatus(ctx context.Context, o client.Object) error {
+	trimmed, err := trimStatus(o, i.ms)
+	if err != nil {
+		return fmt.Errorf("unable to trim status: %w", err)
+	}
+	if len(trimmed) > 0 {
+		msg := fmt.Sprintf("status exceeds the size limit, trimmed fields: %s", strings.Join(trimmed, ", "))
+		i.log.Info(msg)
+		i.rec.Event(o, corev1.EventTypeWarning, eventStatusTrimmed, msg)
+	}
+
+	// The operator owns the status, so it is saved over the concurrent changes
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := i.k8s.Status().Update(ctx, o)
+		if apierrors.IsConflict(err) {
+			latest := o.DeepCopyObject().(client.Object)
+			if err := i.k8s.Get(ctx, client.ObjectKeyFromObject(o), latest); err != nil {
+				return err
+			}
+			o.SetResourceVersion(latest.GetResourceVersion())
+		}
+		return err
+	})
+}
+
+// updateObject saves the object annotations, the only metadata changed by the operator.
+// On conflict, the operator annotations are reapplied to the latest object, so concurrent changes are kept.
+// Clone is used so update won't overwrite in-memory values
+func (i instanceReconcilerHelper) updateObject(ctx context.Context, o client.Object) error {
+	clone := o.DeepCopyObject().(client.Object)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := i.k8s.Update(ctx, clone)
+		if apierrors.IsConflict(err) {
+			latest := o.DeepCopyObject().(client.Object)
+			if err := i.k8s.Get(ctx, client.ObjectKeyFromObject(o), latest); err != nil {
+				return err
+			}
+			latest.SetAnnotations(reapplyAnnotations(latest.GetAnnotations(), o.GetAnnotations()))
+
+			// The project resolved from projectRef is saved too, it is immutable once set
+			if po, ok := o.(projectObject); ok {
+				latest.(projectObject).SetProject(po.GetProject())
+			}
+			clone = latest
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Original object has been updated
+	o.SetResourceVersion(clone.GetResourceVersion())
+	return nil
+}
+
+// resolveProject sets the project to the Project kind name, which is the Aiven project name.
+// Either project or projectRef must be set, the project set by user must match the reference
+func resolveProject(o client.Object, refs []client.Object) error {
+	po, ok := o.(projectObject)
+	if !ok {
+		return nil
+	}
+
+	if p := v1alpha1.FindProject(refs); p != nil {
+		if project := po.GetProject(); project != "" && project != p.Name {
+			return fmt.Errorf("project %q doesn't match projectRef %q", project, p.Name)
+		}
+		po.SetProject(p.Name)
+	}
+	if po.GetProject() == "" {
+		return errors.New("either project or projectRef must be set")
+	}
+	return nil
+}
+
+// reapplyAnnotations sets the operator annotations over the latest ones, or removes them if they were removed.
+// Other annotations are taken from the latest object
+func reapplyAnnotations(latest, applied map[string]string) map[string]string {
+	result := make(map[string]string, len(latest)+len(operatorAnnotations))
+	for k, v := range latest {
+		result[k] = v
+	}
+	for _, k := range operatorAnnotations {
+		if v, ok := applied[k]; ok {
+			result[k] = v
+		} else {
+			delete(result, k)
+		}
+	}
+	return result
+}
+
 func (i instanceReconcilerHelper) getObjectRefs(ctx context.Context, o client.Object) ([]client.Object, error) {
 	refsObj, ok := o.(refsObject)
 	if !ok {
@@ -318,8 +889,30 @@ func (i instanceReconcilerHelper) getObjectRefs(ctx context.Context, o client.Ob
 // has to be deleted from Kubernetes, and it could be a secret associated with an instance.
 func (i instanceReconcilerHelper) finalize(ctx context.Context, o client.Object) (ctrl.Result, error) {
 	i.rec.Event(o, corev1.EventTypeNormal, eventTryingToDeleteAtAiven, "trying to delete instance at aiven")
+	if err := i.setPhase(ctx, o, v1alpha1.PhaseDeleting); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to set deleting phase: %w", err)
+	}
+
+	protected, err := i.isNamespaceProtected(ctx, o)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to get instance namespace: %w", err)
+	}
+
+	// The instance is never deleted on Aiven side, the user either keeps the resource or orphans it explicitly.
+	// Blocks the deletion visibly, the annotation change triggers the reconcile
+	if protected {
+		if o.GetAnnotations()[orphanAnnotation] != "true" {
+			i.rec.Event(o, corev1.EventTypeWarning, eventUnableToDeleteAtAiven,
+				fmt.Sprintf("%s, annotate it with %s=true to delete the resource and keep the instance at aiven", errNamespaceProtected, orphanAnnotation))
+			return ctrl.Result{}, nil
+		}
+		i.rec.Event(o, corev1.EventTypeWarning, eventOrphaned, "instance namespace is deletion-protected, removing finalizer, the instance is kept at aiven")
+		return ctrl.Result{}, i.orphan(ctx, o)
+	}
 
+	start := time.Now()
 	finalised, err := i.h.delete(i.avn, o)
+	observeHandlerCall(i.kind, "delete", start)
 
 	// There are dependencies on Aiven side, resets error, so it goes for requeue
 	// Handlers does not have logger, it goes here
@@ -328,6 +921,25 @@ func (i instanceReconcilerHelper) finalize(ctx context.Context, o client.Object)
 		err = nil
 	}
 
+	// Dependent resources must be deleted first, goes for requeue
+	if errors.Is(err, errHasDependents) {
+		i.rec.Event(o, corev1.EventTypeWarning, eventWaitingForDependents, err.Error())
+		err = nil
+	}
+
+	// Only the user can disable the protection, the deletion is retried when the instance changes.
+	// Blocks the deletion visibly, so the instance is never abandoned
+	if errors.Is(err, errTerminationProtectionOn) {
+		i.rec.Event(o, corev1.EventTypeWarning, eventUnableToDeleteAtAiven,
+			fmt.Sprintf("%s, disable it to delete the instance", err))
+		return ctrl.Result{}, nil
+	}
+
+	// Gives up when the deletion doesn't succeed in time, so the instance is not stuck forever
+	if !finalised && isFinalizerTimedOut(o, i.ft, time.Now()) {
+		return i.abandon(ctx, o, err)
+	}
+
 	// If the deletion failed, don't remove the finalizer so that we can retry during the next reconciliation.
 	// Unless the error is invalid token and resource is not running, in that case we remove the finalizer
 	// and let the instance be deleted.
@@ -370,6 +982,108 @@ func (i instanceReconcilerHelper) finalize(ctx context.Context, o client.Object)
 	return ctrl.Result{}, nil
 }
 
+// abandon removes the finalizer of the instance which deletion on Aiven side has timed out.
+// The instance might be left on Aiven side, hence it is marked as orphaned
+func (i instanceReconcilerHelper) abandon(ctx context.Context, o client.Object, deleteErr error) (ctrl.Result, error) {
+	msg := fmt.Sprintf("instance is not deleted at aiven in %s, removing finalizer, it may be left at aiven", i.ft)
+	if deleteErr != nil {
+		msg = fmt.Sprintf("%s: %s", msg, deleteErr)
+	}
+	i.log.Info(msg)
+	i.rec.Event(o, corev1.EventTypeWarning, eventFinalizerTimedOut, msg)
+	return ctrl.Result{}, i.orphan(ctx, o)
+}
+
+// orphan marks the instance as orphaned and removes the finalizer without deleting the instance on Aiven side
+func (i instanceReconcilerHelper) orphan(ctx context.Context, o client.Object) error {
+	if err := setStatusOrphaned(o); err != nil {
+		return fmt.Errorf("unable to set orphaned status: %w", err)
+	}
+	if err := i.updateStatus(ctx, o); err != nil {
+		return fmt.Errorf("unable to update status: %w", err)
+	}
+
+	if err := removeFinalizer(ctx, i.k8s, o, instanceDeletionFinalizer); err != nil {
+		i.rec.Event(o, corev1.EventTypeWarning, eventUnableToDeleteFinalizer, err.Error())
+		return fmt.Errorf("unable to remove finalizer: %w", err)
+	}
+	return nil
+}
+
+// isFinalizerTimedOut returns true if the instance has been deleting longer than the timeout
+func isFinalizerTimedOut(o client.Object, timeout time.Duration, now time.Time) bool {
+	deletedAt := o.GetDeletionTimestamp()
+	return timeout > 0 && deletedAt != nil && now.Sub(deletedAt.Time) > timeout
+}
+
+// setStatusOrphaned sets status.orphaned, all the resources have the field
+func setStatusOrphaned(o client.Object) error {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return err
+	}
+
+	status, ok := u["status"].(map[string]interface{})
+	if !ok {
+		status = make(map[string]interface{})
+		u["status"] = status
+	}
+	status["orphaned"] = true
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u, o)
+}
+
+// setStatusPhase sets status.phase, all the resources have the field.
+// Returns true if the phase has changed
+func setStatusPhase(o client.Object, phase v1alpha1.Phase) (bool, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return false, err
+	}
+
+	status, ok := u["status"].(map[string]interface{})
+	if !ok {
+		status = make(map[string]interface{})
+		u["status"] = status
+	}
+	if status["phase"] == string(phase) {
+		return false, nil
+	}
+	status["phase"] = string(phase)
+	return true, runtime.DefaultUnstructuredConverter.FromUnstructured(u, o)
+}
+
+// statusPhase returns status.phase, empty if not set
+func statusPhase(o client.Object) v1alpha1.Phase {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return ""
+	}
+	phase, _, _ := unstructured.NestedString(u, "status", "phase")
+	return v1alpha1.Phase(phase)
+}
+
+// setPhase sets the phase and saves the status if it has changed
+func (i instanceReconcilerHelper) setPhase(ctx context.Context, o client.Object, phase v1alpha1.Phase) error {
+	changed, err := setStatusPhase(o, phase)
+	if err != nil || !changed {
+		return err
+	}
+	return i.updateStatus(ctx, o)
+}
+
+// isNamespaceProtected checks if the instance namespace matches the deletion-protected namespaces selector
+func (i instanceReconcilerHelper) isNamespaceProtected(ctx context.Context, o client.Object) (bool, error) {
+	if i.pns == nil || i.pns.Empty() {
+		return false, nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := i.k8s.Get(ctx, types.NamespacedName{Name: o.GetNamespace()}, ns); err != nil {
+		return false, err
+	}
+	return i.pns.Matches(labels.Set(ns.Labels)), nil
+}
+
 // isInvalidTokenError checks if the error is related to invalid token
 func (i instanceReconcilerHelper) isInvalidTokenError(err error) bool {
 	// When an instance was created but pointing to an invalid API token
@@ -383,7 +1097,14 @@ func (i instanceReconcilerHelper) createOrUpdateInstance(o client.Object, refs [
 	delete(a, processedGenerationAnnotation)
 	delete(a, instanceIsRunningAnnotation)
 
-	if err := i.h.createOrUpdate(i.avn, o, refs); err != nil {
+	if err := i.validateUserConfig(o); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	err := i.h.createOrUpdate(i.avn, o, refs)
+	observeHandlerCall(i.kind, "createOrUpdate", start)
+	if err != nil {
 		return fmt.Errorf("unable to create or update aiven instance: %w", err)
 	}
 
@@ -392,49 +1113,448 @@ func (i instanceReconcilerHelper) createOrUpdateInstance(o client.Object, refs [
 		"generation", o.GetGeneration(),
 		"annotations", o.GetAnnotations(),
 	)
+
+	// Ties the spec change to the moment it was applied
+	i.rec.Eventf(o, corev1.EventTypeNormal, eventGenerationProcessed, "generation %d was applied at aiven", o.GetGeneration())
 	return nil
 }
 
-func (i instanceReconcilerHelper) updateInstanceStateAndSecretUntilRunning(ctx context.Context, o client.Object) (bool, error) {
-	var err error
+// validateUserConfig rejects user config options which are unknown to Aiven, for instance, removed options
+func (i instanceReconcilerHelper) validateUserConfig(o client.Object) error {
+	h, ok := i.h.(userConfigHandler)
+	if i.us == nil || !ok {
+		return nil
+	}
+
+	project, serviceType, userConfig, err := h.getAPIUserConfig(o)
+	if err != nil || len(userConfig) == 0 {
+		return err
+	}
+
+	schema, err := i.us.get(i.avn, project, serviceType, time.Now())
+	if err != nil {
+		return err
+	}
+	return validateUserConfig(schema, userConfig)
+}
 
+func (i instanceReconcilerHelper) updateInstanceStateAndSecretUntilRunning(ctx context.Context, o client.Object) (running bool, rotationDue time.Duration, err error) {
 	i.log.Info("checking if instance is ready")
 
 	defer func() {
+		// The requeue attempt is saved with the object
+		switch {
+		case running:
+			setRequeueAttempt(o, 0)
+		case err == nil:
+			setRequeueAttempt(o, requeueAttempt(o)+1)
+		}
+
+		// Changes on Aiven side, like maintenance, make the running instance updating
+		var phaseErr error
+		switch {
+		case running:
+			_, phaseErr = setStatusPhase(o, v1alpha1.PhaseRunning)
+		case err == nil && statusPhase(o) == v1alpha1.PhaseRunning:
+			_, phaseErr = setStatusPhase(o, v1alpha1.PhaseUpdating)
+		case err != nil && !aiven.IsNotFound(err):
+			_, phaseErr = setStatusPhase(o, v1alpha1.PhaseFailed)
+		}
+		err = multierror.Append(err, phaseErr)
+
 		// Order matters.
 		// First need to update the object, and then update the status.
 		// So dependent resources won't see READY before it has been updated with new values
-		// Clone is used so update won't overwrite in-memory values
-		clone := o.DeepCopyObject().(client.Object)
-		err = multierror.Append(err, i.k8s.Update(ctx, clone))
-
-		// Original object has been updated
-		o.SetResourceVersion(clone.GetResourceVersion())
+		err = multierror.Append(err, i.updateObject(ctx, o))
 
 		// It's ready to cast its status
-		err = multierror.Append(err, i.k8s.Status().Update(ctx, o))
+		err = multierror.Append(err, i.updateStatus(ctx, o))
 		err = err.(*multierror.Error).ErrorOrNil()
 	}()
 
-	serviceSecret, err := i.h.get(i.avn, o)
+	serviceSecret, err := i.get(o)
 	if err != nil {
-		return false, err
-	} else if serviceSecret != nil {
-		if err = i.createOrUpdateSecret(ctx, o, serviceSecret); err != nil {
-			return false, fmt.Errorf("unable to create or update aiven secret: %w", err)
+		return false, 0, err
+	}
+
+	isRunning := IsAlreadyRunning(o)
+	if serviceSecret == nil {
+		return isRunning, 0, nil
+	}
+
+	// The secret is emitted only when the instance is running,
+	// so applications won't pick up credentials of an instance that is not ready yet
+	if !isRunning {
+		// Polled until running, the event goes once per deferral
+		if setSecretEmitted(o, metav1.ConditionFalse, "InstanceNotRunning", "instance is not running yet, secret emission is deferred") {
+			i.rec.Event(o, corev1.EventTypeNormal, eventSecretEmissionDeferred, "instance is not running yet, secret emission is deferred")
 		}
+		return false, 0, nil
 	}
-	return IsAlreadyRunning(o), nil
 
+	// Config map and scrape config go first, secret's data is read here.
+	// The secret write moves the data from StringData to Data
+	if err = i.createOrUpdateConfigMap(ctx, o, serviceSecret); err != nil {
+		return false, 0, fmt.Errorf("unable to create or update config map: %w", err)
+	}
+	if err = i.createOrUpdateScrapeConfig(ctx, o, serviceSecret); err != nil {
+		return false, 0, fmt.Errorf("unable to create or update scrape config: %w", err)
+	}
+	err = i.createOrUpdateSecret(ctx, o, serviceSecret)
+	if errors.Is(err, errSecretNotOwned) {
+		i.rec.Event(o, corev1.EventTypeWarning, eventSecretNotOwned, err.Error())
+	}
+	if err != nil {
+		return false, 0, fmt.Errorf("unable to create or update aiven secret: %w", err)
+	}
+	if err = i.deleteRenamedSecret(ctx, o, serviceSecret.Name); err != nil {
+		return false, 0, fmt.Errorf("unable to delete renamed secret: %w", err)
+	}
+	setSecretEmitted(o, metav1.ConditionTrue, "InstanceRunning", "secret is emitted")
+	return true, caRotationDue(serviceSecret, time.Now()), nil
+}
+
+// get calls handler's get unless there is a cached result for the running instance
+func (i instanceReconcilerHelper) get(o client.Object) (*corev1.Secret, error) {
+	now := time.Now()
+	if secret, ok := i.gc.load(o, now); ok {
+		i.log.Info("using cached instance state")
+		return secret, nil
+	}
+
+	start := time.Now()
+	secret, err := i.h.get(i.avn, o)
+	observeHandlerCall(i.kind, "get", start)
+	if err != nil {
+		return nil, err
+	}
+	i.gc.store(o, secret, now)
+	return secret, nil
 }
 
 func (i instanceReconcilerHelper) createOrUpdateSecret(ctx context.Context, owner client.Object, want *corev1.Secret) error {
+	gvk, err := apiutil.GVKForObject(owner, i.k8s.Scheme())
+	if err != nil {
+		return err
+	}
+
+	// CreateOrUpdate overwrites the object with the existing secret, keeps the desired data
+	desired := make(map[string]string, len(want.Data)+len(want.StringData))
+	for k, v := range want.Data {
+		desired[k] = string(v)
+	}
+	for k, v := range want.StringData {
+		desired[k] = v
+	}
+
+	var target v1alpha1.ConnInfoSecretTarget
+	if t, ok := owner.(connInfoSecretTargetObject); ok {
+		target = t.GetConnInfoSecretTarget()
+	}
+	for _, k := range unknownKeyNames(desired, target.KeyNames) {
+		i.rec.Eventf(owner, corev1.EventTypeWarning, eventUnknownSecretKey,
+			"connInfoSecretTarget.keyNames renames %q, which is not in the secret", k)
+	}
+
+	_, err = controllerutil.CreateOrUpdate(ctx, i.k8s, want, func() error {
+		// The secret exists, for instance, created by a user or another resource
+		if want.ResourceVersion != "" && !isSecretOwnedBy(want, owner) {
+			// Another resource emits the same data, like a service and its read replica sharing the credentials.
+			// The secret is left untouched, so the owners don't overwrite each other
+			if hasSecretData(want.Data, withKeyNames(withKeyEncodings(desired, target.KeyEncodings), target.KeyNames)) {
+				return nil
+			}
+			if !i.as {
+				return fmt.Errorf("%w: %s/%s", errSecretNotOwned, want.Namespace, want.Name)
+			}
+		}
+
+		// The stored keys are renamed back, so the rotation compares the values under the default key names
+		want.Data = withKeyNames(want.Data, reverseKeyNames(target.KeyNames))
+
+		// Encodes before the rotation, which compares the values with the stored ones
+		now := time.Now()
+		encoded := withKeyEncodings(desired, target.KeyEncodings)
+		rotated := withPreviousCredentials(want, withCARotation(want, encoded, now), target.PreviousCredentialsGracePeriod, now)
+		data := secretData(want.Data, rotated, target.UpdateStrategy)
+		want.Data = withKeyNames(data, target.KeyNames)
+		want.StringData = nil
+
+		labels := want.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[secretOwnerUIDLabel] = string(owner.GetUID())
+		labels[secretOwnerKindLabel] = gvk.Kind
+		want.SetLabels(labels)
+		return ctrl.SetControllerReference(owner, want, i.k8s.Scheme())
+	})
+	return err
+}
+
+// isSecretOwnedBy returns true if the secret is controlled by the owner or labeled with its UID
+func isSecretOwnedBy(secret *corev1.Secret, owner client.Object) bool {
+	if ref := metav1.GetControllerOf(secret); ref != nil {
+		return ref.UID == owner.GetUID()
+	}
+	return owner.GetUID() != "" && secret.GetLabels()[secretOwnerUIDLabel] == string(owner.GetUID())
+}
+
+// hasSecretData returns true if the secret has all the data keys with the same values, other keys are ignored
+func hasSecretData(current map[string][]byte, data map[string]string) bool {
+	for k, v := range data {
+		c, ok := current[k]
+		if !ok || string(c) != v {
+			return false
+		}
+	}
+	return true
+}
+
+// secretData returns the secret data according to the update strategy.
+// The "merge" strategy keeps the keys which are not managed by the operator
+func secretData(current map[string][]byte, desired map[string]string, strategy string) map[string][]byte {
+	data := make(map[string][]byte, len(desired))
+	if strategy == secretUpdateStrategyMerge {
+		for k, v := range current {
+			data[k] = v
+		}
+		// Managed by the operator, but might be not desired anymore
+		delete(data, caCertNextKey)
+		for k := range data {
+			if strings.HasSuffix(k, previousCredentialSuffix) {
+				delete(data, k)
+			}
+		}
+	}
+	for k, v := range desired {
+		data[k] = []byte(v)
+	}
+	return data
+}
+
+// withKeyEncodings returns a copy of the secret data with the values of the base64 encoded keys encoded.
+// CA_CERT_NEXT gets the CA_CERT encoding
+func withKeyEncodings(data map[string]string, encodings []v1alpha1.SecretKeyEncoding) map[string]string {
+	if len(encodings) == 0 {
+		return data
+	}
+
+	result := make(map[string]string, len(data))
+	for k, v := range data {
+		result[k] = v
+	}
+	for _, e := range encodings {
+		v, ok := data[e.Key]
+		if ok && e.Encoding == secretKeyEncodingBase64 {
+			result[e.Key] = base64.StdEncoding.EncodeToString([]byte(v))
+		}
+	}
+	return result
+}
+
+// withKeyNames returns a copy of the data with the keys renamed, the keys which are not in names are kept
+func withKeyNames[T any](data map[string]T, names map[string]string) map[string]T {
+	if len(names) == 0 {
+		return data
+	}
+
+	result := make(map[string]T, len(data))
+	for k, v := range data {
+		if name, ok := names[k]; ok {
+			k = name
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// reverseKeyNames maps the renamed keys back to the default ones
+func reverseKeyNames(names map[string]string) map[string]string {
+	result := make(map[string]string, len(names))
+	for k, v := range names {
+		result[v] = k
+	}
+	return result
+}
+
+// unknownKeyNames returns the sorted renamed keys missing in the secret data.
+// CA_CERT_NEXT and the _PREVIOUS keys are there only while rotated, hence never reported
+func unknownKeyNames(data map[string]string, names map[string]string) []string {
+	var unknown []string
+	for k := range names {
+		if _, ok := data[k]; !ok && k != caCertNextKey && !strings.HasSuffix(k, previousCredentialSuffix) {
+			unknown = append(unknown, k)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// withCARotation publishes the current and the new CA bundled in CA_CERT for caRotationPeriod,
+// so clients which read CA_CERT only trust both while Aiven rotates the CA. The new CA alone is in CA_CERT_NEXT.
+// Then collapses back to the new CA in CA_CERT only. Rotation start time is stored in the secret annotation
+func withCARotation(secret *corev1.Secret, desired map[string]string, now time.Time) map[string]string {
+	currentCA := string(secret.Data[caCertKey])
+	nextCA := string(secret.Data[caCertNextKey])
+	newCA := desired[caCertKey]
+	started, _ := time.Parse(time.RFC3339, secret.GetAnnotations()[caRotationStartedAnnotation])
+
+	bundle := currentCA
+	switch {
+	case newCA != "" && currentCA != "" && currentCA != newCA && nextCA != newCA:
+		// A new rotation has started. If the previous one is in progress, its new CA is the current one
+		started = now
+		oldCA := currentCA
+		if nextCA != "" {
+			oldCA = nextCA
+		}
+		bundle = strings.TrimRight(oldCA, "\n") + "\n" + newCA
+	case newCA != "" && nextCA == newCA && now.Sub(started) < caRotationPeriod:
+		// Rotation is in progress
+	default:
+		// No rotation, or it is complete
+		delete(secret.GetAnnotations(), caRotationStartedAnnotation)
+		return desired
+	}
+
+	data := make(map[string]string, len(desired)+1)
+	for k, v := range desired {
+		data[k] = v
+	}
+	data[caCertKey] = bundle
+	data[caCertNextKey] = newCA
+	metav1.SetMetaDataAnnotation(&secret.ObjectMeta, caRotationStartedAnnotation, started.Format(time.RFC3339))
+	return data
+}
+
+// caRotationDue returns when the CA rotation of the written secret collapses, zero if there is no rotation
+func caRotationDue(secret *corev1.Secret, now time.Time) time.Duration {
+	started, err := time.Parse(time.RFC3339, secret.GetAnnotations()[caRotationStartedAnnotation])
+	if err != nil {
+		return 0
+	}
+	// Rounded up to the annotation precision, so the requeue never comes early
+	if due := started.Add(caRotationPeriod).Sub(now) + time.Second; due > 0 {
+		return due
+	}
+	return time.Second
+}
+
+// isCredentialKey returns true for the secret keys with credentials, like PASSWORD or PGPASSWORD
+func isCredentialKey(k string) bool {
+	return strings.HasSuffix(k, "PASSWORD") || k == "ACCESS_KEY" || k == "ACCESS_CERT"
+}
+
+// withPreviousCredentials keeps the replaced credentials under the _PREVIOUS suffixed keys for the grace period,
+// so clients can use the old credentials for a while after the rotation. Disabled if the period is not set.
+// The rotation time is stored in the secret annotation, a new rotation restarts the period of all the keys
+func withPreviousCredentials(secret *corev1.Secret, desired map[string]string, period *metav1.Duration, now time.Time) map[string]string {
+	if period == nil || period.Duration <= 0 {
+		delete(secret.GetAnnotations(), credentialsRotatedAnnotation)
+		return desired
+	}
+
+	rotated, _ := time.Parse(time.RFC3339, secret.GetAnnotations()[credentialsRotatedAnnotation])
+	previous := make(map[string]string)
+	for k, v := range desired {
+		if !isCredentialKey(k) {
+			continue
+		}
+		if current := string(secret.Data[k]); current != "" && current != v {
+			// A new rotation has started
+			previous[k+previousCredentialSuffix] = current
+			rotated = now
+		} else if p := string(secret.Data[k+previousCredentialSuffix]); p != "" {
+			previous[k+previousCredentialSuffix] = p
+		}
+	}
+
+	if len(previous) == 0 || now.Sub(rotated) >= period.Duration {
+		// No rotation, or the grace period is over
+		delete(secret.GetAnnotations(), credentialsRotatedAnnotation)
+		return desired
+	}
+
+	data := make(map[string]string, len(desired)+len(previous))
+	for k, v := range desired {
+		data[k] = v
+	}
+	for k, v := range previous {
+		data[k] = v
+	}
+	metav1.SetMetaDataAnnotation(&secret.ObjectMeta, credentialsRotatedAnnotation, rotated.Format(time.RFC3339))
+	return data
+}
+
+// deleteRenamedSecret deletes the secret created for the previous connInfoSecretTarget name.
+// The last secret name is tracked in the annotation.
+// Instances created before the annotation had the default secret name, which is the instance name
+func (i instanceReconcilerHelper) deleteRenamedSecret(ctx context.Context, owner client.Object, name string) error {
+	prev, ok := owner.GetAnnotations()[secretNameAnnotation]
+	if !ok {
+		prev = owner.GetName()
+	}
+	if prev != "" && prev != name {
+		old := &corev1.Secret{}
+		err := i.k8s.Get(ctx, types.NamespacedName{Name: prev, Namespace: owner.GetNamespace()}, old)
+		if client.IgnoreNotFound(err) != nil {
+			return err
+		}
+
+		// Never touches secrets that are not created by the operator
+		if err == nil && metav1.IsControlledBy(old, owner) {
+			i.log.Info("deleting renamed secret", "secret", prev)
+			if err = i.k8s.Delete(ctx, old); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+	}
+
+	a := owner.GetAnnotations()
+	if a == nil {
+		a = make(map[string]string)
+	}
+	a[secretNameAnnotation] = name
+	owner.SetAnnotations(a)
+	return nil
+}
+
+// createOrUpdateConfigMap copies non-sensitive connection info from the secret to the config map,
+// if the owner has a config map target
+func (i instanceReconcilerHelper) createOrUpdateConfigMap(ctx context.Context, owner client.Object, secret *corev1.Secret) error {
+	o, ok := owner.(connInfoConfigMapObject)
+	if !ok || o.GetConnInfoConfigMapTarget() == nil {
+		return nil
+	}
+
+	want := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      o.GetConnInfoConfigMapTarget().Name,
+			Namespace: owner.GetNamespace(),
+		},
+	}
 	_, err := controllerutil.CreateOrUpdate(ctx, i.k8s, want, func() error {
+		want.Data = nonSensitiveConnInfo(secret)
 		return ctrl.SetControllerReference(owner, want, i.k8s.Scheme())
 	})
 	return err
 }
 
+// nonSensitiveConnInfo returns secret fields with hosts, ports, database names and ssl settings
+func nonSensitiveConnInfo(s *corev1.Secret) map[string]string {
+	data := make(map[string]string)
+	for k, v := range s.StringData {
+		for _, suffix := range []string{"HOST", "HOSTS", "PORT", "DATABASE", "SSL", "SSLMODE", "SSL_MODE"} {
+			if strings.HasSuffix(k, suffix) {
+				data[k] = v
+				break
+			}
+		}
+	}
+	return data
+}
+
 func setupLogger(log logr.Logger, o client.Object) logr.Logger {
 	a := make(map[string]string)
 	if r, ok := o.GetAnnotations()[instanceIsRunningAnnotation]; ok {
@@ -451,7 +1571,8 @@ func setupLogger(log logr.Logger, o client.Object) logr.Logger {
 }
 
 // UserConfigurationToAPI converts UserConfiguration options structure
-// to Aiven API compatible map[string]interface{}
+// to Aiven API compatible map[string]interface{}.
+// Presence is decided by the pointers: nil pointers are omitted, the others are sent even if zero, like false or 0
 func UserConfigurationToAPI(c interface{}) interface{} {
 	result := make(map[string]interface{})
 
@@ -463,11 +1584,15 @@ func UserConfigurationToAPI(c interface{}) interface{} {
 	}
 
 	if v.Kind() != reflect.Struct {
+		// v is already resolved, so scalars can be either values or pointers
 		switch v.Kind() {
-		case reflect.Int64:
-			return *c.(*int64)
-		case reflect.Bool:
-			return *c.(*bool)
+		case reflect.Invalid:
+			// nil pointer
+			return nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64, reflect.Bool, reflect.String:
+			return v.Interface()
 		default:
 			return c
 		}
@@ -475,28 +1600,34 @@ func UserConfigurationToAPI(c interface{}) interface{} {
 
 	structType := v.Type()
 
-	// convert UserConfig structure to a map
+	// convert UserConfig structure to a map,
+	// nested structs and pointers to structs are converted recursively
 	for i := 0; i < structType.NumField(); i++ {
-		name := strings.ReplaceAll(structType.Field(i).Tag.Get("json"), ",omitempty", "")
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
 
-		if structType.Kind() == reflect.Struct {
-			result[name] = UserConfigurationToAPI(v.Field(i).Interface())
-		} else {
-			result[name] = v.Elem().Field(i).Interface()
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
 		}
-	}
 
-	// remove all the nil and empty map data
-	for key, val := range result {
-		if val == nil || isNil(val) || val == "" {
-			delete(result, key)
+		// Empty string pointer is a valid value, unlike a plain empty string
+		f := v.Field(i)
+		if isNil(f.Interface()) || (f.Kind() == reflect.String && f.Len() == 0) {
+			continue
 		}
 
-		if reflect.TypeOf(val).Kind() == reflect.Map {
-			if len(val.(map[string]interface{})) == 0 {
-				delete(result, key)
-			}
+		// A plain nested struct without options is omitted, a pointer to it is an explicitly empty object
+		val := UserConfigurationToAPI(f.Interface())
+		if m, ok := val.(map[string]interface{}); ok && len(m) == 0 && f.Kind() == reflect.Struct {
+			continue
 		}
+		result[name] = val
 	}
 
 	return result
@@ -528,6 +1659,108 @@ func UserConfigurationToAPIV2(userConfig interface{}, groups []string) (map[stri
 	return m, nil
 }
 
+// userConfigChanges returns top level options of the desired user config
+// which are missing or have different values in the live config.
+// Nested objects are compared and sent as a whole.
+func userConfigChanges(desired, live map[string]interface{}) (map[string]interface{}, error) {
+	if len(desired) == 0 {
+		return nil, nil
+	}
+
+	// Normalizes types, so numbers can be compared with values received from the API
+	normalized, err := normalizeUserConfig(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]interface{})
+	for k, v := range normalized {
+		lv, ok := live[k]
+		if v == nil && !ok {
+			// The removed option is not set on Aiven side already
+			continue
+		}
+		if !ok || !reflect.DeepEqual(v, lv) {
+			changes[k] = desired[k]
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return changes, nil
+}
+
+// withRemovedUserConfig sets nil to the options of the last applied user config which are missing in the desired one,
+// so Aiven unsets them. Nested objects are compared recursively
+func withRemovedUserConfig(desired, lastApplied map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(desired))
+	for k, v := range desired {
+		result[k] = v
+	}
+
+	for k, lv := range lastApplied {
+		v, ok := desired[k]
+		if !ok {
+			result[k] = nil
+			continue
+		}
+
+		lm, okLast := lv.(map[string]interface{})
+		m, okDesired := v.(map[string]interface{})
+		if okLast && okDesired {
+			result[k] = withRemovedUserConfig(m, lm)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// getLastAppliedUserConfig returns the user config stored in the annotation
+func getLastAppliedUserConfig(o client.Object) (map[string]interface{}, error) {
+	v, ok := o.GetAnnotations()[lastAppliedUserConfigAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(v), &m); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", lastAppliedUserConfigAnnotation, err)
+	}
+	return m, nil
+}
+
+// setLastAppliedUserConfig stores the user config in the annotation
+func setLastAppliedUserConfig(o client.Object, userConfig map[string]interface{}) error {
+	b, err := json.Marshal(userConfig)
+	if err != nil {
+		return err
+	}
+
+	a := o.GetAnnotations()
+	if a == nil {
+		a = make(map[string]string)
+	}
+	a[lastAppliedUserConfigAnnotation] = string(b)
+	o.SetAnnotations(a)
+	return nil
+}
+
+// normalizeUserConfig converts the map into the same types json decoder would produce
+func normalizeUserConfig(m map[string]interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	err = json.Unmarshal(b, &result)
+	return result, err
+}
+
 func isNil(i interface{}) bool {
 	if i == nil {
 		return true
@@ -576,3 +1809,21 @@ func ensureSecretDataIsNotEmpty(log *logr.Logger, s *corev1.Secret) *corev1.Secr
 
 	return s
 }
+
+// creationDelay returns how long the first reconcile of the new instance is still deferred,
+// counting from the instance creation
+func creationDelay(o client.Object, now time.Time) time.Duration {
+	p, ok := o.(creationDelayObject)
+	if !ok || p.GetCreationDelay() <= 0 {
+		return 0
+	}
+	if _, processed := o.GetAnnotations()[processedGenerationAnnotation]; processed {
+		return 0
+	}
+
+	deadline := o.GetCreationTimestamp().Add(time.Duration(p.GetCreationDelay()) * time.Second)
+	if d := deadline.Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
@@ -11,7 +11,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
 )
@@ -26,18 +28,20 @@ type CassandraReconciler struct {
 // +kubebuilder:rbac:groups=aiven.io,resources=cassandras/finalizers,verbs=update
 
 func (r *CassandraReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newCassandraAdapter), &v1alpha1.Cassandra{})
+	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newCassandraAdapter, r.Client, r.Recorder), &v1alpha1.Cassandra{})
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *CassandraReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Cassandra{}).
+		For(&v1alpha1.Cassandra{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, ipFilterConfigMapHandler(r.Client, newCassandraAdapter, &v1alpha1.CassandraList{})).
 		Owns(&corev1.Secret{}).
+		Owns(&corev1.ConfigMap{}).
 		Complete(r)
 }
 
-func newCassandraAdapter(_ *aiven.Client, object client.Object) (serviceAdapter, error) {
+func newCassandraAdapter(_ AivenClient, object client.Object) (serviceAdapter, error) {
 	cassandra, ok := object.(*v1alpha1.Cassandra)
 	if !ok {
 		return nil, fmt.Errorf("object is not of type v1alpha1.Cassandra")
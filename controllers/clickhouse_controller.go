@@ -10,7 +10,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
 )
@@ -25,18 +27,20 @@ type ClickhouseReconciler struct {
 //+kubebuilder:rbac:groups=aiven.io,resources=clickhouses/finalizers,verbs=update
 
 func (r *ClickhouseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newClickhouseAdapter), &v1alpha1.Clickhouse{})
+	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newClickhouseAdapter, r.Client, r.Recorder), &v1alpha1.Clickhouse{})
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClickhouseReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Clickhouse{}).
+		For(&v1alpha1.Clickhouse{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, ipFilterConfigMapHandler(r.Client, newClickhouseAdapter, &v1alpha1.ClickhouseList{})).
 		Owns(&corev1.Secret{}).
+		Owns(&corev1.ConfigMap{}).
 		Complete(r)
 }
 
-func newClickhouseAdapter(_ *aiven.Client, object client.Object) (serviceAdapter, error) {
+func newClickhouseAdapter(_ AivenClient, object client.Object) (serviceAdapter, error) {
 	clickhouse, ok := object.(*v1alpha1.Clickhouse)
 	if !ok {
 		return nil, fmt.Errorf("object is not of type v1alpha1.Clickhouse")
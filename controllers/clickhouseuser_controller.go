@@ -11,10 +11,10 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	"github.com/aiven/aiven-go-client"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -52,17 +52,20 @@ func (r *ClickhouseUserReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	r.Controller.Recorder.Event(user, corev1.EventTypeNormal, eventReconciliationStarted, "starting reconciliation")
 
-	clientAuthSecret := &corev1.Secret{}
-	if err := r.Controller.Get(ctx, types.NamespacedName{Name: user.AuthSecretRef().Name, Namespace: req.Namespace}, clientAuthSecret); err != nil {
-		r.Controller.Recorder.Eventf(user, corev1.EventTypeWarning, eventUnableToGetAuthSecret, err.Error())
-		return ctrl.Result{}, fmt.Errorf("cannot get secret %q: %w", user.AuthSecretRef().Name, err)
+	token, _, err := resolveAuthToken(ctx, r.Client, user, r.authTokens)
+	if err != nil {
+		r.Controller.Recorder.Event(user, corev1.EventTypeWarning, authTokenEventReason(err), err.Error())
+		return ctrl.Result{}, err
 	}
 
-	avn, err := aiven.NewTokenClient(string(clientAuthSecret.Data[user.AuthSecretRef().Key]), operatorUserAgent)
+	avn, err := r.Controller.aivenClient(token)
 	if err != nil {
 		r.Controller.Recorder.Event(user, corev1.EventTypeWarning, eventUnableToCreateClient, err.Error())
 		return ctrl.Result{}, fmt.Errorf("cannot initialize aiven client: %w", err)
 	}
+	if isDebugEnabled(user) {
+		avn = withDebugLog(avn, log.WithValues("debug", true))
+	}
 
 	// check if the clickhouse User instance is marked to be deleted, which is
 	// indicated by the deletion timestamp being set.
@@ -74,7 +77,7 @@ func (r *ClickhouseUserReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			// that we can retry during the next reconciliation.
 			r.Controller.Recorder.Event(user, corev1.EventTypeNormal, eventTryingToDeleteAtAiven, "deleting clickhouse user on aiven side")
 			if len(user.Status.UUID) > 0 {
-				if err := avn.ClickhouseUser.Delete(user.Spec.Project, user.Spec.ServiceName, user.Status.UUID); err != nil {
+				if err := avn.ClickhouseUser().Delete(user.Spec.Project, user.Spec.ServiceName, user.Status.UUID); err != nil {
 					r.Controller.Recorder.Event(user, corev1.EventTypeWarning, eventUnableToDeleteAtAiven, err.Error())
 					return reconcile.Result{}, err
 				}
@@ -124,23 +127,29 @@ func (r *ClickhouseUserReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, err
 	}
 
-	if !isAlreadyProcessed(user) {
-		if len(uuid) > 0 {
-			pass, err := r.generatePassword()
-			if err != nil {
-				log.Error(err, "failed to generate a clickhouse user password")
-				return ctrl.Result{}, err
-			}
+	// The password can't be read back, so it is reset when the secret is deleted or emptied
+	secretMissing, err := isSecretMissing(ctx, r.Client, user.Namespace, r.getSecretName(user))
+	if err != nil {
+		log.Error(err, "failed to get a clickhouse user secret")
+		return ctrl.Result{}, err
+	}
 
-			p, err := avn.ClickhouseUser.ResetPassword(user.Spec.Project, user.Spec.ServiceName, uuid, pass)
+	if !isAlreadyProcessed(user) || secretMissing {
+		// Never changes the password, which can't be written to the secret
+		if err := r.checkSecretOwner(ctx, user); err != nil {
+			log.Error(err, "failed to create a clickhouse user secret")
+			return ctrl.Result{}, err
+		}
+
+		if len(uuid) > 0 {
+			password, err = r.getOrResetPassword(ctx, avn, user, uuid)
 			if err != nil {
 				r.Controller.Recorder.Event(user, corev1.EventTypeWarning, eventUnableToCreateOrUpdateAtAiven, err.Error())
 				log.Error(err, "failed to reset clickhouse user password")
 				return ctrl.Result{}, err
 			}
-			password = p
 		} else {
-			u, err := avn.ClickhouseUser.Create(user.Spec.Project, user.Spec.ServiceName, user.Name)
+			u, err := avn.ClickhouseUser().Create(user.Spec.Project, user.Spec.ServiceName, user.Name)
 			if err != nil {
 				r.Controller.Recorder.Event(user, corev1.EventTypeWarning, eventUnableToCreateOrUpdateAtAiven, err.Error())
 				log.Error(err, "failed to create a clickhouse user")
@@ -171,8 +180,20 @@ func (r *ClickhouseUserReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			return ctrl.Result{}, err
 		}
 
+		// The annotations are not saved with the status
+		h := instanceReconcilerHelper{k8s: r.Client, log: log}
+		if err = h.deleteRenamedSecret(ctx, user, r.getSecretName(user)); err != nil {
+			log.Error(err, "failed to delete a renamed clickhouse user secret")
+			return ctrl.Result{}, err
+		}
+		if err = h.updateObject(ctx, user); err != nil {
+			log.Error(err, "failed to update a clickhouse user cr")
+			return ctrl.Result{}, err
+		}
+
 		// updating clickhouse user resource status
 		user.Status.UUID = uuid
+		user.Status.Phase = v1alpha1.PhaseRunning
 		err = r.Status().Update(context.Background(), user)
 		if err != nil {
 			log.Error(err, "failed to update a clickhouse user cr status")
@@ -193,8 +214,58 @@ func (*ClickhouseUserReconciler) generatePassword() (string, error) {
 	return fmt.Sprintf("%x", b), err
 }
 
-func (r *ClickhouseUserReconciler) createSecret(ctx context.Context, avn *aiven.Client, user *v1alpha1.ClickhouseUser, password string) error {
-	s, err := avn.Services.Get(user.Spec.Project, user.Spec.ServiceName)
+// getOrResetPassword returns the password kept in the secret, or in the renamed one.
+// The password is reset only if neither of them has it
+func (r *ClickhouseUserReconciler) getOrResetPassword(ctx context.Context, avn AivenClient, user *v1alpha1.ClickhouseUser, uuid string) (string, error) {
+	for _, name := range []string{r.getSecretName(user), user.GetAnnotations()[secretNameAnnotation]} {
+		if name == "" {
+			continue
+		}
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: user.Namespace}, secret)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if p := secret.Data["PASSWORD"]; len(p) > 0 && isSecretOwnedBy(secret, user) {
+			return string(p), nil
+		}
+	}
+
+	pass, err := r.generatePassword()
+	if err != nil {
+		return "", err
+	}
+	return avn.ClickhouseUser().ResetPassword(user.Spec.Project, user.Spec.ServiceName, uuid, pass)
+}
+
+// checkSecretOwner refuses to overwrite the existing secret, which is not owned by the user, unless secrets are adopted
+func (r *ClickhouseUserReconciler) checkSecretOwner(ctx context.Context, user *v1alpha1.ClickhouseUser) error {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Name: r.getSecretName(user), Namespace: user.Namespace}, secret)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return r.secretOwnerError(secret, user)
+}
+
+// secretOwnerError returns errSecretNotOwned for the existing secret, which can't be overwritten
+func (r *ClickhouseUserReconciler) secretOwnerError(secret *corev1.Secret, user *v1alpha1.ClickhouseUser) error {
+	if secret.ResourceVersion != "" && !r.adoptSecrets && !isSecretOwnedBy(secret, user) {
+		err := fmt.Errorf("%w: %s/%s", errSecretNotOwned, secret.Namespace, secret.Name)
+		r.Controller.Recorder.Event(user, corev1.EventTypeWarning, eventSecretNotOwned, err.Error())
+		return err
+	}
+	return nil
+}
+
+func (r *ClickhouseUserReconciler) createSecret(ctx context.Context, avn AivenClient, user *v1alpha1.ClickhouseUser, password string) error {
+	s, err := avn.Services().Get(user.Spec.Project, user.Spec.ServiceName)
 	if err != nil {
 		return fmt.Errorf("cannot get a clickhouse service %w", err)
 	}
@@ -213,15 +284,22 @@ func (r *ClickhouseUserReconciler) createSecret(ctx context.Context, avn *aiven.
 			"USERNAME": user.Name,
 		},
 	}
+	// CreateOrUpdate overwrites the object with the existing secret, keeps the desired data
+	desired := secret.StringData
 	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if err := r.secretOwnerError(secret, user); err != nil {
+			return err
+		}
+		secret.Data = secretData(secret.Data, desired, user.Spec.ConnInfoSecretTarget.UpdateStrategy)
+		secret.StringData = nil
 		return ctrl.SetControllerReference(user, secret, r.Scheme)
 	})
 
 	return err
 }
 
-func (*ClickhouseUserReconciler) isAlreadyExists(avn *aiven.Client, user *v1alpha1.ClickhouseUser) (string, error) {
-	l, err := avn.ClickhouseUser.List(user.Spec.Project, user.Spec.ServiceName)
+func (*ClickhouseUserReconciler) isAlreadyExists(avn AivenClient, user *v1alpha1.ClickhouseUser) (string, error) {
+	l, err := avn.ClickhouseUser().List(user.Spec.Project, user.Spec.ServiceName)
 	if err != nil {
 		return "", err
 	}
@@ -235,7 +313,7 @@ func (*ClickhouseUserReconciler) isAlreadyExists(avn *aiven.Client, user *v1alph
 	return "", nil
 }
 
-func checkPreconditions(avn *aiven.Client, user *v1alpha1.ClickhouseUser) (bool, error) {
+func checkPreconditions(avn AivenClient, user *v1alpha1.ClickhouseUser) (bool, error) {
 	meta.SetStatusCondition(&user.Status.Conditions,
 		getInitializedCondition("Preconditions", "Checking preconditions"))
 
@@ -252,7 +330,7 @@ func (r *ClickhouseUserReconciler) getSecretName(user *v1alpha1.ClickhouseUser)
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClickhouseUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.ClickhouseUser{}).
+		For(&v1alpha1.ClickhouseUser{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
 		Owns(&corev1.Secret{}).
 		Complete(r)
 }
@@ -4,13 +4,22 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"os"
+	"testing"
 	"time"
 
+	"github.com/aiven/aiven-go-client"
+	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
 
@@ -110,3 +119,138 @@ func clickhouseUserSpec(service, user, namespace string) *v1alpha1.ClickhouseUse
 		},
 	}
 }
+
+func Test_clickhouseUserPassword(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	newUser := func() *v1alpha1.ClickhouseUser {
+		user := &v1alpha1.ClickhouseUser{ObjectMeta: metav1.ObjectMeta{Name: "user", Namespace: "default", UID: "user-uid", Generation: 1}}
+		user.Spec.Project = "my-project"
+		user.Spec.ServiceName = "my-clickhouse"
+		user.Spec.AuthSecretRef = &v1alpha1.AuthSecretReference{Name: "aiven-token", Key: "token"}
+		return user
+	}
+	ownedSecret := func(name, password string) *corev1.Secret {
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Data:       map[string][]byte{"PASSWORD": []byte(password)},
+		}
+		if err := ctrl.SetControllerReference(newUser(), s, scheme); err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	token := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "aiven-token", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("token")},
+	}
+
+	renamed := newUser()
+	renamed.Spec.ConnInfoSecretTarget.Name = "new-secret"
+	renamed.Annotations = map[string]string{secretNameAnnotation: "user"}
+
+	tests := []struct {
+		name         string
+		user         *v1alpha1.ClickhouseUser
+		objects      []client.Object
+		wantPassword string
+		wantReset    bool
+		wantErr      error
+		wantDeleted  string
+	}{
+		{
+			name:         "password is kept in the secret",
+			user:         newUser(),
+			objects:      []client.Object{ownedSecret("user", "kept")},
+			wantPassword: "kept",
+		},
+		{
+			name:         "password is kept from the renamed secret",
+			user:         renamed,
+			objects:      []client.Object{ownedSecret("user", "kept")},
+			wantPassword: "kept",
+			wantDeleted:  "user",
+		},
+		{
+			name:         "password is reset without the secret",
+			user:         newUser(),
+			wantPassword: "reset",
+			wantReset:    true,
+		},
+		{
+			name: "password is not reset if the secret is not owned",
+			user: newUser(),
+			objects: []client.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "user", Namespace: "default"},
+			}},
+			wantErr: errSecretNotOwned,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(tt.objects, token, tt.user)...).Build()
+			resets := 0
+			avn := &mockAivenClient{
+				services: &mockServices{GetFunc: func(project, service string) (*aiven.Service, error) {
+					return &aiven.Service{Name: service, State: "RUNNING", URIParams: map[string]string{"host": "host", "port": "1234"}}, nil
+				}},
+				clickhouseUser: &mockClickhouseUser{
+					ListFunc: func(project, service string) (*aiven.ListClickhouseUserResponse, error) {
+						return &aiven.ListClickhouseUserResponse{Users: []aiven.ClickhouseUser{{Name: "user", UUID: "uuid"}}}, nil
+					},
+					ResetPasswordFunc: func(project, service, uuid, password string) (string, error) {
+						resets++
+						return "reset", nil
+					},
+				},
+			}
+			r := &ClickhouseUserReconciler{Controller: Controller{
+				Client:         k8s,
+				Log:            logr.Discard(),
+				Scheme:         scheme,
+				Recorder:       record.NewFakeRecorder(100),
+				newAivenClient: func(string) (AivenClient, error) { return avn, nil },
+			}}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "user", Namespace: "default"}}
+
+			_, err := r.Reconcile(context.Background(), req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Reconcile() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if resets != 0 {
+					t.Error("password is reset, but can't be written to the secret")
+				}
+				return
+			}
+
+			// Next reconcile doesn't reset the password again
+			if _, err = r.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if wantResets := map[bool]int{true: 1}[tt.wantReset]; resets != wantResets {
+				t.Errorf("password resets = %d, want %d", resets, wantResets)
+			}
+
+			secret := &corev1.Secret{}
+			if err = k8s.Get(context.Background(), types.NamespacedName{Name: r.getSecretName(tt.user), Namespace: "default"}, secret); err != nil {
+				t.Fatal(err)
+			}
+			if got := string(secret.Data["PASSWORD"]); got != tt.wantPassword {
+				t.Errorf("secret password = %q, want %q", got, tt.wantPassword)
+			}
+			if tt.wantDeleted != "" {
+				err = k8s.Get(context.Background(), types.NamespacedName{Name: tt.wantDeleted, Namespace: "default"}, &corev1.Secret{})
+				if err == nil {
+					t.Errorf("renamed secret %q is not deleted", tt.wantDeleted)
+				}
+			}
+		})
+	}
+}
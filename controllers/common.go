@@ -2,35 +2,138 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strconv"
+	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/aiven/aiven-go-client"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
 )
 
 const (
 	conditionTypeRunning     = "Running"
 	conditionTypeInitialized = "Initialized"
+	conditionTypeWarning     = "Warning"
+
+	// conditionTypeDependenciesReady is False while the instance waits for references or preconditions
+	conditionTypeDependenciesReady = "DependenciesReady"
+
+	// conditionTypeAccountSuspended is True while Aiven rejects the calls because the account or project is suspended
+	conditionTypeAccountSuspended = "AccountSuspended"
+
+	// conditionTypeSecretEmitted is False while the secret waits for the instance to be running
+	conditionTypeSecretEmitted = "SecretEmitted"
+
+	// reasonPoweredOff is the Running condition reason of the instance powered off on purpose
+	reasonPoweredOff = "PoweredOff"
 
 	secretProtectionFinalizer = "finalizers.aiven.io/needed-to-delete-services"
 	instanceDeletionFinalizer = "finalizers.aiven.io/delete-remote-resource"
 
-	processedGenerationAnnotation = "controllers.aiven.io/generation-was-processed"
-	instanceIsRunningAnnotation   = "controllers.aiven.io/instance-is-running"
+	processedGenerationAnnotation   = "controllers.aiven.io/generation-was-processed"
+	instanceIsRunningAnnotation     = "controllers.aiven.io/instance-is-running"
+	forceDeleteAnnotation           = "controllers.aiven.io/force-delete"
+	secretNameAnnotation            = "controllers.aiven.io/secret-name"
+	caRotationStartedAnnotation     = "controllers.aiven.io/ca-rotation-started"
+	reconcileNowAnnotation          = "controllers.aiven.io/reconcile-now"
+	lastAppliedUserConfigAnnotation = "controllers.aiven.io/last-applied-user-config"
+
+	// credentialsRotatedAnnotation when the previous credentials were put to the _PREVIOUS suffixed secret keys
+	credentialsRotatedAnnotation = "controllers.aiven.io/credentials-rotated"
+
+	// datadogAPIKeyHashAnnotation hash of the API key applied to the integration datadog endpoint
+	datadogAPIKeyHashAnnotation = "controllers.aiven.io/datadog-api-key-hash"
+
+	// schemaRegistryAuthHashAnnotation hash of the basic auth credentials applied to the integration external schema registry endpoint
+	schemaRegistryAuthHashAnnotation = "controllers.aiven.io/schema-registry-auth-hash"
+
+	// samlCertificateHashAnnotation hash of the SAML certificate applied to the account authentication method
+	samlCertificateHashAnnotation = "controllers.aiven.io/saml-certificate-hash"
+
+	// requeueAttemptAnnotation "uid/generation/attempt" of the requeues while waiting for the instance to be running
+	requeueAttemptAnnotation = "controllers.aiven.io/requeue-attempt"
+
+	// lastAppliedTagsAnnotation service tags set by the operator, the tags removed from the spec are removed on Aiven side
+	lastAppliedTagsAnnotation = "controllers.aiven.io/last-applied-tags"
+
+	// debugAnnotation "true" logs the Aiven API requests and responses of the instance reconcile
+	debugAnnotation = "controllers.aiven.io/debug"
+
+	// ipFilterHashAnnotation hash of the CIDR blocks applied from the service ip filter ConfigMap
+	ipFilterHashAnnotation = "controllers.aiven.io/ip-filter-hash"
+
+	// templateHashAnnotation hash of the user config applied from the integration template ConfigMap
+	templateHashAnnotation = "controllers.aiven.io/template-hash"
+
+	// authSecretAllowedNamespacesAnnotation comma-separated namespaces which resources can use the auth secret, "*" for all
+	authSecretAllowedNamespacesAnnotation = "controllers.aiven.io/allowed-namespaces"
+
+	// dryRunAnnotation "true" plans the changes of the instance without applying them
+	dryRunAnnotation = "controllers.aiven.io/dry-run"
+
+	// orphanAnnotation "true" removes the finalizer of the instance in a deletion-protected namespace,
+	// the instance is kept on Aiven side
+	orphanAnnotation = "controllers.aiven.io/orphan"
+
+	secretOwnerUIDLabel  = "controllers.aiven.io/owner-uid"
+	secretOwnerKindLabel = "controllers.aiven.io/owner-kind"
+
+	serviceStatePowerOff = "POWEROFF"
+
+	secretUpdateStrategyMerge = "merge"
+
+	secretKeyEncodingBase64 = "base64"
+
+	caCertKey     = "CA_CERT"
+	caCertNextKey = "CA_CERT_NEXT"
+
+	// previousCredentialSuffix the replaced credentials are kept under the suffixed keys for the grace period
+	previousCredentialSuffix = "_PREVIOUS"
+
+	// caRotationPeriod is how long both the current and the new CA are kept in the secret
+	caRotationPeriod = 24 * time.Hour
 )
 
 var (
 	operatorUserAgent          = "k8s-operator/" + aiven.Version()
 	errTerminationProtectionOn = errors.New("termination protection is on")
+	errNotEmpty                = errors.New("instance is not empty, deletion protection is on")
+	errNamespaceProtected      = errors.New("instance namespace is deletion-protected")
+	errHasDependents           = errors.New("instance has dependent resources")
+	errSecretNotReady          = errors.New("referenced secret is not ready")
+	errServiceNameReserved     = errors.New("service name is reserved by a deleted service")
+	errIntegrationNotReady     = errors.New("integrated services are not ready for the integration yet")
+	errSecretNotOwned          = errors.New("secret exists and is not owned by the resource, not overwriting it")
+	errAuthTokenNotReadable    = errors.New("unable to read auth token")
+	errServiceNotFound         = errors.New("service is not found")
+	errNoPrometheusIntegration = errors.New("service has no prometheus integration")
+	errProjectNotFound         = errors.New("project is not found")
+	errAuthSecretNotShared     = errors.New("auth secret is not shared with the namespace")
 )
 
-func checkServiceIsRunning(c *aiven.Client, project, serviceName string) (bool, error) {
-	s, err := c.Services.Get(project, serviceName)
+func checkServiceIsRunning(c AivenClient, project, serviceName string) (bool, error) {
+	s, err := c.Services().Get(project, serviceName)
 	if err != nil {
 		// if service is not found, it is not running
 		if aiven.IsNotFound(err) {
@@ -41,6 +144,23 @@ func checkServiceIsRunning(c *aiven.Client, project, serviceName string) (bool,
 	return s.State == "RUNNING", nil
 }
 
+// checkServiceTypeIsRunning works as checkServiceIsRunning, but also fails if the service has none of the types
+func checkServiceTypeIsRunning(c AivenClient, project, serviceName string, serviceTypes ...string) (bool, error) {
+	s, err := c.Services().Get(project, serviceName)
+	if err != nil {
+		if aiven.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, t := range serviceTypes {
+		if s.Type == t {
+			return s.State == "RUNNING", nil
+		}
+	}
+	return false, fmt.Errorf("service %q has type %q, expected %s", serviceName, s.Type, strings.Join(serviceTypes, " or "))
+}
+
 func getInitializedCondition(reason, message string) metav1.Condition {
 	return metav1.Condition{
 		Type:    conditionTypeInitialized,
@@ -59,6 +179,85 @@ func getRunningCondition(status metav1.ConditionStatus, reason, message string)
 	}
 }
 
+func getDependenciesReadyCondition(status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    conditionTypeDependenciesReady,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+func getSecretEmittedCondition(status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    conditionTypeSecretEmitted,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+func getAccountSuspendedCondition(message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    conditionTypeAccountSuspended,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AccountSuspended",
+		Message: message,
+	}
+}
+
+// checkProjectExists checks that the spec.project exists before the instance is created.
+// Returns errProjectNotFound if Aiven doesn't know the project, Aiven answers forbidden for the projects the token has no access to.
+// Skipped for the instances which are already created and the ones with a Project reference, which is running by now
+func checkProjectExists(avn AivenClient, o client.Object, refs []client.Object) error {
+	if o.GetAnnotations()[processedGenerationAnnotation] != "" || v1alpha1.FindProject(refs) != nil {
+		return nil
+	}
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return err
+	}
+	project, _, _ := unstructured.NestedString(u, "spec", "project")
+	if project == "" {
+		return nil
+	}
+
+	_, err = avn.Projects().Get(project)
+	var e aiven.Error
+	if errors.As(err, &e) && (e.Status == http.StatusNotFound || e.Status == http.StatusForbidden) {
+		return fmt.Errorf("%w: %q, check the project name and the token access", errProjectNotFound, project)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get project %q: %w", project, err)
+	}
+	return nil
+}
+
+// checkSecretKeys checks that the secret exists and has the non-empty keys.
+// Returns errSecretNotReady otherwise, so the instance waits for the secret
+func checkSecretKeys(k8s client.Client, namespace, name string, keys ...string) error {
+	secret := &corev1.Secret{}
+	err := k8s.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, secret)
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("%w: secret %q not found", errSecretNotReady, name)
+	}
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, k := range keys {
+		if len(secret.Data[k]) == 0 {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: secret %q has no %s keys", errSecretNotReady, name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 func isMarkedForDeletion(o client.Object) bool {
 	return !o.GetDeletionTimestamp().IsZero()
 }
@@ -77,6 +276,114 @@ func isAlreadyProcessed(o client.Object) bool {
 	return o.GetAnnotations()[processedGenerationAnnotation] == strconv.FormatInt(o.GetGeneration(), formatIntBaseDecimal)
 }
 
+// hashValue returns sha256 hex of a secret value, which is safe to store in annotations
+func hashValue(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return hex.EncodeToString(sum[:])
+}
+
+// isNotChangedError returns true if Aiven rejected the update because nothing has changed.
+// Such update has converged, so it must be handled as a successful one
+func isNotChangedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, s := range notChangedMessages {
+		if strings.Contains(err.Error(), s) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTerminationProtectionError returns true if Aiven rejects the deletion of a protected service
+func isTerminationProtectionError(err error) bool {
+	var e aiven.Error
+	if !errors.As(err, &e) || e.Status != http.StatusForbidden {
+		return false
+	}
+	msg := strings.ToLower(e.Message)
+	return strings.Contains(msg, "termination protection") || strings.Contains(msg, "protected against termination")
+}
+
+// notChangedMessages no-op update error messages of Aiven endpoints
+var notChangedMessages = []string{
+	"user config not changed",
+	"nothing to update",
+}
+
+// isSecretMissing returns true if the secret is deleted or emptied out of band, so it must be regenerated
+func isSecretMissing(ctx context.Context, k8s client.Client, namespace, name string) (bool, error) {
+	secret := &corev1.Secret{}
+	err := k8s.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, secret)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return len(secret.Data) == 0, nil
+}
+
+// operatorAnnotations annotations which are set by the operator itself
+var operatorAnnotations = []string{
+	processedGenerationAnnotation,
+	instanceIsRunningAnnotation,
+	secretNameAnnotation,
+	caRotationStartedAnnotation,
+	credentialsRotatedAnnotation,
+	lastAppliedUserConfigAnnotation,
+	lastAppliedTagsAnnotation,
+	datadogAPIKeyHashAnnotation,
+	schemaRegistryAuthHashAnnotation,
+	samlCertificateHashAnnotation,
+	ipFilterHashAnnotation,
+	templateHashAnnotation,
+	requeueAttemptAnnotation,
+}
+
+// ignoreOperatorChangesPredicate skips update events caused by the operator itself:
+// changes of the operator-owned annotations and status, which is written by the operator only
+var ignoreOperatorChangesPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		o, n := e.ObjectOld, e.ObjectNew
+		return o.GetGeneration() != n.GetGeneration() ||
+			!o.GetDeletionTimestamp().Equal(n.GetDeletionTimestamp()) ||
+			!reflect.DeepEqual(o.GetLabels(), n.GetLabels()) ||
+			!reflect.DeepEqual(o.GetFinalizers(), n.GetFinalizers()) ||
+			!reflect.DeepEqual(userAnnotations(o), userAnnotations(n))
+	},
+}
+
+// userAnnotations returns annotations without the operator-owned ones
+func userAnnotations(o client.Object) map[string]string {
+	a := make(map[string]string, len(o.GetAnnotations()))
+	for k, v := range o.GetAnnotations() {
+		a[k] = v
+	}
+	for _, k := range operatorAnnotations {
+		delete(a, k)
+	}
+	return a
+}
+
+// isTimeoutError returns true if the error is caused by a network timeout
+func isTimeoutError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isAccountSuspendedError returns true if Aiven rejected the call because the account or project is suspended,
+// for instance, due to unpaid invoices
+func isAccountSuspendedError(err error) bool {
+	var e aiven.Error
+	return errors.As(err, &e) && e.Status == http.StatusForbidden && strings.Contains(strings.ToLower(e.Message), "suspended")
+}
+
+func isForceDelete(o client.Object) bool {
+	return o.GetAnnotations()[forceDeleteAnnotation] == "true"
+}
+
 // IsAlreadyRunning returns true if object is ready to use
 func IsAlreadyRunning(o client.Object) bool {
 	_, found := o.GetAnnotations()[instanceIsRunningAnnotation]
@@ -95,3 +402,127 @@ func isAivenServerError(err error) bool {
 	e, ok := err.(aiven.Error)
 	return ok && e.Status >= http.StatusInternalServerError
 }
+
+// newObjectAivenClient returns Aiven client authenticated with the default token,
+// or with the object authSecretRef token if there is no default one
+func newObjectAivenClient(ctx context.Context, k8s client.Client, opts Options, o aivenManagedObject) (AivenClient, error) {
+	token := opts.DefaultToken
+	if token == "" {
+		var err error
+		token, _, err = resolveAuthToken(ctx, k8s, o, opts.AuthTokens)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return opts.aivenClient(token)
+}
+
+// authTokenEnvPrefix the environment variables with tokens, so resources can't read the others
+const authTokenEnvPrefix = "AIVEN_TOKEN_"
+
+// AuthTokenSources configures the authSecretRef file and environment variable token sources.
+// The operator files and environment are shared by all namespaces,
+// so each source is allowed to the listed namespaces only
+type AuthTokenSources struct {
+	// Dir the directory token files are read from, for instance, mounted by Vault agent.
+	// File sources are disabled if empty
+	Dir string
+
+	// FileNamespaces the namespaces which resources can read token files, "*" allows all
+	FileNamespaces []string
+
+	// EnvNamespaces the namespaces which resources can read environment variables, "*" allows all
+	EnvNamespaces []string
+}
+
+// isAllowed returns true if the namespace is in the list
+func isAllowed(namespaces []string, namespace string) bool {
+	for _, n := range namespaces {
+		if n == "*" || n == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// authTokenEventReason tells the missing secret from the unreadable file or environment variable
+func authTokenEventReason(err error) string {
+	if errors.Is(err, errAuthTokenNotReadable) {
+		return eventUnableToReadAuthToken
+	}
+	return eventUnableToGetAuthSecret
+}
+
+// resolveAuthToken returns the object authSecretRef token: from the secret, the file in the sources dir or the environment variable.
+// The secret is returned to be protected with the finalizer, nil for the other sources.
+// The file and env failures are errAuthTokenNotReadable, including the sources not allowed to the object namespace
+func resolveAuthToken(ctx context.Context, k8s client.Client, o aivenManagedObject, sources AuthTokenSources) (string, *corev1.Secret, error) {
+	auth := o.AuthSecretRef()
+	tokenDir := sources.Dir
+	switch {
+	case auth == nil || (auth.Name == "" && auth.File == "" && auth.Env == ""):
+		return "", nil, errNoTokenProvided
+	case auth.File != "":
+		if tokenDir == "" {
+			return "", nil, fmt.Errorf("%w: file %q, token files are disabled, set --auth-token-dir", errAuthTokenNotReadable, auth.File)
+		}
+		if !isAllowed(sources.FileNamespaces, o.GetNamespace()) {
+			return "", nil, fmt.Errorf("%w: file %q, token files are not allowed in namespace %q, see --auth-token-file-namespaces",
+				errAuthTokenNotReadable, auth.File, o.GetNamespace())
+		}
+		path := filepath.Clean(auth.File)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(tokenDir, path)
+		}
+		if rel, err := filepath.Rel(filepath.Clean(tokenDir), path); err != nil || strings.HasPrefix(rel, "..") {
+			return "", nil, fmt.Errorf("%w: file %q is not in %q", errAuthTokenNotReadable, auth.File, tokenDir)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %s", errAuthTokenNotReadable, err)
+		}
+		return strings.TrimSpace(string(b)), nil, nil
+	case auth.Env != "":
+		if !isAllowed(sources.EnvNamespaces, o.GetNamespace()) {
+			return "", nil, fmt.Errorf("%w: environment variable %q is not allowed in namespace %q, see --auth-token-env-namespaces",
+				errAuthTokenNotReadable, auth.Env, o.GetNamespace())
+		}
+		token, ok := os.LookupEnv(auth.Env)
+		if !strings.HasPrefix(auth.Env, authTokenEnvPrefix) || !ok {
+			return "", nil, fmt.Errorf("%w: environment variable %q is not set or doesn't start with %s", errAuthTokenNotReadable, auth.Env, authTokenEnvPrefix)
+		}
+		return token, nil, nil
+	}
+
+	key := authSecretKey(o.GetNamespace(), auth)
+	secret := &corev1.Secret{}
+	err := k8s.Get(ctx, key, secret)
+	switch {
+	case err != nil && key.Namespace != o.GetNamespace() && !apierrors.IsNotFound(err):
+		return "", nil, fmt.Errorf("cannot get secret %q, the operator must watch and have access to namespace %q: %w", key, key.Namespace, err)
+	case err != nil:
+		return "", nil, fmt.Errorf("cannot get secret %q: %w", key, err)
+	case key.Namespace != o.GetNamespace() && !isSecretSharedWith(secret, o.GetNamespace()):
+		return "", nil, fmt.Errorf("%w: secret %q doesn't list namespace %q in the %s annotation",
+			errAuthSecretNotShared, key, o.GetNamespace(), authSecretAllowedNamespacesAnnotation)
+	}
+	return string(secret.Data[auth.Key]), secret, nil
+}
+
+// authSecretKey returns the auth secret key, the secret is in the resource namespace unless the namespace is set
+func authSecretKey(namespace string, auth *v1alpha1.AuthSecretReference) types.NamespacedName {
+	if auth.Namespace != "" {
+		namespace = auth.Namespace
+	}
+	return types.NamespacedName{Name: auth.Name, Namespace: namespace}
+}
+
+// isSecretSharedWith returns true if the secret allows the namespace resources to use it
+func isSecretSharedWith(secret *corev1.Secret, namespace string) bool {
+	for _, n := range strings.Split(secret.GetAnnotations()[authSecretAllowedNamespacesAnnotation], ",") {
+		if n = strings.TrimSpace(n); n == "*" || n == namespace {
+			return true
+		}
+	}
+	return false
+}
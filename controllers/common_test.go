@@ -0,0 +1,287 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aiven/aiven-go-client"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+func Test_isTimeoutError(t *testing.T) {
+	raw := &aiven.Client{Client: &http.Client{
+		Timeout: time.Millisecond,
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			<-r.Context().Done()
+			return nil, r.Context().Err()
+		}),
+	}}
+	raw.Init()
+	avn := newGoClient(raw)
+
+	_, err := avn.Services().Get("foo", "bar")
+	if !isTimeoutError(fmt.Errorf("wrapped: %w", err)) {
+		t.Errorf("isTimeoutError(%q) = false, want true", err)
+	}
+	if isTimeoutError(fmt.Errorf("not found")) {
+		t.Error("isTimeoutError() = true, want false")
+	}
+}
+
+func Test_ignoreOperatorChangesPredicate(t *testing.T) {
+	old := &v1alpha1.Kafka{ObjectMeta: metav1.ObjectMeta{
+		Generation:  1,
+		Annotations: map[string]string{"foo": "bar"},
+	}}
+	tests := []struct {
+		name   string
+		mutate func(o *v1alpha1.Kafka)
+		want   bool
+	}{
+		{"operator annotation", func(o *v1alpha1.Kafka) { o.Annotations[processedGenerationAnnotation] = "1" }, false},
+		{"status", func(o *v1alpha1.Kafka) { o.Status.State = "RUNNING" }, false},
+		{"generation", func(o *v1alpha1.Kafka) { o.Generation = 2 }, true},
+		{"user annotation", func(o *v1alpha1.Kafka) { o.Annotations[reconcileNowAnnotation] = "now" }, true},
+		{"labels", func(o *v1alpha1.Kafka) { o.Labels = map[string]string{"foo": "bar"} }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := old.DeepCopy()
+			tt.mutate(n)
+			if got := ignoreOperatorChangesPredicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: n}); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkSecretKeys(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default"},
+		Data:       map[string][]byte{"username": []byte("foo"), "password": nil},
+	}
+	k8s := fake.NewClientBuilder().WithObjects(secret).Build()
+
+	if err := checkSecretKeys(k8s, "default", "creds", "username"); err != nil {
+		t.Errorf("checkSecretKeys() unexpected error %s", err)
+	}
+
+	err := checkSecretKeys(k8s, "default", "creds", "username", "password")
+	if !errors.Is(err, errSecretNotReady) || !strings.Contains(err.Error(), "password") {
+		t.Errorf("checkSecretKeys() expected missing password key error, got %v", err)
+	}
+
+	err = checkSecretKeys(k8s, "default", "missing", "username")
+	if !errors.Is(err, errSecretNotReady) || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("checkSecretKeys() expected secret not found error, got %v", err)
+	}
+}
+
+func Test_checkServiceTypeIsRunning(t *testing.T) {
+	avn := &mockAivenClient{services: &mockServices{
+		GetFunc: func(project, service string) (*aiven.Service, error) {
+			return &aiven.Service{Name: service, Type: "influxdb", State: "RUNNING"}, nil
+		},
+	}}
+
+	running, err := checkServiceTypeIsRunning(avn, "project", "foo", metricsDestinationTypes...)
+	if err != nil || !running {
+		t.Errorf("checkServiceTypeIsRunning() = %v, %v, want true", running, err)
+	}
+
+	if _, err = checkServiceTypeIsRunning(avn, "project", "foo", "pg"); err == nil {
+		t.Error("checkServiceTypeIsRunning() expected service type error")
+	}
+}
+
+func Test_isSecretMissing(t *testing.T) {
+	full := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "full", Namespace: "default"},
+		Data:       map[string][]byte{"PASSWORD": []byte("secret")},
+	}
+	empty := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: "default"}}
+	k8s := fake.NewClientBuilder().WithObjects(full, empty).Build()
+
+	cases := map[string]bool{"full": false, "empty": true, "deleted": true}
+	for name, want := range cases {
+		got, err := isSecretMissing(context.Background(), k8s, "default", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("isSecretMissing(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func Test_isNotChangedError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{aiven.Error{Message: "user config not changed", Status: 400}, true},
+		{fmt.Errorf("failed to update: %w", aiven.Error{Message: "Nothing to update: nothing to update", Status: 400}), true},
+		{aiven.Error{Message: "Invalid user config", Status: 400}, false},
+	}
+	for _, c := range cases {
+		if got := isNotChangedError(c.err); got != c.want {
+			t.Errorf("isNotChangedError(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
+
+func Test_resolveAuthToken(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte("file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AIVEN_TOKEN_TEAM", "env-token")
+	t.Setenv("OTHER_TOKEN", "other")
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "aiven-token", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("secret-token")},
+	}
+	shared := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared-token", Namespace: "central", Annotations: map[string]string{authSecretAllowedNamespacesAnnotation: "team-a, default"}},
+		Data:       map[string][]byte{"token": []byte("shared-token")},
+	}
+	private := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "private-token", Namespace: "central"},
+		Data:       map[string][]byte{"token": []byte("private-token")},
+	}
+	k8s := fake.NewClientBuilder().WithObjects(secret, shared, private).Build()
+	allowed := AuthTokenSources{Dir: dir, FileNamespaces: []string{"default"}, EnvNamespaces: []string{"team-a", "default"}}
+
+	tests := []struct {
+		name         string
+		auth         *v1alpha1.AuthSecretReference
+		sources      AuthTokenSources
+		want         string
+		wantSecret   bool
+		wantNotRead  bool
+		wantNoSource bool
+		wantShared   bool
+	}{
+		{name: "secret", auth: &v1alpha1.AuthSecretReference{Name: "aiven-token", Key: "token"}, want: "secret-token", wantSecret: true},
+		{name: "shared secret", auth: &v1alpha1.AuthSecretReference{Name: "shared-token", Key: "token", Namespace: "central"}, want: "shared-token", wantSecret: true},
+		{name: "not shared secret", auth: &v1alpha1.AuthSecretReference{Name: "private-token", Key: "token", Namespace: "central"}, wantShared: true},
+		{name: "no source", wantNoSource: true},
+		{name: "file", auth: &v1alpha1.AuthSecretReference{File: "token"}, sources: allowed, want: "file-token"},
+		{name: "absolute file", auth: &v1alpha1.AuthSecretReference{File: filepath.Join(dir, "token")}, sources: allowed, want: "file-token"},
+		{name: "missing file", auth: &v1alpha1.AuthSecretReference{File: "missing"}, sources: allowed, wantNotRead: true},
+		{name: "file out of the dir", auth: &v1alpha1.AuthSecretReference{File: "../token"}, sources: allowed, wantNotRead: true},
+		{name: "files disabled", auth: &v1alpha1.AuthSecretReference{File: "token"}, sources: AuthTokenSources{FileNamespaces: []string{"*"}}, wantNotRead: true},
+		{name: "file not allowed in namespace", auth: &v1alpha1.AuthSecretReference{File: "token"}, sources: AuthTokenSources{Dir: dir, FileNamespaces: []string{"team-a"}}, wantNotRead: true},
+		{name: "file without namespaces", auth: &v1alpha1.AuthSecretReference{File: "token"}, sources: AuthTokenSources{Dir: dir}, wantNotRead: true},
+		{name: "file allowed in all namespaces", auth: &v1alpha1.AuthSecretReference{File: "token"}, sources: AuthTokenSources{Dir: dir, FileNamespaces: []string{"*"}}, want: "file-token"},
+		{name: "env", auth: &v1alpha1.AuthSecretReference{Env: "AIVEN_TOKEN_TEAM"}, sources: allowed, want: "env-token"},
+		{name: "env without prefix", auth: &v1alpha1.AuthSecretReference{Env: "OTHER_TOKEN"}, sources: allowed, wantNotRead: true},
+		{name: "env not allowed in namespace", auth: &v1alpha1.AuthSecretReference{Env: "AIVEN_TOKEN_TEAM"}, sources: AuthTokenSources{EnvNamespaces: []string{"team-a"}}, wantNotRead: true},
+		{name: "env without namespaces", auth: &v1alpha1.AuthSecretReference{Env: "AIVEN_TOKEN_TEAM"}, wantNotRead: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pg := &v1alpha1.PostgreSQL{ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "default"}}
+			pg.Spec.AuthSecretRef = tt.auth
+			token, s, err := resolveAuthToken(context.Background(), k8s, pg, tt.sources)
+			if errors.Is(err, errAuthTokenNotReadable) != tt.wantNotRead || errors.Is(err, errNoTokenProvided) != tt.wantNoSource ||
+				errors.Is(err, errAuthSecretNotShared) != tt.wantShared {
+				t.Fatalf("resolveAuthToken() error = %v", err)
+			}
+			if token != tt.want || (s != nil) != tt.wantSecret {
+				t.Errorf("resolveAuthToken() = %q, secret %t, want %q, secret %t", token, s != nil, tt.want, tt.wantSecret)
+			}
+		})
+	}
+
+	pg := &v1alpha1.PostgreSQL{ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "default"}}
+	pg.Spec.AuthSecretRef = &v1alpha1.AuthSecretReference{Name: "shared-token", Key: "token", Namespace: "central"}
+	if got := secretRefIndexFunc(pg); !reflect.DeepEqual(got, []string{"central/shared-token"}) {
+		t.Errorf("secretRefIndexFunc() = %v, want the secret namespaced name", got)
+	}
+
+	if got := authTokenEventReason(fmt.Errorf("%w: missing", errAuthTokenNotReadable)); got != eventUnableToReadAuthToken {
+		t.Errorf("authTokenEventReason() = %q, want %q", got, eventUnableToReadAuthToken)
+	}
+}
+
+func Test_newObjectAivenClient(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "aiven-token", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("secret-token")},
+	}
+	k8s := fake.NewClientBuilder().WithObjects(secret).Build()
+	pg := &v1alpha1.PostgreSQL{ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "default"}}
+	pg.Spec.AuthSecretRef = &v1alpha1.AuthSecretReference{Name: "aiven-token", Key: "token"}
+
+	// The webhooks and the dry-run endpoint get the clients from the configured factory
+	var tokens []string
+	avn := &mockAivenClient{}
+	opts := Options{NewAivenClient: func(token string) (AivenClient, error) {
+		tokens = append(tokens, token)
+		return avn, nil
+	}}
+	for _, defaultToken := range []string{"", "default-token"} {
+		opts.DefaultToken = defaultToken
+		got, err := newObjectAivenClient(context.Background(), k8s, opts, pg)
+		if err != nil || got != avn {
+			t.Fatalf("newObjectAivenClient() = %v, %v, want the factory client", got, err)
+		}
+	}
+	if !reflect.DeepEqual(tokens, []string{"secret-token", "default-token"}) {
+		t.Errorf("factory tokens = %v, want the object token, then the default one", tokens)
+	}
+}
+
+func Test_checkProjectExists(t *testing.T) {
+	avn := &mockAivenClient{projects: &mockProjects{GetFunc: func(project string) (*aiven.Project, error) {
+		switch project {
+		case "foo":
+			return &aiven.Project{Name: project}, nil
+		case "flaky":
+			return nil, aiven.Error{Status: http.StatusBadGateway}
+		}
+		return nil, aiven.Error{Status: http.StatusForbidden}
+	}}}
+	newDB := func(project string) *v1alpha1.Database {
+		return &v1alpha1.Database{Spec: v1alpha1.DatabaseSpec{Project: project}}
+	}
+
+	if err := checkProjectExists(avn, newDB("foo"), nil); err != nil {
+		t.Errorf("existing project: %v", err)
+	}
+	if err := checkProjectExists(avn, newDB("typo"), nil); !errors.Is(err, errProjectNotFound) {
+		t.Errorf("missing project error = %v, want errProjectNotFound", err)
+	}
+	if err := checkProjectExists(avn, newDB("flaky"), nil); err == nil || errors.Is(err, errProjectNotFound) {
+		t.Errorf("server error = %v, want an error other than errProjectNotFound", err)
+	}
+
+	// Created instances and the ones referencing a running Project are not checked
+	processed := newDB("typo")
+	processed.Annotations = map[string]string{processedGenerationAnnotation: "1"}
+	if err := checkProjectExists(avn, processed, nil); err != nil {
+		t.Errorf("processed instance: %v", err)
+	}
+	if err := checkProjectExists(avn, newDB("typo"), []client.Object{&v1alpha1.Project{}}); err != nil {
+		t.Errorf("instance with Project reference: %v", err)
+	}
+}
@@ -5,13 +5,17 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/aiven/aiven-go-client"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
@@ -34,12 +38,12 @@ func (r *ConnectionPoolReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 func (r *ConnectionPoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.ConnectionPool{}).
+		For(&v1alpha1.ConnectionPool{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
 		Owns(&corev1.Secret{}).
 		Complete(r)
 }
 
-func (h ConnectionPoolHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs []client.Object) error {
+func (h ConnectionPoolHandler) createOrUpdate(avn AivenClient, i client.Object, refs []client.Object) error {
 	cp, err := h.convert(i)
 	if err != nil {
 		return err
@@ -51,7 +55,7 @@ func (h ConnectionPoolHandler) createOrUpdate(avn *aiven.Client, i client.Object
 	}
 	var reason string
 	if !exists {
-		_, err := avn.ConnectionPools.Create(cp.Spec.Project, cp.Spec.ServiceName,
+		_, err := avn.ConnectionPools().Create(cp.Spec.Project, cp.Spec.ServiceName,
 			aiven.CreateConnectionPoolRequest{
 				Database: cp.Spec.DatabaseName,
 				PoolMode: cp.Spec.PoolMode,
@@ -64,14 +68,14 @@ func (h ConnectionPoolHandler) createOrUpdate(avn *aiven.Client, i client.Object
 		}
 		reason = "Created"
 	} else {
-		_, err := avn.ConnectionPools.Update(cp.Spec.Project, cp.Spec.ServiceName, cp.Name,
+		_, err := avn.ConnectionPools().Update(cp.Spec.Project, cp.Spec.ServiceName, cp.Name,
 			aiven.UpdateConnectionPoolRequest{
 				Database: cp.Spec.DatabaseName,
 				PoolMode: cp.Spec.PoolMode,
 				PoolSize: cp.Spec.PoolSize,
 				Username: optionalStringPointer(cp.Spec.Username),
 			})
-		if err != nil {
+		if err != nil && !isNotChangedError(err) {
 			return err
 		}
 		reason = "Updated"
@@ -91,13 +95,13 @@ func (h ConnectionPoolHandler) createOrUpdate(avn *aiven.Client, i client.Object
 	return nil
 }
 
-func (h ConnectionPoolHandler) delete(avn *aiven.Client, i client.Object) (bool, error) {
+func (h ConnectionPoolHandler) delete(avn AivenClient, i client.Object) (bool, error) {
 	cp, err := h.convert(i)
 	if err != nil {
 		return false, err
 	}
 
-	err = avn.ConnectionPools.Delete(
+	err = avn.ConnectionPools().Delete(
 		cp.Spec.Project, cp.Spec.ServiceName, cp.Name)
 	if err != nil && !aiven.IsNotFound(err) {
 		return false, err
@@ -106,8 +110,8 @@ func (h ConnectionPoolHandler) delete(avn *aiven.Client, i client.Object) (bool,
 	return true, nil
 }
 
-func (h ConnectionPoolHandler) exists(avn *aiven.Client, cp *v1alpha1.ConnectionPool) (bool, error) {
-	conPool, err := avn.ConnectionPools.Get(cp.Spec.Project, cp.Spec.ServiceName, cp.Name)
+func (h ConnectionPoolHandler) exists(avn AivenClient, cp *v1alpha1.ConnectionPool) (bool, error) {
+	conPool, err := avn.ConnectionPools().Get(cp.Spec.Project, cp.Spec.ServiceName, cp.Name)
 	if err != nil {
 		if aiven.IsNotFound(err) {
 			return false, nil
@@ -118,18 +122,18 @@ func (h ConnectionPoolHandler) exists(avn *aiven.Client, cp *v1alpha1.Connection
 	return conPool != nil, nil
 }
 
-func (h ConnectionPoolHandler) get(avn *aiven.Client, i client.Object) (*corev1.Secret, error) {
+func (h ConnectionPoolHandler) get(avn AivenClient, i client.Object) (*corev1.Secret, error) {
 	connPool, err := h.convert(i)
 	if err != nil {
 		return nil, err
 	}
 
-	cp, err := avn.ConnectionPools.Get(connPool.Spec.Project, connPool.Spec.ServiceName, connPool.Name)
+	cp, err := avn.ConnectionPools().Get(connPool.Spec.Project, connPool.Spec.ServiceName, connPool.Name)
 	if err != nil {
 		return nil, fmt.Errorf("cannot get ConnectionPool: %w", err)
 	}
 
-	s, err := avn.Services.Get(connPool.Spec.Project, connPool.Spec.ServiceName)
+	s, err := avn.Services().Get(connPool.Spec.Project, connPool.Spec.ServiceName)
 	if err != nil {
 		return nil, fmt.Errorf("cannot get service: %w", err)
 	}
@@ -140,27 +144,18 @@ func (h ConnectionPoolHandler) get(avn *aiven.Client, i client.Object) (*corev1.
 		getRunningCondition(metav1.ConditionTrue, "CheckRunning",
 			"Instance is running on Aiven side"))
 
-	if len(connPool.Spec.Username) == 0 {
-		return &corev1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      h.getSecretName(connPool),
-				Namespace: connPool.Namespace,
-			},
-			StringData: map[string]string{
-				"PGHOST":       s.URIParams["host"],
-				"PGPORT":       s.URIParams["port"],
-				"PGDATABASE":   cp.Database,
-				"PGUSER":       s.URIParams["user"],
-				"PGPASSWORD":   s.URIParams["password"],
-				"PGSSLMODE":    s.URIParams["sslmode"],
-				"DATABASE_URI": cp.ConnectionURI,
-			},
-		}, nil
-	}
-
-	u, err := avn.ServiceUsers.Get(connPool.Spec.Project, connPool.Spec.ServiceName, connPool.Spec.Username)
+	user, password := s.URIParams["user"], s.URIParams["password"]
+	if len(connPool.Spec.Username) > 0 {
+		u, err := avn.ServiceUsers().Get(connPool.Spec.Project, connPool.Spec.ServiceName, connPool.Spec.Username)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get user: %w", err)
+		}
+		user, password = cp.Username, u.Password
+	}
+
+	stringData, err := newConnectionPoolConnInfo(s, cp, user, password)
 	if err != nil {
-		return nil, fmt.Errorf("cannot get user: %w", err)
+		return nil, err
 	}
 
 	return &corev1.Secret{
@@ -168,19 +163,46 @@ func (h ConnectionPoolHandler) get(avn *aiven.Client, i client.Object) (*corev1.
 			Name:      h.getSecretName(connPool),
 			Namespace: connPool.Namespace,
 		},
-		StringData: map[string]string{
-			"PGHOST":       s.URIParams["host"],
-			"PGPORT":       s.URIParams["port"],
-			"PGDATABASE":   cp.Database,
-			"PGUSER":       cp.Username,
-			"PGPASSWORD":   u.Password,
-			"PGSSLMODE":    s.URIParams["sslmode"],
-			"DATABASE_URI": cp.ConnectionURI,
-		},
+		StringData: stringData,
+	}, nil
+}
+
+// newConnectionPoolConnInfo returns the pool connection info, so apps can choose
+// between the pooled (PGBOUNCER_ prefixed keys) and the direct (DIRECT_DATABASE_URI) connection.
+// DATABASE_URI is the pooled connection
+func newConnectionPoolConnInfo(s *aiven.Service, cp *aiven.ConnectionPool, user, password string) (map[string]string, error) {
+	pool, err := url.Parse(cp.ConnectionURI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection pool uri: %w", err)
+	}
+
+	direct := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(user, password),
+		Host:   net.JoinHostPort(s.URIParams["host"], s.URIParams["port"]),
+		Path:   cp.Database,
+	}
+	if sslmode := s.URIParams["sslmode"]; sslmode != "" {
+		direct.RawQuery = url.Values{"sslmode": {sslmode}}.Encode()
+	}
+
+	return map[string]string{
+		"PGHOST":              s.URIParams["host"],
+		"PGPORT":              s.URIParams["port"],
+		"PGDATABASE":          cp.Database,
+		"PGUSER":              user,
+		"PGPASSWORD":          password,
+		"PGSSLMODE":           s.URIParams["sslmode"],
+		"DATABASE_URI":        cp.ConnectionURI,
+		"DIRECT_DATABASE_URI": direct.String(),
+		"PGBOUNCER_HOST":      pool.Hostname(),
+		"PGBOUNCER_PORT":      pool.Port(),
+		"PGBOUNCER_DATABASE":  strings.TrimPrefix(pool.Path, "/"),
+		"PGBOUNCER_URI":       cp.ConnectionURI,
 	}, nil
 }
 
-func (h ConnectionPoolHandler) checkPreconditions(avn *aiven.Client, i client.Object) (bool, error) {
+func (h ConnectionPoolHandler) checkPreconditions(avn AivenClient, i client.Object) (bool, error) {
 	cp, err := h.convert(i)
 	if err != nil {
 		return false, err
@@ -195,7 +217,7 @@ func (h ConnectionPoolHandler) checkPreconditions(avn *aiven.Client, i client.Ob
 	}
 
 	if check {
-		db, err := avn.Databases.Get(cp.Spec.Project, cp.Spec.ServiceName, cp.Spec.DatabaseName)
+		db, err := avn.Databases().Get(cp.Spec.Project, cp.Spec.ServiceName, cp.Spec.DatabaseName)
 		if err != nil {
 			return false, err
 		}
@@ -0,0 +1,272 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+const (
+	// estimatedMonthlyCostAnnotation service estimated monthly cost in USD, set on create
+	estimatedMonthlyCostAnnotation = "controllers.aiven.io/estimated-monthly-cost-usd"
+
+	// monthlyBudgetAnnotation namespace annotation, services which estimated monthly cost
+	// sums up over the budget (USD) are rejected on create
+	monthlyBudgetAnnotation = "controllers.aiven.io/monthly-budget-usd"
+
+	// hoursPerMonth Aiven plan prices are hourly
+	hoursPerMonth = 730
+
+	costEstimationWebhookPath = "/mutate-aiven-io-v1alpha1-cost-estimation"
+
+	// costReservationTTL how long the admitted service cost counts towards the budget,
+	// the created service is listed with its annotation by then
+	costReservationTTL = time.Minute
+)
+
+//+kubebuilder:webhook:path=/mutate-aiven-io-v1alpha1-cost-estimation,mutating=true,failurePolicy=fail,groups=aiven.io,resources=cassandras;clickhouses;grafanas;kafkas;kafkaconnects;mysqls;opensearches;postgresqls;redis,verbs=create,versions=v1alpha1,name=mcostestimation.kb.io,sideEffects=none,admissionReviewVersions=v1
+
+// costEstimator estimates service monthly cost from the plan pricing on create,
+// writes it to the annotation and rejects services over the namespace budget.
+// Services in namespaces with a budget are rejected if the cost can't be estimated
+type costEstimator struct {
+	k8s     client.Client
+	decoder *admission.Decoder
+	enabled bool
+
+	// newClient returns Aiven client authorized with the default token or the object token
+	newClient func(ctx context.Context, o aivenManagedObject) (AivenClient, error)
+
+	// budgets enables namespace budgets, which requires namespaces read access
+	budgets bool
+
+	// mu serializes the budget checks, so concurrent creates can't exceed the budget together
+	mu sync.Mutex
+
+	// reserved the cost of the admitted services by namespace and kind/name, which might not be listed yet
+	reserved map[string]map[string]costReservation
+}
+
+// costReservation the admitted service cost
+type costReservation struct {
+	cost    float64
+	expires time.Time
+}
+
+// SetupCostEstimationWebhook registers the cost estimation webhook.
+// The webhook is registered in manifests, hence it allows everything when not enabled.
+// Namespace budgets are not checked unless budgets is true
+func SetupCostEstimationWebhook(mgr ctrl.Manager, opts Options, enabled, budgets bool) error {
+	decoder, err := admission.NewDecoder(mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
+	k8s := mgr.GetClient()
+	mgr.GetWebhookServer().Register(costEstimationWebhookPath, &webhook.Admission{Handler: &costEstimator{
+		k8s:     k8s,
+		decoder: decoder,
+		enabled: enabled,
+		budgets: budgets,
+		newClient: func(ctx context.Context, o aivenManagedObject) (AivenClient, error) {
+			return newObjectAivenClient(ctx, k8s, opts, o)
+		},
+	}})
+	return nil
+}
+
+func (e *costEstimator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	service, ok := serviceKinds[req.Kind.Kind]
+	if !ok || !e.enabled {
+		return admission.Allowed("no cost estimation for the kind")
+	}
+
+	obj, err := e.k8s.Scheme().New(schema.GroupVersionKind{Group: req.Kind.Group, Version: req.Kind.Version, Kind: req.Kind.Kind})
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	o := obj.(aivenManagedObject)
+	if err = e.decoder.Decode(req, o); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	adapter, err := service.fabric(nil, o)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// The pricing request goes before the lock, so a slow API doesn't hold the other creates
+	cost, estimateErr := e.estimate(ctx, o, adapter)
+
+	var budget float64
+	if e.budgets {
+		// The check and the reservation go together
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		budget, err = e.getBudget(ctx, req.Namespace)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+	}
+
+	if err = estimateErr; err != nil {
+		// Estimation is best effort, unless the budget can be bypassed
+		if budget > 0 {
+			return admission.Denied(fmt.Sprintf("unable to estimate cost against namespace budget %.2f USD: %s", budget, err))
+		}
+		return admission.Allowed(fmt.Sprintf("unable to estimate cost: %s", err))
+	}
+
+	if budget > 0 {
+		spent, err := e.namespaceSpent(ctx, req.Namespace, time.Now())
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if spent+cost > budget {
+			return admission.Denied(fmt.Sprintf(
+				"estimated monthly cost %.2f USD exceeds namespace budget %.2f USD, %.2f USD is already spent", cost, budget, spent))
+		}
+		if req.DryRun == nil || !*req.DryRun {
+			e.reserve(req.Namespace, req.Kind.Kind+"/"+req.Name, cost, time.Now())
+		}
+	}
+
+	metav1.SetMetaDataAnnotation(adapter.getObjectMeta(), estimatedMonthlyCostAnnotation, strconv.FormatFloat(cost, 'f', 2, 64))
+	b, err := json.Marshal(o)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, b)
+}
+
+// estimate returns the monthly plan price in USD
+func (e *costEstimator) estimate(ctx context.Context, o aivenManagedObject, adapter serviceAdapter) (float64, error) {
+	spec := adapter.getServiceCommonSpec()
+	if spec.CloudName == "" {
+		return 0, fmt.Errorf("cloudName must be set")
+	}
+
+	project, err := getProjectName(ctx, e.k8s, spec, o.GetNamespace())
+	if err != nil {
+		return 0, err
+	}
+	if project == "" {
+		return 0, fmt.Errorf("either project or projectRef must be set")
+	}
+
+	avn, err := e.newClient(ctx, o)
+	if err != nil {
+		return 0, err
+	}
+
+	pricing, err := avn.ServiceTypes().GetPlanPricing(project, adapter.getServiceType(), spec.Plan, spec.CloudName)
+	if err != nil {
+		return 0, err
+	}
+
+	hourly, err := strconv.ParseFloat(pricing.BasePriceUSD, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid plan price %q: %w", pricing.BasePriceUSD, err)
+	}
+	return hourly * hoursPerMonth, nil
+}
+
+// getBudget returns the namespace budget, zero if not set
+func (e *costEstimator) getBudget(ctx context.Context, namespace string) (float64, error) {
+	ns := &corev1.Namespace{}
+	if err := e.k8s.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return 0, err
+	}
+
+	v, ok := ns.Annotations[monthlyBudgetAnnotation]
+	if !ok {
+		return 0, nil
+	}
+
+	budget, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s namespace annotation: %w", monthlyBudgetAnnotation, err)
+	}
+	return budget, nil
+}
+
+// namespaceSpent returns the estimated monthly cost of the namespace services,
+// including the admitted ones which are not listed yet
+func (e *costEstimator) namespaceSpent(ctx context.Context, namespace string, now time.Time) (float64, error) {
+	listed := make(map[string]bool)
+	var spent float64
+	for kind, s := range serviceKinds {
+		list := s.newList()
+		if err := e.k8s.List(ctx, list, client.InNamespace(namespace)); err != nil {
+			return 0, err
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return 0, err
+		}
+		for _, item := range items {
+			o := item.(client.Object)
+			cost, err := strconv.ParseFloat(o.GetAnnotations()[estimatedMonthlyCostAnnotation], 64)
+			if err == nil {
+				listed[kind+"/"+o.GetName()] = true
+				spent += cost
+			}
+		}
+	}
+
+	for key, r := range e.reserved[namespace] {
+		switch {
+		case now.After(r.expires):
+			delete(e.reserved[namespace], key)
+		case !listed[key]:
+			spent += r.cost
+		}
+	}
+	return spent, nil
+}
+
+// reserve counts the admitted service cost until the service is listed
+func (e *costEstimator) reserve(namespace, key string, cost float64, now time.Time) {
+	if e.reserved == nil {
+		e.reserved = make(map[string]map[string]costReservation)
+	}
+	if e.reserved[namespace] == nil {
+		e.reserved[namespace] = make(map[string]costReservation)
+	}
+	e.reserved[namespace][key] = costReservation{cost: cost, expires: now.Add(costReservationTTL)}
+}
+
+// getProjectName returns the Aiven project name, reads the referenced Project if only projectRef is set
+func getProjectName(ctx context.Context, k8s client.Client, spec *v1alpha1.ServiceCommonSpec, namespace string) (string, error) {
+	// The validation webhook ensures both match if both are set
+	if spec.Project != "" || spec.ProjectRef == nil {
+		return spec.Project, nil
+	}
+
+	ref := spec.ProjectRef.Project(namespace)
+	p := &v1alpha1.Project{}
+	if err := k8s.Get(ctx, ref.NamespacedName, p); err != nil {
+		return "", fmt.Errorf("unable to get projectRef %s: %w", ref.NamespacedName, err)
+	}
+	return p.Name, nil
+}
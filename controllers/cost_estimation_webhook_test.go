@@ -0,0 +1,197 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aiven/aiven-go-client"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+func Test_costEstimator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namespace := func(name, budget string) *corev1.Namespace {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if budget != "" {
+			ns.Annotations = map[string]string{monthlyBudgetAnnotation: budget}
+		}
+		return ns
+	}
+	spent := &v1alpha1.PostgreSQL{ObjectMeta: metav1.ObjectMeta{
+		Name:        "spent",
+		Namespace:   "budget",
+		Annotations: map[string]string{estimatedMonthlyCostAnnotation: "730.00"},
+	}}
+	project := &v1alpha1.Project{ObjectMeta: metav1.ObjectMeta{Name: "referenced", Namespace: "budget"}}
+
+	cases := []struct {
+		name        string
+		namespace   string
+		object      string
+		pricingErr  error
+		allowed     bool
+		wantProject string
+	}{
+		{
+			name:        "no budget",
+			namespace:   "free",
+			object:      `{"metadata": {"name": "pg", "namespace": "free"}, "spec": {"project": "my-project", "cloudName": "aws", "plan": "startup-4"}}`,
+			allowed:     true,
+			wantProject: "my-project",
+		},
+		{
+			name:       "no budget, estimation fails",
+			namespace:  "free",
+			object:     `{"metadata": {"name": "pg", "namespace": "free"}, "spec": {"project": "my-project", "cloudName": "aws", "plan": "startup-4"}}`,
+			pricingErr: errors.New("aiven is down"),
+			allowed:    true,
+		},
+		{
+			name:        "within budget, projectRef is resolved",
+			namespace:   "budget",
+			object:      `{"metadata": {"name": "pg", "namespace": "budget"}, "spec": {"projectRef": {"name": "referenced"}, "cloudName": "aws", "plan": "startup-4"}}`,
+			allowed:     true,
+			wantProject: "referenced",
+		},
+		{
+			name:       "budget, estimation fails",
+			namespace:  "budget",
+			object:     `{"metadata": {"name": "pg", "namespace": "budget"}, "spec": {"project": "my-project", "cloudName": "aws", "plan": "startup-4"}}`,
+			pricingErr: errors.New("aiven is down"),
+			allowed:    false,
+		},
+		{
+			name:      "budget, no cloud",
+			namespace: "budget",
+			object:    `{"metadata": {"name": "pg", "namespace": "budget"}, "spec": {"project": "my-project", "plan": "startup-4"}}`,
+			allowed:   false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var gotProject string
+			avn := &mockAivenClient{serviceTypes: &mockServiceTypes{
+				GetPlanPricingFunc: func(project, serviceType, servicePlan, cloudName string) (*aiven.GetServicePlanPricingResponse, error) {
+					gotProject = project
+					if c.pricingErr != nil {
+						return nil, c.pricingErr
+					}
+					return &aiven.GetServicePlanPricingResponse{BasePriceUSD: "1.0"}, nil
+				},
+			}}
+			e := &costEstimator{
+				k8s:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespace("free", ""), namespace("budget", "1500"), spent, project).Build(),
+				decoder:   decoder,
+				enabled:   true,
+				budgets:   true,
+				newClient: func(context.Context, aivenManagedObject) (AivenClient, error) { return avn, nil },
+			}
+
+			got := e.Handle(context.Background(), costRequest(c.namespace, "pg", c.object))
+			if got.Allowed != c.allowed {
+				t.Errorf("allowed = %v, want %v: %s", got.Allowed, c.allowed, got.Result.Message)
+			}
+			if gotProject != c.wantProject && c.wantProject != "" {
+				t.Errorf("pricing project = %q, want %q", gotProject, c.wantProject)
+			}
+		})
+	}
+}
+
+func Test_costEstimatorConcurrentCreates(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "budget",
+		Annotations: map[string]string{monthlyBudgetAnnotation: "1000"},
+	}}
+	k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ns).Build()
+	avn := &mockAivenClient{serviceTypes: &mockServiceTypes{
+		GetPlanPricingFunc: func(project, serviceType, servicePlan, cloudName string) (*aiven.GetServicePlanPricingResponse, error) {
+			return &aiven.GetServicePlanPricingResponse{BasePriceUSD: "1.0"}, nil
+		},
+	}}
+	e := &costEstimator{
+		k8s:       k8s,
+		decoder:   decoder,
+		enabled:   true,
+		budgets:   true,
+		newClient: func(context.Context, aivenManagedObject) (AivenClient, error) { return avn, nil },
+	}
+
+	// The first service is admitted, but not created yet
+	object := `{"metadata": {"name": "%s", "namespace": "budget"}, "spec": {"project": "my-project", "cloudName": "aws", "plan": "startup-4"}}`
+	if got := e.Handle(context.Background(), costRequest("budget", "first", fmt.Sprintf(object, "first"))); !got.Allowed {
+		t.Fatalf("first service is denied: %s", got.Result.Message)
+	}
+	if got := e.Handle(context.Background(), costRequest("budget", "second", fmt.Sprintf(object, "second"))); got.Allowed {
+		t.Error("second service is admitted over the budget together with the first one")
+	}
+
+	// The reservation expires, the first service is never created
+	spent, err := e.namespaceSpent(context.Background(), "budget", time.Now().Add(costReservationTTL+time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spent != 0 {
+		t.Errorf("spent = %.2f, want the expired reservation dropped", spent)
+	}
+
+	// The created service is counted once
+	first := &v1alpha1.PostgreSQL{ObjectMeta: metav1.ObjectMeta{
+		Name:        "first",
+		Namespace:   "budget",
+		Annotations: map[string]string{estimatedMonthlyCostAnnotation: "730.00"},
+	}}
+	if err = k8s.Create(context.Background(), first); err != nil {
+		t.Fatal(err)
+	}
+	e.reserve("budget", "PostgreSQL/first", 730, time.Now())
+	if spent, err = e.namespaceSpent(context.Background(), "budget", time.Now()); err != nil || spent != 730 {
+		t.Errorf("spent = %.2f, %v, want the created service counted once", spent, err)
+	}
+}
+
+func costRequest(namespace, name, object string) admission.Request {
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Group: "aiven.io", Version: "v1alpha1", Kind: "PostgreSQL"},
+		Namespace: namespace,
+		Name:      name,
+		Operation: admissionv1.Create,
+		Object:    runtime.RawExtension{Raw: []byte(object)},
+	}}
+}
@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
@@ -34,11 +35,11 @@ func (r *DatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 func (r *DatabaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Database{}).
+		For(&v1alpha1.Database{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
 		Complete(r)
 }
 
-func (h DatabaseHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs []client.Object) error {
+func (h DatabaseHandler) createOrUpdate(avn AivenClient, i client.Object, refs []client.Object) error {
 	db, err := h.convert(i)
 	if err != nil {
 		return err
@@ -51,7 +52,7 @@ func (h DatabaseHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs
 	}
 
 	if !exists {
-		_, err := avn.Databases.Create(db.Spec.Project, db.Spec.ServiceName, aiven.CreateDatabaseRequest{
+		_, err := avn.Databases().Create(db.Spec.Project, db.Spec.ServiceName, aiven.CreateDatabaseRequest{
 			Database:  db.Name,
 			LcCollate: db.Spec.LcCollate,
 			LcType:    db.Spec.LcCtype,
@@ -75,7 +76,7 @@ func (h DatabaseHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs
 	return nil
 }
 
-func (h DatabaseHandler) delete(avn *aiven.Client, i client.Object) (bool, error) {
+func (h DatabaseHandler) delete(avn AivenClient, i client.Object) (bool, error) {
 	db, err := h.convert(i)
 	if err != nil {
 		return false, err
@@ -85,7 +86,7 @@ func (h DatabaseHandler) delete(avn *aiven.Client, i client.Object) (bool, error
 		return false, errTerminationProtectionOn
 	}
 
-	err = avn.Databases.Delete(
+	err = avn.Databases().Delete(
 		db.Spec.Project,
 		db.Spec.ServiceName,
 		db.Name)
@@ -96,8 +97,8 @@ func (h DatabaseHandler) delete(avn *aiven.Client, i client.Object) (bool, error
 	return true, nil
 }
 
-func (h DatabaseHandler) exists(avn *aiven.Client, db *v1alpha1.Database) (bool, error) {
-	d, err := avn.Databases.Get(db.Spec.Project, db.Spec.ServiceName, db.Name)
+func (h DatabaseHandler) exists(avn AivenClient, db *v1alpha1.Database) (bool, error) {
+	d, err := avn.Databases().Get(db.Spec.Project, db.Spec.ServiceName, db.Name)
 	if aiven.IsNotFound(err) {
 		return false, nil
 	}
@@ -105,13 +106,13 @@ func (h DatabaseHandler) exists(avn *aiven.Client, db *v1alpha1.Database) (bool,
 	return d != nil, nil
 }
 
-func (h DatabaseHandler) get(avn *aiven.Client, i client.Object) (*corev1.Secret, error) {
+func (h DatabaseHandler) get(avn AivenClient, i client.Object) (*corev1.Secret, error) {
 	db, err := h.convert(i)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = avn.Databases.Get(db.Spec.Project, db.Spec.ServiceName, db.Name)
+	_, err = avn.Databases().Get(db.Spec.Project, db.Spec.ServiceName, db.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -125,7 +126,7 @@ func (h DatabaseHandler) get(avn *aiven.Client, i client.Object) (*corev1.Secret
 	return nil, nil
 }
 
-func (h DatabaseHandler) checkPreconditions(avn *aiven.Client, i client.Object) (bool, error) {
+func (h DatabaseHandler) checkPreconditions(avn AivenClient, i client.Object) (bool, error) {
 	db, err := h.convert(i)
 	if err != nil {
 		return false, err
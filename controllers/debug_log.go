@@ -0,0 +1,150 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// redactedValue replaces sensitive values
+const redactedValue = "REDACTED"
+
+// debugLogMaxBodySize longer request and response bodies are cut in the debug log
+const debugLogMaxBodySize = 4096
+
+// debugRedactedKeys JSON fields which values are replaced in the debug log, matched by substring
+var debugRedactedKeys = []string{"password", "secret", "token", "key", "cert", "uri", "credentials"}
+
+// debugRedactedObjects JSON fields which are replaced as a whole, for instance, connection_info has per service type
+// keys with URIs and credentials
+var debugRedactedObjects = []string{"connection_info"}
+
+// uriUserInfo matches the user info of URIs in any value, like "postgres://avnadmin:password@"
+var uriUserInfo = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)[^/@\s"]+@`)
+
+// isDebugEnabled returns true if the object has the debug annotation
+func isDebugEnabled(o client.Object) bool {
+	return o.GetAnnotations()[debugAnnotation] == "true"
+}
+
+// withDebugLog logs Aiven API requests and responses of the client.
+// Only aiven-go-client is supported, other clients are returned as is
+func withDebugLog(avn AivenClient, log logr.Logger) AivenClient {
+	g, ok := avn.(*goClient)
+	if !ok {
+		return avn
+	}
+
+	// aiven-go-client creates http client per client, which is created per reconcile
+	next := g.c.Client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	g.c.Client.Transport = &debugTransport{next: next, log: log}
+	return avn
+}
+
+// debugTransport logs the requests and the responses with sensitive values redacted
+type debugTransport struct {
+	next http.RoundTripper
+	log  logr.Logger
+}
+
+func (t *debugTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if r.Body != nil {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(b))
+		reqBody = b
+	}
+	t.log.Info("aiven api request", "method", r.Method, "path", r.URL.Path, "body", redactBody(reqBody))
+
+	rsp, err := t.next.RoundTrip(r)
+	if err != nil {
+		t.log.Info("aiven api request failed", "method", r.Method, "path", r.URL.Path, "error", err.Error())
+		return nil, err
+	}
+
+	b, err := io.ReadAll(rsp.Body)
+	rsp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	rsp.Body = io.NopCloser(bytes.NewReader(b))
+	t.log.Info("aiven api response", "method", r.Method, "path", r.URL.Path, "status", rsp.StatusCode, "body", redactBody(b))
+	return rsp, nil
+}
+
+// redactBody replaces sensitive values of JSON body and cuts it to debugLogMaxBodySize
+func redactBody(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+
+	// Bodies which can't be redacted are never logged
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Sprintf("(%d bytes of non-JSON body)", len(b))
+	}
+	redacted, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return fmt.Sprintf("(%d bytes of body)", len(b))
+	}
+	b = redacted
+
+	if len(b) > debugLogMaxBodySize {
+		return string(b[:debugLogMaxBodySize]) + "...(truncated)"
+	}
+	return string(b)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			if isRedactedKey(k) || isRedactedObject(k) {
+				v[k] = redactedValue
+				continue
+			}
+			v[k] = redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	case string:
+		return uriUserInfo.ReplaceAllString(v, "${1}"+redactedValue+"@")
+	}
+	return v
+}
+
+func isRedactedObject(k string) bool {
+	for _, r := range debugRedactedObjects {
+		if strings.EqualFold(k, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func isRedactedKey(k string) bool {
+	k = strings.ToLower(k)
+	for _, r := range debugRedactedKeys {
+		if strings.Contains(k, r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,348 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aiven/aiven-go-client"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+const (
+	dryRunDiffPath = "/dry-run-diff"
+
+	// dryRunDiffMaxBodySize the largest resource manifest accepted
+	dryRunDiffMaxBodySize = 1 << 20
+)
+
+// fieldDiff a service field which would be changed on apply
+type fieldDiff struct {
+	Field   string      `json:"field"`
+	Live    interface{} `json:"live"`
+	Desired interface{} `json:"desired"`
+}
+
+// dryRunDiffResponse is empty diff if the live service matches the resource,
+// exists is false if the service would be created
+type dryRunDiffResponse struct {
+	Project string      `json:"project"`
+	Service string      `json:"service"`
+	Exists  bool        `json:"exists"`
+	Diff    []fieldDiff `json:"diff"`
+}
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// dryRunDiffHandler returns the diff between a posted service resource and the live service on Aiven.
+// It is read-only, nothing is applied.
+// The diff is read with the operator credentials, hence the caller must be allowed to update the resource
+type dryRunDiffHandler struct {
+	k8s    client.Client
+	scheme *runtime.Scheme
+
+	// opts the default token, authSecretRef token sources and Aiven client settings
+	opts Options
+}
+
+// SetupDryRunDiffEndpoint serves the dry-run diff endpoint on the metrics server.
+// POST a service manifest (YAML or JSON) with a Kubernetes bearer token to get the fields which would be changed on Aiven
+func SetupDryRunDiffEndpoint(mgr ctrl.Manager, opts Options) error {
+	return mgr.AddMetricsExtraHandler(dryRunDiffPath, &dryRunDiffHandler{
+		k8s:    mgr.GetClient(),
+		scheme: mgr.GetScheme(),
+		opts:   opts,
+	})
+}
+
+func (h *dryRunDiffHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || token == r.Header.Get("Authorization") {
+		http.Error(w, "bearer token is required", http.StatusUnauthorized)
+		return
+	}
+
+	u := &unstructured.Unstructured{}
+	decoder := yaml.NewYAMLOrJSONDecoder(http.MaxBytesReader(w, r.Body, dryRunDiffMaxBodySize), 4096)
+	if err := decoder.Decode(&u.Object); err != nil {
+		http.Error(w, fmt.Sprintf("invalid manifest: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	kind := u.GroupVersionKind().Kind
+	service, ok := serviceKinds[kind]
+	if !ok {
+		http.Error(w, fmt.Sprintf("dry-run diff is not supported for kind %q", kind), http.StatusBadRequest)
+		return
+	}
+
+	obj, err := h.scheme.New(u.GroupVersionKind())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+		http.Error(w, fmt.Sprintf("invalid manifest: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	o := obj.(aivenManagedObject)
+	if o.GetNamespace() == "" {
+		o.SetNamespace("default")
+	}
+
+	if err = h.authorize(r.Context(), token, service.resource, o); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	adapter, err := service.fabric(nil, o)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	spec := adapter.getServiceCommonSpec()
+	project := spec.Project
+	if project == "" && spec.ProjectRef != nil {
+		project = spec.ProjectRef.Name
+	}
+	if project == "" {
+		http.Error(w, "project must be set", http.StatusBadRequest)
+		return
+	}
+
+	avn, err := newObjectAivenClient(r.Context(), h.k8s, h.opts, o)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	live, err := avn.Services().Get(project, o.GetName())
+	if err != nil && !aiven.IsNotFound(err) {
+		http.Error(w, fmt.Sprintf("failed to fetch service: %s", err), http.StatusBadGateway)
+		return
+	}
+
+	diff, err := serviceDiff(adapter, live)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(&dryRunDiffResponse{
+		Project: project,
+		Service: o.GetName(),
+		Exists:  live != nil,
+		Diff:    diff,
+	})
+}
+
+// authorize checks that the token user is allowed to update the resource in its namespace
+func (h *dryRunDiffHandler) authorize(ctx context.Context, token, resource string, o client.Object) error {
+	review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := h.k8s.Create(ctx, review); err != nil {
+		return fmt.Errorf("unable to review the token: %w", err)
+	}
+	if !review.Status.Authenticated {
+		return fmt.Errorf("invalid token")
+	}
+
+	user := review.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	access := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		User:   user.Username,
+		UID:    user.UID,
+		Groups: user.Groups,
+		Extra:  extra,
+		ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace: o.GetNamespace(),
+			Verb:      "update",
+			Group:     v1alpha1.GroupVersion.Group,
+			Resource:  resource,
+			Name:      o.GetName(),
+		},
+	}}
+	if err := h.k8s.Create(ctx, access); err != nil {
+		return fmt.Errorf("unable to review access: %w", err)
+	}
+	if !access.Status.Allowed {
+		return fmt.Errorf("user %q is not allowed to update %s %s/%s", user.Username, resource, o.GetNamespace(), o.GetName())
+	}
+	return nil
+}
+
+// serviceDiff compares the desired service with the live one, nil live service means it doesn't exist.
+// The user config is built the same way as on apply.
+// Only the options set in the resource are compared, the live service has defaults for the rest
+func serviceDiff(adapter serviceAdapter, live *aiven.Service) ([]fieldDiff, error) {
+	if live == nil {
+		live = &aiven.Service{}
+	}
+
+	spec := adapter.getServiceCommonSpec()
+	diff := make([]fieldDiff, 0)
+	add := func(field string, liveValue, desired interface{}) {
+		if !reflect.DeepEqual(liveValue, desired) {
+			diff = append(diff, fieldDiff{Field: field, Live: liveValue, Desired: desired})
+		}
+	}
+
+	add("plan", live.Plan, spec.Plan)
+	if spec.CloudName != "" {
+		add("cloudName", live.CloudName, spec.CloudName)
+	}
+	if spec.MaintenanceWindowDow != "" {
+		add("maintenanceWindowDow", live.MaintenanceWindow.DayOfWeek, spec.MaintenanceWindowDow)
+	}
+	if spec.MaintenanceWindowTime != "" {
+		add("maintenanceWindowTime", live.MaintenanceWindow.TimeOfDay, spec.MaintenanceWindowTime)
+	}
+	if spec.TerminationProtection != nil {
+		add("terminationProtection", live.TerminationProtection, *spec.TerminationProtection)
+	}
+	if d := adapter.getDiskSpace(); d != "" {
+		add("diskSpace", live.DiskSpaceMB, v1alpha1.ConvertDiscSpace(d))
+	}
+
+	// Create only options can't be changed for an existing service
+	groups := []string{"update"}
+	if live.Name == "" {
+		groups = append(groups, "create")
+	}
+	desired, err := UserConfigurationToAPIV2(adapter.getUserConfig(), groups)
+	if err != nil {
+		return nil, err
+	}
+
+	// Normalizes types, so numbers can be compared with values received from the API
+	normalized, err := normalizeUserConfig(desired)
+	if err != nil {
+		return nil, err
+	}
+	userConfigDiff("userConfig", normalized, live.UserConfig, add)
+
+	// Credentials, like migration.password, are never shown
+	for i, d := range diff {
+		for _, k := range strings.Split(d.Field, ".") {
+			if isRedactedKey(k) {
+				diff[i].Live, diff[i].Desired = redactedValue, redactedValue
+				break
+			}
+		}
+	}
+	return diff, nil
+}
+
+// userConfigDiff compares nested objects recursively, lists and scalars are compared as a whole
+func userConfigDiff(prefix string, desired, live map[string]interface{}, add func(string, interface{}, interface{})) {
+	keys := make([]string, 0, len(desired))
+	for k := range desired {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		field := prefix + "." + k
+		if m, ok := desired[k].(map[string]interface{}); ok {
+			lm, _ := live[k].(map[string]interface{})
+			userConfigDiff(field, m, lm, add)
+			continue
+		}
+		add(field, live[k], desired[k])
+	}
+}
+
+// isDryRun returns true if the instance changes must be planned, but not applied
+func isDryRun(o client.Object) bool {
+	return o.GetAnnotations()[dryRunAnnotation] == "true"
+}
+
+// dryRun records the changes the handler would apply to the instance as an event and in the status.
+// Nothing is applied until the annotation is removed
+func (i instanceReconcilerHelper) dryRun(ctx context.Context, o client.Object) error {
+	h, ok := i.h.(planHandler)
+	if !ok {
+		i.rec.Event(o, corev1.EventTypeWarning, eventDryRunPlan, "dry-run plan is not supported for the kind, changes are not applied")
+		return nil
+	}
+
+	diff, err := h.plan(i.avn, o)
+	if err != nil {
+		return fmt.Errorf("unable to plan changes: %w", err)
+	}
+
+	changes := make([]string, 0, len(diff))
+	for _, d := range diff {
+		changes = append(changes, fmt.Sprintf("%s: %v -> %v", d.Field, d.Live, d.Desired))
+	}
+	msg := "no changes"
+	if len(changes) > 0 {
+		msg = strings.Join(changes, ", ")
+	}
+	i.rec.Eventf(o, corev1.EventTypeNormal, eventDryRunPlan, "generation %d is not applied in the dry-run mode: %s", o.GetGeneration(), msg)
+	return i.updateStatus(ctx, o)
+}
+
+// plan computes the changes createOrUpdate would apply to the service, stores them in status.dryRunPlan
+func (h *genericServiceHandler) plan(a AivenClient, object client.Object) ([]fieldDiff, error) {
+	o, err := h.fabric(a, object)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := a.Services().Get(o.getServiceCommonSpec().Project, o.getObjectMeta().Name)
+	if err != nil && !aiven.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get service from Aiven: %w", err)
+	}
+
+	diff, err := serviceDiff(o, live)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &v1alpha1.ServiceDryRunPlan{Generation: object.GetGeneration(), Exists: live != nil}
+	for _, d := range diff {
+		c := v1alpha1.DryRunChange{Field: d.Field}
+		if d.Live != nil {
+			b, err := json.Marshal(d.Live)
+			if err != nil {
+				return nil, err
+			}
+			c.Live = string(b)
+		}
+		b, err := json.Marshal(d.Desired)
+		if err != nil {
+			return nil, err
+		}
+		c.Desired = string(b)
+		plan.Changes = append(plan.Changes, c)
+	}
+	o.getServiceStatus().DryRunPlan = plan
+	return diff, nil
+}
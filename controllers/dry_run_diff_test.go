@@ -0,0 +1,206 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aiven/aiven-go-client"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+	pguserconfig "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/service/pg"
+)
+
+func Test_serviceDiff(t *testing.T) {
+	version, jit := "15", true
+	pg := &v1alpha1.PostgreSQL{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "default"},
+		Spec: v1alpha1.PostgreSQLSpec{
+			ServiceCommonSpec: v1alpha1.ServiceCommonSpec{Plan: "business-4", CloudName: "google-europe-west1"},
+			UserConfig: &pguserconfig.PgUserConfig{
+				PgVersion: &version,
+				Pg:        &pguserconfig.Pg{Jit: &jit},
+			},
+		},
+	}
+	adapter, err := newPostgresSQLAdapter(nil, pg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	live := &aiven.Service{
+		Name:      "pg",
+		Plan:      "startup-4",
+		CloudName: "google-europe-west1",
+		UserConfig: map[string]interface{}{
+			"pg_version": "15",
+			"pg":         map[string]interface{}{"jit": false, "max_connections": 100.0},
+		},
+	}
+	got, err := serviceDiff(adapter, live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []fieldDiff{
+		{Field: "plan", Live: "startup-4", Desired: "business-4"},
+		{Field: "userConfig.pg.jit", Live: false, Desired: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("serviceDiff() = %v, want %v", got, want)
+	}
+
+	// The service doesn't exist, everything is new
+	got, err = serviceDiff(adapter, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 {
+		t.Errorf("serviceDiff() = %v, want 4 fields", got)
+	}
+}
+
+func Test_dryRun(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	pg := &v1alpha1.PostgreSQL{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "default", Generation: 2, Annotations: map[string]string{dryRunAnnotation: "true"}},
+		Spec: v1alpha1.PostgreSQLSpec{ServiceCommonSpec: v1alpha1.ServiceCommonSpec{
+			Project: "foo",
+			Plan:    "business-4",
+		}},
+	}
+	k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pg).Build()
+	rec := record.NewFakeRecorder(10)
+	avn := &mockAivenClient{services: &mockServices{GetFunc: func(project, service string) (*aiven.Service, error) {
+		return &aiven.Service{Name: service, Plan: "startup-4"}, nil
+	}}}
+	i := instanceReconcilerHelper{k8s: k8s, avn: avn, rec: rec, h: newGenericServiceHandler(newPostgresSQLAdapter, k8s, rec)}
+
+	if !isDryRun(pg) {
+		t.Fatal("isDryRun() = false, want true")
+	}
+	if err := i.dryRun(context.Background(), pg); err != nil {
+		t.Fatal(err)
+	}
+	if e := <-rec.Events; e != "Normal DryRunPlan generation 2 is not applied in the dry-run mode: plan: startup-4 -> business-4" {
+		t.Errorf("unexpected event %q", e)
+	}
+
+	stored := &v1alpha1.PostgreSQL{}
+	if err := k8s.Get(context.Background(), types.NamespacedName{Name: "pg", Namespace: "default"}, stored); err != nil {
+		t.Fatal(err)
+	}
+	want := &v1alpha1.ServiceDryRunPlan{
+		Generation: 2,
+		Exists:     true,
+		Changes:    []v1alpha1.DryRunChange{{Field: "plan", Live: `"startup-4"`, Desired: `"business-4"`}},
+	}
+	if !reflect.DeepEqual(stored.Status.DryRunPlan, want) {
+		t.Errorf("status.dryRunPlan = %+v, want %+v", stored.Status.DryRunPlan, want)
+	}
+
+	// Handlers without plans don't apply changes either
+	i.h = DatabaseHandler{}
+	if err := i.dryRun(context.Background(), &v1alpha1.Database{}); err != nil {
+		t.Fatal(err)
+	}
+	if e := <-rec.Events; !strings.HasPrefix(e, "Warning DryRunPlan") {
+		t.Errorf("unexpected event %q", e)
+	}
+}
+
+// reviewClient answers token and access reviews, the users in allowed may update resources
+type reviewClient struct {
+	client.Client
+	allowed map[string]bool
+}
+
+func (c *reviewClient) Create(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+	switch o := obj.(type) {
+	case *authenticationv1.TokenReview:
+		if _, ok := c.allowed[o.Spec.Token]; ok {
+			o.Status.Authenticated = true
+			o.Status.User.Username = o.Spec.Token
+		}
+	case *authorizationv1.SubjectAccessReview:
+		a := o.Spec.ResourceAttributes
+		o.Status.Allowed = c.allowed[o.Spec.User] && a.Verb == "update" && a.Resource == "postgresqls" && a.Namespace == "default"
+	}
+	return nil
+}
+
+func Test_dryRunDiffHandlerAuthorization(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	h := &dryRunDiffHandler{
+		k8s:    &reviewClient{allowed: map[string]bool{"admin": true, "viewer": false}},
+		scheme: scheme,
+	}
+
+	manifest := `{"apiVersion": "aiven.io/v1alpha1", "kind": "PostgreSQL", "metadata": {"name": "pg"}, "spec": {"project": "foo", "plan": "startup-4"}}`
+	for token, want := range map[string]int{"": http.StatusUnauthorized, "unknown": http.StatusForbidden, "viewer": http.StatusForbidden} {
+		r := httptest.NewRequest(http.MethodPost, dryRunDiffPath, strings.NewReader(manifest))
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != want {
+			t.Errorf("token %q status = %d, want %d", token, w.Code, want)
+		}
+	}
+
+	pg := &v1alpha1.PostgreSQL{ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "default"}}
+	if err := h.authorize(context.Background(), "admin", "postgresqls", pg); err != nil {
+		t.Errorf("authorize() error = %v, want nil", err)
+	}
+	pg.Namespace = "other"
+	if err := h.authorize(context.Background(), "admin", "postgresqls", pg); err == nil {
+		t.Error("authorize() must reject other namespaces")
+	}
+}
+
+func Test_serviceDiffRedacted(t *testing.T) {
+	password := "new-password"
+	pg := &v1alpha1.PostgreSQL{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg"},
+		Spec: v1alpha1.PostgreSQLSpec{
+			ServiceCommonSpec: v1alpha1.ServiceCommonSpec{Plan: "startup-4"},
+			UserConfig:        &pguserconfig.PgUserConfig{Migration: &pguserconfig.Migration{Host: "pg.example.com", Port: 5432, Password: &password}},
+		},
+	}
+	adapter, err := newPostgresSQLAdapter(nil, pg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	live := &aiven.Service{Name: "pg", Plan: "startup-4", UserConfig: map[string]interface{}{
+		"migration": map[string]interface{}{"host": "pg.example.com", "port": 5432.0, "password": "old-password"},
+	}}
+	got, err := serviceDiff(adapter, live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []fieldDiff{{Field: "userConfig.migration.password", Live: redactedValue, Desired: redactedValue}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("serviceDiff() = %v, want %v", got, want)
+	}
+}
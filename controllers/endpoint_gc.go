@@ -0,0 +1,141 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/aiven/aiven-go-client"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+// endpointGCInterval how often the orphaned endpoints are looked for
+const endpointGCInterval = time.Hour
+
+// managedEndpointNameRe matches the names of the endpoints created by the operator, see managedEndpointName
+var managedEndpointNameRe = regexp.MustCompile("^" + managedEndpointPrefix + ".+-[0-9a-f]{8}$")
+
+// endpointGC looks for the endpoints created along with integrations, which no integration references anymore,
+// for instance, left by a timed out finalizer. The endpoints are reported, or deleted if gc is set.
+// Endpoints created outside the operator are never touched
+type endpointGC struct {
+	k8s client.Client
+	log logr.Logger
+	rec record.EventRecorder
+
+	// newClient returns Aiven client authorized with the integration token
+	newClient func(ctx context.Context, si *v1alpha1.ServiceIntegration) (AivenClient, error)
+
+	// gc deletes the orphaned endpoints, otherwise they are reported only
+	gc bool
+
+	interval time.Duration
+}
+
+// Start implements manager.Runnable
+func (g *endpointGC) Start(ctx context.Context) error {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := g.run(ctx); err != nil {
+				g.log.Error(err, "unable to check orphaned endpoints")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, only the leader deletes endpoints
+func (g *endpointGC) NeedLeaderElection() bool {
+	return true
+}
+
+// run checks the projects, which have integrations with managed endpoints
+func (g *endpointGC) run(ctx context.Context) error {
+	list := &v1alpha1.ServiceIntegrationList{}
+	if err := g.k8s.List(ctx, list); err != nil {
+		return fmt.Errorf("unable to list service integrations: %w", err)
+	}
+
+	checked := make(map[string]bool)
+	for i := range list.Items {
+		si := &list.Items[i]
+		if si.Status.EndpointID == "" || checked[si.Spec.Project] || !si.DeletionTimestamp.IsZero() {
+			continue
+		}
+		checked[si.Spec.Project] = true
+
+		avn, err := g.newClient(ctx, si)
+		if err != nil {
+			return err
+		}
+		if err = g.checkProject(avn, si, list.Items); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkProject reports or deletes the orphaned endpoints of the integration project, the events go to the integration
+func (g *endpointGC) checkProject(avn AivenClient, si *v1alpha1.ServiceIntegration, integrations []v1alpha1.ServiceIntegration) error {
+	endpoints, err := avn.ServiceIntegrationEndpoints().List(si.Spec.Project)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range orphanedEndpoints(endpoints, integrations) {
+		if !g.gc {
+			g.rec.Eventf(si, corev1.EventTypeWarning, eventOrphanedEndpoint,
+				"%s endpoint %q (%s) in project %q is not used by any ServiceIntegration", e.EndpointType, e.EndpointName, e.EndpointID, si.Spec.Project)
+			continue
+		}
+
+		err = avn.ServiceIntegrationEndpoints().Delete(si.Spec.Project, e.EndpointID)
+		if err != nil && !aiven.IsNotFound(err) {
+			return fmt.Errorf("unable to delete orphaned endpoint %q: %w", e.EndpointID, err)
+		}
+		g.log.Info("deleted orphaned endpoint", "project", si.Spec.Project, "endpoint", e.EndpointName, "id", e.EndpointID)
+		g.rec.Eventf(si, corev1.EventTypeNormal, eventOrphanedEndpoint,
+			"deleted %s endpoint %q (%s) in project %q not used by any ServiceIntegration", e.EndpointType, e.EndpointName, e.EndpointID, si.Spec.Project)
+	}
+	return nil
+}
+
+// orphanedEndpoints returns the endpoints created by the operator, which are neither referenced by the integrations by ID
+// nor are about to be adopted by name. Managed endpoints are named after the integration namespace and name
+func orphanedEndpoints(endpoints []*aiven.ServiceIntegrationEndpoint, integrations []v1alpha1.ServiceIntegration) []*aiven.ServiceIntegrationEndpoint {
+	used := make(map[string]bool)
+	for _, si := range integrations {
+		used[si.Status.EndpointID] = true
+		used[si.Spec.SourceEndpointID] = true
+		used[si.Spec.DestinationEndpointID] = true
+		if si.Status.EndpointID == "" {
+			used[managedEndpointName(&si)] = true
+		}
+	}
+
+	var orphaned []*aiven.ServiceIntegrationEndpoint
+	for _, e := range endpoints {
+		if e.EndpointType != endpointTypeDatadog && e.EndpointType != endpointTypeExternalSchemaRegistry {
+			continue
+		}
+		if !managedEndpointNameRe.MatchString(e.EndpointName) {
+			continue
+		}
+		if !used[e.EndpointID] && !used[e.EndpointName] {
+			orphaned = append(orphaned, e)
+		}
+	}
+	return orphaned
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aiven/aiven-go-client"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+func managedEndpointNameOf(namespace, name string) string {
+	return managedEndpointName(&v1alpha1.ServiceIntegration{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}})
+}
+
+func Test_orphanedEndpoints(t *testing.T) {
+	endpoints := []*aiven.ServiceIntegrationEndpoint{
+		{EndpointID: "used", EndpointName: managedEndpointNameOf("default", "datadog"), EndpointType: endpointTypeDatadog},
+		{EndpointID: "referenced", EndpointName: managedEndpointNameOf("default", "registry"), EndpointType: endpointTypeExternalSchemaRegistry},
+		{EndpointID: "adopted", EndpointName: managedEndpointNameOf("default", "new-datadog"), EndpointType: endpointTypeDatadog},
+		{EndpointID: "orphaned", EndpointName: managedEndpointNameOf("default", "deleted-datadog"), EndpointType: endpointTypeDatadog},
+		{EndpointID: "external", EndpointName: "datadog", EndpointType: endpointTypeDatadog},
+		{EndpointID: "unmanaged", EndpointName: managedEndpointNameOf("default", "prometheus"), EndpointType: "prometheus"},
+	}
+	integrations := []v1alpha1.ServiceIntegration{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "datadog", Namespace: "default", UID: "1"},
+			Status:     v1alpha1.ServiceIntegrationStatus{EndpointID: "used"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "mirror", Namespace: "default", UID: "2"},
+			Spec:       v1alpha1.ServiceIntegrationSpec{DestinationEndpointID: "referenced"},
+			Status:     v1alpha1.ServiceIntegrationStatus{ID: "integration"},
+		},
+		{
+			// The endpoint is created, but the status is not saved yet
+			ObjectMeta: metav1.ObjectMeta{Name: "new-datadog", Namespace: "default", UID: "3"},
+		},
+	}
+
+	orphaned := orphanedEndpoints(endpoints, integrations)
+	if len(orphaned) != 1 || orphaned[0].EndpointID != "orphaned" {
+		t.Errorf("orphanedEndpoints() = %v, want the orphaned endpoint only", orphaned)
+	}
+}
+
+func Test_endpointGC(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	si := &v1alpha1.ServiceIntegration{ObjectMeta: metav1.ObjectMeta{Name: "datadog", Namespace: "default"}}
+	si.Spec.Project = "my-project"
+	si.Status.EndpointID = "used"
+	endpoints := []*aiven.ServiceIntegrationEndpoint{
+		{EndpointID: "used", EndpointName: managedEndpointNameOf("default", "datadog"), EndpointType: endpointTypeDatadog},
+		{EndpointID: "orphaned", EndpointName: managedEndpointNameOf("default", "deleted"), EndpointType: endpointTypeDatadog},
+		{EndpointID: "external", EndpointName: "datadog", EndpointType: endpointTypeDatadog},
+	}
+
+	for _, gc := range []bool{false, true} {
+		var lists int
+		var deleted []string
+		avn := &mockAivenClient{serviceIntegrationEndpoints: &mockServiceIntegrationEndpoints{
+			ListFunc: func(project string) ([]*aiven.ServiceIntegrationEndpoint, error) {
+				lists++
+				return endpoints, nil
+			},
+			DeleteFunc: func(project, endpointID string) error {
+				deleted = append(deleted, endpointID)
+				return nil
+			},
+		}}
+		rec := record.NewFakeRecorder(10)
+		g := &endpointGC{
+			k8s:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(si.DeepCopy()).Build(),
+			log:       logr.Discard(),
+			rec:       rec,
+			newClient: func(context.Context, *v1alpha1.ServiceIntegration) (AivenClient, error) { return avn, nil },
+			gc:        gc,
+		}
+		if err := g.run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if lists != 1 {
+			t.Errorf("gc=%t: endpoints listed %d times, want once per project", gc, lists)
+		}
+
+		if len(rec.Events) != 1 {
+			t.Fatalf("gc=%t: got %d events, want 1", gc, len(rec.Events))
+		}
+		event := <-rec.Events
+		if !strings.Contains(event, "orphaned") {
+			t.Errorf("gc=%t: event %q is not about the orphaned endpoint", gc, event)
+		}
+
+		if gc {
+			if len(deleted) != 1 || deleted[0] != "orphaned" {
+				t.Errorf("deleted endpoints = %v, want the orphaned endpoint only", deleted)
+			}
+			if !strings.HasPrefix(event, "Normal") {
+				t.Errorf("event = %q, want a Normal event", event)
+			}
+		} else {
+			if len(deleted) != 0 {
+				t.Errorf("deleted endpoints = %v, want none without gc", deleted)
+			}
+			if !strings.HasPrefix(event, "Warning") {
+				t.Errorf("event = %q, want a Warning event", event)
+			}
+		}
+	}
+}
@@ -1,29 +1,87 @@
 package controllers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aiven/aiven-go-client"
+	"github.com/stoewer/go-strcase"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
 )
 
-func newGenericServiceHandler(fabric serviceAdapterFabric) Handlers {
-	return &genericServiceHandler{fabric: fabric}
+const (
+	// conditionTypeForked reports fork progress of the services created with spec.forkFrom
+	conditionTypeForked = "Forked"
+
+	// conditionTypePrometheusReady is False while the service with connInfoSecretTarget.prometheus has no prometheus integration
+	conditionTypePrometheusReady = "PrometheusReady"
+
+	// prometheusCheckInterval how often the service waiting for the prometheus integration is checked
+	prometheusCheckInterval = time.Minute
+
+	// eventPlanRemapped the live service plan differs from the spec
+	eventPlanRemapped = "PlanRemapped"
+)
+
+// serviceKind a service resource kind handled by genericServiceHandler
+type serviceKind struct {
+	fabric  serviceAdapterFabric
+	newList func() client.ObjectList
+
+	// resource the plural resource name, for instance, for access reviews
+	resource string
+}
+
+// serviceKinds the service kinds by the kind name
+var serviceKinds = map[string]serviceKind{
+	"Cassandra":    {newCassandraAdapter, func() client.ObjectList { return &v1alpha1.CassandraList{} }, "cassandras"},
+	"Clickhouse":   {newClickhouseAdapter, func() client.ObjectList { return &v1alpha1.ClickhouseList{} }, "clickhouses"},
+	"Grafana":      {newGrafanaAdapter, func() client.ObjectList { return &v1alpha1.GrafanaList{} }, "grafanas"},
+	"Kafka":        {newKafkaAdapter, func() client.ObjectList { return &v1alpha1.KafkaList{} }, "kafkas"},
+	"KafkaConnect": {newKafkaConnectAdapter, func() client.ObjectList { return &v1alpha1.KafkaConnectList{} }, "kafkaconnects"},
+	"MySQL":        {newMySQLAdapter, func() client.ObjectList { return &v1alpha1.MySQLList{} }, "mysqls"},
+	"OpenSearch":   {newOpenSearchAdapter, func() client.ObjectList { return &v1alpha1.OpenSearchList{} }, "opensearches"},
+	"PostgreSQL":   {newPostgresSQLAdapter, func() client.ObjectList { return &v1alpha1.PostgreSQLList{} }, "postgresqls"},
+	"Redis":        {newRedisAdapter, func() client.ObjectList { return &v1alpha1.RedisList{} }, "redis"},
+}
+
+func newGenericServiceHandler(fabric serviceAdapterFabric, k8s client.Client, rec record.EventRecorder) Handlers {
+	return &genericServiceHandler{fabric: fabric, k8s: k8s, rec: rec}
 }
 
 // genericServiceHandler provides common CRUD management for all service types using serviceAdapter,
 // which turns specific service (mysql, redis) into a generic.
 type genericServiceHandler struct {
 	fabric serviceAdapterFabric
+
+	// k8s reads the ip filter ConfigMap
+	k8s client.Client
+
+	// rec records service warnings
+	rec record.EventRecorder
 }
 
-func (h *genericServiceHandler) createOrUpdate(a *aiven.Client, object client.Object, refs []client.Object) error {
+func (h *genericServiceHandler) createOrUpdate(a AivenClient, object client.Object, refs []client.Object) error {
 	o, err := h.fabric(a, object)
 	if err != nil {
 		return err
@@ -41,12 +99,24 @@ func (h *genericServiceHandler) createOrUpdate(a *aiven.Client, object client.Ob
 		}
 	}
 
-	_, err = a.Services.Get(spec.Project, ometa.Name)
+	current, err := a.Services().Get(spec.Project, ometa.Name)
 	exists := err == nil
 	if !exists && !aiven.IsNotFound(err) {
 		return fmt.Errorf("failed to fetch service: %w", err)
 	}
 
+	// CIDR blocks from the ConfigMap are not stored as applied, they are added on every create or update
+	ipFilter, err := h.getIPFilter(object.GetNamespace(), spec.IPFilterConfigMapRef)
+	if err != nil {
+		return err
+	}
+
+	// The update group is stored, create only options can't be unset
+	appliedUserConfig, err := UserConfigurationToAPIV2(o.getUserConfig(), []string{"update"})
+	if err != nil {
+		return err
+	}
+
 	// Creates if not exists or updates existing service
 	var reason string
 	if !exists {
@@ -55,9 +125,21 @@ func (h *genericServiceHandler) createOrUpdate(a *aiven.Client, object client.Ob
 		if err != nil {
 			return err
 		}
+		if spec.ForkFrom != nil {
+			userConfig = withForkFrom(userConfig, spec.Project, spec.ForkFrom)
+		}
+		userConfig = withIPFilter(userConfig, ipFilter)
+
+		cloudName := spec.CloudName
+		if cloudName == "" {
+			cloudName, err = getDefaultCloud(a, spec.Project, refs)
+			if err != nil {
+				return err
+			}
+		}
 
 		req := aiven.CreateServiceRequest{
-			Cloud:                 spec.CloudName,
+			Cloud:                 cloudName,
 			DiskSpaceMB:           v1alpha1.ConvertDiscSpace(o.getDiskSpace()),
 			MaintenanceWindow:     getMaintenanceWindow(spec.MaintenanceWindowDow, spec.MaintenanceWindowTime),
 			Plan:                  spec.Plan,
@@ -79,7 +161,11 @@ func (h *genericServiceHandler) createOrUpdate(a *aiven.Client, object client.Ob
 			req.ServiceIntegrations = append(req.ServiceIntegrations, i)
 		}
 
-		_, err = a.Services.Create(spec.Project, req)
+		_, err = a.Services().Create(spec.Project, req)
+		if isConflictError(err) {
+			// The name of a recently deleted service is reserved until the deletion is complete
+			return fmt.Errorf("%w: %s", errServiceNameReserved, err)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to create service: %w", err)
 		}
@@ -89,23 +175,61 @@ func (h *genericServiceHandler) createOrUpdate(a *aiven.Client, object client.Ob
 		if err != nil {
 			return err
 		}
+		userConfig = withIPFilter(userConfig, ipFilter)
+
+		// The removed options are found in the full desired config, the partial diff would unset the unchanged ones
+		if fromAnyPointer(spec.UnsetRemovedUserConfig) {
+			lastApplied, err := getLastAppliedUserConfig(object)
+			if err != nil {
+				return err
+			}
+			userConfig = withRemovedUserConfig(userConfig, lastApplied)
+		}
+
+		if fromAnyPointer(spec.PartialUserConfigUpdate) {
+			userConfig, err = userConfigChanges(userConfig, current.UserConfig)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Both window fields are sent, the one not set is kept as is
+		window := getMaintenanceWindow(spec.MaintenanceWindowDow, spec.MaintenanceWindowTime)
+		if window != nil {
+			if window.DayOfWeek == "" {
+				window.DayOfWeek = current.MaintenanceWindow.DayOfWeek
+			}
+			if window.TimeOfDay == "" {
+				window.TimeOfDay = current.MaintenanceWindow.TimeOfDay
+			}
+		}
 
 		req := aiven.UpdateServiceRequest{
 			Cloud:                 spec.CloudName,
 			DiskSpaceMB:           v1alpha1.ConvertDiscSpace(o.getDiskSpace()),
-			MaintenanceWindow:     getMaintenanceWindow(spec.MaintenanceWindowDow, spec.MaintenanceWindowTime),
+			MaintenanceWindow:     window,
 			Plan:                  spec.Plan,
-			Powered:               true,
+			Powered:               spec.IsPowered(),
 			ProjectVPCID:          toOptionalStringPointer(projectVPCID),
 			TerminationProtection: fromAnyPointer(spec.TerminationProtection),
 			UserConfig:            userConfig,
 		}
-		_, err = a.Services.Update(spec.Project, ometa.Name, req)
-		if err != nil {
+		_, err = a.Services().Update(spec.Project, ometa.Name, req)
+		if err != nil && !isNotChangedError(err) {
 			return fmt.Errorf("failed to update service: %w", err)
 		}
 	}
 
+	if err = updateServiceTags(a, spec.Project, ometa.Name, object, spec.Tags); err != nil {
+		return err
+	}
+	if err = setLastAppliedUserConfig(object, appliedUserConfig); err != nil {
+		return err
+	}
+	if spec.IPFilterConfigMapRef != nil {
+		metav1.SetMetaDataAnnotation(ometa, ipFilterHashAnnotation, hashValue(strings.Join(ipFilter, ",")))
+	}
+
 	status := o.getServiceStatus()
 	meta.SetStatusCondition(&status.Conditions,
 		getInitializedCondition(reason, "Instance was created or update on Aiven side"))
@@ -120,38 +244,98 @@ func (h *genericServiceHandler) createOrUpdate(a *aiven.Client, object client.Ob
 	return nil
 }
 
-func (h *genericServiceHandler) delete(a *aiven.Client, object client.Object) (bool, error) {
+func (h *genericServiceHandler) delete(a AivenClient, object client.Object) (bool, error) {
 	o, err := h.fabric(a, object)
 	if err != nil {
 		return false, err
 	}
 
 	spec := o.getServiceCommonSpec()
-	if fromAnyPointer(spec.TerminationProtection) {
+	clearProtection := fromAnyPointer(spec.ClearTerminationProtectionOnDelete)
+	if fromAnyPointer(spec.TerminationProtection) && !clearProtection {
 		return false, errTerminationProtectionOn
 	}
 
-	err = a.Services.Delete(spec.Project, o.getObjectMeta().Name)
+	name := o.getObjectMeta().Name
+	err = a.Services().Delete(spec.Project, name)
+
+	// The protection might be enabled outside the operator too
+	if isTerminationProtectionError(err) && clearProtection {
+		path := fmt.Sprintf("/v1/project/%s/service/%s", url.PathEscape(spec.Project), url.PathEscape(name))
+		err = a.RawPut(path, map[string]interface{}{"termination_protection": false}, nil)
+		if err != nil {
+			return false, fmt.Errorf("failed to disable termination protection: %w", err)
+		}
+		err = a.Services().Delete(spec.Project, name)
+	}
 	if err == nil || aiven.IsNotFound(err) {
 		return true, nil
 	}
+	if isTerminationProtectionError(err) {
+		return false, fmt.Errorf("%w at Aiven", errTerminationProtectionOn)
+	}
 
 	return false, fmt.Errorf("failed to delete service in Aiven: %w", err)
 }
 
-func (h *genericServiceHandler) get(a *aiven.Client, object client.Object) (*corev1.Secret, error) {
+func (h *genericServiceHandler) get(a AivenClient, object client.Object) (*corev1.Secret, error) {
 	o, err := h.fabric(a, object)
 	if err != nil {
 		return nil, err
 	}
 
-	s, err := a.Services.Get(o.getServiceCommonSpec().Project, o.getObjectMeta().Name)
+	s, extras, err := getServiceWithExtras(a, o.getServiceCommonSpec().Project, o.getObjectMeta().Name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get service from Aiven: %w", err)
 	}
 
 	status := o.getServiceStatus()
 	status.State = s.State
+	if !isDryRun(object) {
+		status.DryRunPlan = nil
+	}
+	status.ServiceSnapshot = nil
+	if fromAnyPointer(o.getServiceCommonSpec().ServiceSnapshot) {
+		status.ServiceSnapshot, err = newServiceSnapshot(s)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if isPlanRemapped(status.PlanDetails, s.Plan, o.getServiceCommonSpec().Plan) {
+		h.rec.Eventf(object, corev1.EventTypeWarning, eventPlanRemapped,
+			"service plan is %q on Aiven side, differs from the spec plan %q", s.Plan, o.getServiceCommonSpec().Plan)
+	}
+	status.PlanDetails = newServicePlanDetails(s, extras)
+	status.ConnectionPools = nil
+	if o.getServiceType() == "pg" {
+		status.ConnectionPools = newServiceConnectionPools(s.ConnectionPools)
+	}
+	if setServiceWarningCondition(&status.Conditions, extras.Notifications) {
+		c := meta.FindStatusCondition(status.Conditions, conditionTypeWarning)
+		h.rec.Event(object, corev1.EventTypeWarning, c.Reason, c.Message)
+	}
+
+	if o.getServiceCommonSpec().ForkFrom != nil {
+		meta.SetStatusCondition(&status.Conditions, getForkedCondition(s.State))
+	}
+
+	if err = h.checkMaintenance(a, object, o, s, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if s.State == serviceStatePowerOff && !o.getServiceCommonSpec().IsPowered() {
+		meta.SetStatusCondition(&status.Conditions,
+			getRunningCondition(metav1.ConditionFalse, reasonPoweredOff, "Instance is powered off on Aiven side"))
+		return nil, nil
+	}
+
+	// Powered off is a stable state, the instance is polled again once it's being powered on
+	if isPoweredOff(object) {
+		meta.SetStatusCondition(&status.Conditions,
+			getRunningCondition(metav1.ConditionUnknown, "PoweringOn", "Instance is being powered on"))
+	}
+
 	if s.State == "RUNNING" {
 		meta.SetStatusCondition(&status.Conditions,
 			getRunningCondition(metav1.ConditionTrue, "CheckRunning", "Instance is running on Aiven side"))
@@ -160,13 +344,94 @@ func (h *genericServiceHandler) get(a *aiven.Client, object client.Object) (*cor
 
 		// Some services get secrets after they are running only,
 		// like ip addresses (hosts)
-		return o.newSecret(s)
+		secret, err := o.newSecret(s)
+		if err != nil || secret == nil {
+			return secret, err
+		}
+
+		t, ok := object.(serviceConnInfoSecretTargetObject)
+		if !ok || !fromAnyPointer(t.GetServiceConnInfoSecretTarget().Prometheus) {
+			meta.RemoveStatusCondition(&status.Conditions, conditionTypePrometheusReady)
+		} else {
+			prometheus, err := getPrometheusConnInfo(a, o.getServiceCommonSpec().Project, s)
+			if errors.Is(err, errNoPrometheusIntegration) {
+				// The integration is usually created after the service is running,
+				// the secret goes without the scrape config until then
+				meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+					Type:    conditionTypePrometheusReady,
+					Status:  metav1.ConditionFalse,
+					Reason:  "NoPrometheusIntegration",
+					Message: err.Error(),
+				})
+				return secret, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+				Type:    conditionTypePrometheusReady,
+				Status:  metav1.ConditionTrue,
+				Reason:  "PrometheusIntegration",
+				Message: "Prometheus scrape config is in the secret",
+			})
+			if secret.StringData == nil {
+				secret.StringData = make(map[string]string, len(prometheus))
+			}
+			for k, v := range prometheus {
+				secret.StringData[k] = v
+			}
+		}
+		return secret, nil
 	}
 	return nil, nil
 }
 
+// getPrometheusConnInfo returns the service Prometheus scrape config:
+// the prometheus component URL and the basic auth credentials of the prometheus integration endpoint
+func getPrometheusConnInfo(a AivenClient, project string, s *aiven.Service) (map[string]string, error) {
+	integrations, err := a.ServiceIntegrations().List(project, s.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service integrations: %w", err)
+	}
+
+	var endpointID string
+	for _, i := range integrations {
+		if i.IntegrationType == "prometheus" && i.DestinationEndpointID != nil {
+			endpointID = *i.DestinationEndpointID
+			break
+		}
+	}
+	if endpointID == "" {
+		return nil, errNoPrometheusIntegration
+	}
+
+	endpoint, err := a.ServiceIntegrationEndpoints().Get(project, endpointID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prometheus integration endpoint: %w", err)
+	}
+	return newPrometheusConnInfo(s.Components, endpoint.UserConfig)
+}
+
+// newPrometheusConnInfo makes the scrape config from the service components and the endpoint user config
+func newPrometheusConnInfo(components []*aiven.ServiceComponents, userConfig map[string]interface{}) (map[string]string, error) {
+	for _, c := range components {
+		if c.Component != "prometheus" || c.Usage != "primary" {
+			continue
+		}
+
+		username, _ := userConfig["basic_auth_username"].(string)
+		password, _ := userConfig["basic_auth_password"].(string)
+		return map[string]string{
+			"PROMETHEUS_URL":      fmt.Sprintf("https://%s/metrics", net.JoinHostPort(c.Host, strconv.Itoa(c.Port))),
+			"PROMETHEUS_USERNAME": username,
+			"PROMETHEUS_PASSWORD": password,
+		}, nil
+	}
+	return nil, fmt.Errorf("service has no prometheus component")
+}
+
 // checkPreconditions not required for now by services to be implemented
-func (h *genericServiceHandler) checkPreconditions(a *aiven.Client, object client.Object) (bool, error) {
+func (h *genericServiceHandler) checkPreconditions(a AivenClient, object client.Object) (bool, error) {
 	o, err := h.fabric(a, object)
 	if err != nil {
 		return false, err
@@ -183,11 +448,237 @@ func (h *genericServiceHandler) checkPreconditions(a *aiven.Client, object clien
 			}
 		}
 	}
+
+	// The fork source must be running to create the service,
+	// is not required after the service is created
+	if spec.ForkFrom != nil && object.GetAnnotations()[processedGenerationAnnotation] == "" {
+		project := spec.ForkFrom.Project
+		if project == "" {
+			project = spec.Project
+		}
+		r, err := checkServiceTypeIsRunning(a, project, spec.ForkFrom.ServiceName, o.getServiceType())
+		if !(r && err == nil) {
+			return false, nil
+		}
+	}
 	return true, nil
 }
 
+// getDefaultCloud returns the cloud for services without cloudName:
+// the cloud of the referenced Project resource or the Aiven project default cloud.
+// Fails if the cloud is not available for the project
+func getDefaultCloud(a AivenClient, project string, refs []client.Object) (string, error) {
+	var cloudName string
+	if p := v1alpha1.FindProject(refs); p != nil {
+		cloudName = p.Spec.Cloud
+	}
+	if cloudName == "" {
+		p, err := a.Projects().Get(project)
+		if err != nil {
+			return "", fmt.Errorf("failed to get project default cloud: %w", err)
+		}
+		cloudName = p.DefaultCloud
+	}
+	if cloudName == "" {
+		// Aiven picks the cloud
+		return "", nil
+	}
+
+	clouds, err := getProjectClouds(a, project)
+	if err != nil {
+		return "", fmt.Errorf("failed to list project clouds: %w", err)
+	}
+	for _, c := range clouds {
+		if c == cloudName {
+			return cloudName, nil
+		}
+	}
+	return "", fmt.Errorf("project default cloud %q is not available for the project %q", cloudName, project)
+}
+
+// withForkFrom adds the fork source to the create user config
+func withForkFrom(userConfig map[string]interface{}, project string, src *v1alpha1.ServiceForkSource) map[string]interface{} {
+	if userConfig == nil {
+		userConfig = make(map[string]interface{})
+	}
+	userConfig["service_to_fork_from"] = src.ServiceName
+	if src.Project != "" && src.Project != project {
+		userConfig["project_to_fork_from"] = src.Project
+	}
+	return userConfig
+}
+
+// getForkedCondition returns fork progress, the forked service is rebuilt from the source backup until running
+func getForkedCondition(state string) metav1.Condition {
+	if state == "RUNNING" {
+		return metav1.Condition{
+			Type:    conditionTypeForked,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Forked",
+			Message: "Service data is restored from the source service",
+		}
+	}
+	return metav1.Condition{
+		Type:    conditionTypeForked,
+		Status:  metav1.ConditionUnknown,
+		Reason:  "Forking",
+		Message: fmt.Sprintf("Service data is being restored from the source service, the service state is %s", state),
+	}
+}
+
+// getAPIUserConfig returns the user config with both create and update options, as it is sent on create
+func (h *genericServiceHandler) getAPIUserConfig(object client.Object) (string, string, map[string]interface{}, error) {
+	o, err := h.fabric(nil, object)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	userConfig, err := UserConfigurationToAPIV2(o.getUserConfig(), []string{"create", "update"})
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	// Normalizes nested lists and objects, so they can be walked
+	userConfig, err = normalizeUserConfig(userConfig)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return o.getServiceCommonSpec().Project, o.getServiceType(), userConfig, nil
+}
+
+// isOutdated returns true if the ip filter ConfigMap has changed since the last update
+func (h *genericServiceHandler) isOutdated(object client.Object) (bool, error) {
+	o, err := h.fabric(nil, object)
+	if err != nil {
+		return false, err
+	}
+
+	ref := o.getServiceCommonSpec().IPFilterConfigMapRef
+	if ref == nil {
+		return false, nil
+	}
+
+	ipFilter, err := h.getIPFilter(object.GetNamespace(), ref)
+	if err != nil {
+		return false, err
+	}
+	return object.GetAnnotations()[ipFilterHashAnnotation] != hashValue(strings.Join(ipFilter, ",")), nil
+}
+
+// getIPFilter returns CIDR blocks from the ConfigMap key, separated by newlines or commas
+func (h *genericServiceHandler) getIPFilter(namespace string, ref *v1alpha1.IPFilterConfigMapReference) ([]string, error) {
+	if ref == nil {
+		return nil, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	err := h.k8s.Get(context.Background(), types.NamespacedName{Name: ref.Name, Namespace: namespace}, cm)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get ip filter config map: %w", err)
+	}
+
+	var networks []string
+	for _, n := range strings.FieldsFunc(cm.Data[ref.Key], func(r rune) bool { return r == '\n' || r == ',' }) {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
+		if _, _, err = net.ParseCIDR(n); err != nil && net.ParseIP(n) == nil {
+			return nil, fmt.Errorf("ip filter config map %q has invalid network %q", ref.Name, n)
+		}
+		networks = append(networks, n)
+	}
+	return networks, nil
+}
+
+// withIPFilter adds networks to the user config ip_filter, skips the ones which are already there
+func withIPFilter(userConfig map[string]interface{}, networks []string) map[string]interface{} {
+	if len(networks) == 0 {
+		return userConfig
+	}
+	if userConfig == nil {
+		userConfig = make(map[string]interface{})
+	}
+
+	ipFilter, _ := userConfig["ip_filter"].([]interface{})
+	known := make(map[string]bool, len(ipFilter))
+	for _, f := range ipFilter {
+		switch v := f.(type) {
+		case string:
+			known[v] = true
+		case map[string]interface{}:
+			if n, ok := v["network"].(string); ok {
+				known[n] = true
+			}
+		}
+	}
+
+	for _, n := range networks {
+		if !known[n] {
+			known[n] = true
+			ipFilter = append(ipFilter, map[string]interface{}{"network": n})
+		}
+	}
+	userConfig["ip_filter"] = ipFilter
+	return userConfig
+}
+
+// ipFilterConfigMapHandler enqueues services of the list type which reference the ConfigMap
+func ipFilterConfigMapHandler(k8s client.Client, fabric serviceAdapterFabric, list client.ObjectList) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(cm client.Object) []reconcile.Request {
+		l := list.DeepCopyObject().(client.ObjectList)
+		if err := k8s.List(context.Background(), l, client.InNamespace(cm.GetNamespace())); err != nil {
+			return nil
+		}
+
+		items, err := meta.ExtractList(l)
+		if err != nil {
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, item := range items {
+			o, err := fabric(nil, item.(client.Object))
+			if err != nil {
+				continue
+			}
+			if ref := o.getServiceCommonSpec().IPFilterConfigMapRef; ref != nil && ref.Name == cm.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(item.(client.Object))})
+			}
+		}
+		return requests
+	})
+}
+
+// newServiceSnapshot returns a copy of the service without users, connection info and other fields with secrets.
+// Sensitive values, for instance, in the user config, are redacted
+func newServiceSnapshot(s *aiven.Service) (*runtime.RawExtension, error) {
+	sanitized := *s
+	sanitized.Users = nil
+	sanitized.ConnectionPools = nil
+	sanitized.ConnectionInfo = aiven.ConnectionInfo{}
+	sanitized.URI = ""
+	sanitized.URIParams = nil
+
+	b, err := json.Marshal(&sanitized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make service snapshot: %w", err)
+	}
+
+	// The user config and other fields may keep credentials, like migration.password
+	var v interface{}
+	if err = json.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("failed to make service snapshot: %w", err)
+	}
+	b, err = json.Marshal(redactValue(v))
+	if err != nil {
+		return nil, fmt.Errorf("failed to make service snapshot: %w", err)
+	}
+	return &runtime.RawExtension{Raw: b}, nil
+}
+
 // serviceAdapterFabric returns serviceAdapter for specific service, like MySQL
-type serviceAdapterFabric func(*aiven.Client, client.Object) (serviceAdapter, error)
+type serviceAdapterFabric func(AivenClient, client.Object) (serviceAdapter, error)
 
 // serviceAdapter turns client.Object into a generic thing
 type serviceAdapter interface {
@@ -207,3 +698,147 @@ func fromAnyPointer[T any](v *T) T {
 	var t T
 	return t
 }
+
+// serviceNotification is a service warning, like disk usage close to the plan limit
+type serviceNotification struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// serviceExtras service fields which are not exposed by aiven.Service
+type serviceExtras struct {
+	Notifications []serviceNotification `json:"service_notifications"`
+	NodeCPUCount  int                   `json:"node_cpu_count"`
+	NodeMemoryMB  float64               `json:"node_memory_mb"`
+}
+
+// getServiceWithExtras returns the service and its fields which are not exposed by aiven.Service,
+// both are decoded from the same raw response.
+// The extras are informational: if they can't be decoded, the service goes without them
+func getServiceWithExtras(a AivenClient, project, serviceName string) (*aiven.Service, *serviceExtras, error) {
+	var r struct {
+		Service json.RawMessage `json:"service"`
+	}
+	err := a.RawGet(fmt.Sprintf("/v1/project/%s/service/%s", url.PathEscape(project), url.PathEscape(serviceName)), &r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s := new(aiven.Service)
+	if err = json.Unmarshal(r.Service, s); err != nil {
+		return nil, nil, fmt.Errorf("invalid service response: %w", err)
+	}
+
+	extras := new(serviceExtras)
+	if err = json.Unmarshal(r.Service, extras); err != nil {
+		ctrl.Log.WithName("service").Info("unable to read service notifications and node details", "service", serviceName, "error", err.Error())
+		extras = new(serviceExtras)
+	}
+	return s, extras, nil
+}
+
+// getProjectClouds returns names of the clouds available for the project, not exposed by the client
+func getProjectClouds(a AivenClient, project string) ([]string, error) {
+	var r struct {
+		Clouds []struct {
+			CloudName string `json:"cloud_name"`
+		} `json:"clouds"`
+	}
+	err := a.RawGet(fmt.Sprintf("/v1/project/%s/clouds", url.PathEscape(project)), &r)
+	if err != nil {
+		return nil, err
+	}
+
+	clouds := make([]string, 0, len(r.Clouds))
+	for _, c := range r.Clouds {
+		clouds = append(clouds, c.CloudName)
+	}
+	return clouds, nil
+}
+
+// isConflictError returns true if Aiven responded with 409 Conflict
+func isConflictError(err error) bool {
+	var e aiven.Error
+	return errors.As(err, &e) && e.Status == http.StatusConflict
+}
+
+// isPlanRemapped returns true if the live plan differs from the spec plan and has changed since the last check,
+// Aiven may remap plan names
+func isPlanRemapped(last *v1alpha1.ServicePlanDetails, livePlan, specPlan string) bool {
+	if livePlan == specPlan {
+		return false
+	}
+	return last == nil || last.Plan != livePlan
+}
+
+// newServicePlanDetails returns plan-derived limits of the service
+func newServicePlanDetails(s *aiven.Service, extras *serviceExtras) *v1alpha1.ServicePlanDetails {
+	d := &v1alpha1.ServicePlanDetails{
+		Plan:         s.Plan,
+		NodeCount:    s.NodeCount,
+		NodeCPUCount: extras.NodeCPUCount,
+		NodeMemoryMB: int(extras.NodeMemoryMB),
+		DiskSpaceMB:  s.DiskSpaceMB,
+	}
+
+	// PostgreSQL exposes max connections in metadata
+	if m, ok := s.Metadata.(map[string]interface{}); ok {
+		if v, ok := m["max_connections"].(float64); ok {
+			d.MaxConnections = int(v)
+		}
+	}
+	return d
+}
+
+// newServiceConnectionPools returns the pools sorted by name, connection URIs are excluded as they contain passwords
+func newServiceConnectionPools(pools []*aiven.ConnectionPool) []v1alpha1.ServiceConnectionPool {
+	result := make([]v1alpha1.ServiceConnectionPool, 0, len(pools))
+	for _, p := range pools {
+		result = append(result, v1alpha1.ServiceConnectionPool{
+			Name:     p.PoolName,
+			Database: p.Database,
+			Username: p.Username,
+			Mode:     p.PoolMode,
+			Size:     p.PoolSize,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// setServiceWarningCondition sets non-fatal Warning condition from warning level notifications
+// or removes it if there are none. Returns true if the warning is new or has changed
+func setServiceWarningCondition(conditions *[]metav1.Condition, notifications []serviceNotification) bool {
+	var reasons, messages []string
+	for _, n := range notifications {
+		if n.Level == "warning" {
+			reasons = append(reasons, n.Type)
+			messages = append(messages, n.Message)
+		}
+	}
+
+	if len(messages) == 0 {
+		meta.RemoveStatusCondition(conditions, conditionTypeWarning)
+		return false
+	}
+
+	c := metav1.Condition{
+		Type:    conditionTypeWarning,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ServiceWarning",
+		Message: strings.Join(messages, "; "),
+	}
+
+	// Reason must be CamelCase, uses the notification type if there is only one
+	if len(reasons) == 1 && reasons[0] != "" {
+		c.Reason = strcase.UpperCamelCase(reasons[0])
+	}
+
+	old := meta.FindStatusCondition(*conditions, conditionTypeWarning)
+	changed := old == nil || old.Reason != c.Reason || old.Message != c.Message
+	meta.SetStatusCondition(conditions, c)
+	return changed
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// getCache keeps the last successful get result of running instances for a short time,
+// so repeated reconciles don't call Aiven API when nothing has changed
+type getCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[types.UID]getCacheEntry
+}
+
+type getCacheEntry struct {
+	key     string
+	secret  *corev1.Secret
+	expires time.Time
+}
+
+func newGetCache(ttl time.Duration) *getCache {
+	return &getCache{ttl: ttl, entries: make(map[types.UID]getCacheEntry)}
+}
+
+// getCacheKey changes on spec change or when reconcileNowAnnotation is set
+func getCacheKey(o client.Object) string {
+	return strconv.FormatInt(o.GetGeneration(), formatIntBaseDecimal) + "/" + o.GetAnnotations()[reconcileNowAnnotation]
+}
+
+// load returns the cached secret and true if there is a valid entry
+func (c *getCache) load(o client.Object, now time.Time) (*corev1.Secret, bool) {
+	if c == nil || c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[o.GetUID()]
+	if !ok || e.key != getCacheKey(o) || now.After(e.expires) {
+		delete(c.entries, o.GetUID())
+		return nil, false
+	}
+	if e.secret == nil {
+		return nil, true
+	}
+	return e.secret.DeepCopy(), true
+}
+
+// store caches the get result of the running instance
+func (c *getCache) store(o client.Object, secret *corev1.Secret, now time.Time) {
+	if c == nil || c.ttl <= 0 || !IsAlreadyRunning(o) {
+		return
+	}
+
+	e := getCacheEntry{key: getCacheKey(o), expires: now.Add(c.ttl)}
+	if secret != nil {
+		e.secret = secret.DeepCopy()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[o.GetUID()] = e
+}
+
+// invalidate removes the instance entry
+func (c *getCache) invalidate(o client.Object) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, o.GetUID())
+}
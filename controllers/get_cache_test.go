@@ -0,0 +1,44 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_getCache(t *testing.T) {
+	now := time.Now()
+	c := newGetCache(time.Minute)
+	o := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		UID:         "uid",
+		Generation:  1,
+		Annotations: map[string]string{instanceIsRunningAnnotation: "true"},
+	}}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "secret"}}
+
+	c.store(o, secret, now)
+	if got, ok := c.load(o, now.Add(time.Second)); !ok || !reflect.DeepEqual(got, secret) {
+		t.Errorf("load() = %v, %v, want cached secret", got, ok)
+	}
+
+	if _, ok := c.load(o, now.Add(2*time.Minute)); ok {
+		t.Error("load() must miss expired entry")
+	}
+
+	c.store(o, secret, now)
+	o.Generation = 2
+	if _, ok := c.load(o, now); ok {
+		t.Error("load() must miss on generation change")
+	}
+
+	c.store(o, secret, now)
+	o.Annotations[reconcileNowAnnotation] = "1"
+	if _, ok := c.load(o, now); ok {
+		t.Error("load() must miss on reconcile-now annotation change")
+	}
+}
@@ -5,17 +5,26 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/aiven/aiven-go-client"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
 )
 
+// eventMetricsDatasourceAdded a metrics service is added as Grafana datasource
+const eventMetricsDatasourceAdded = "MetricsDatasourceAdded"
+
 // GrafanaReconciler reconciles a Grafana object
 type GrafanaReconciler struct {
 	Controller
@@ -26,18 +35,137 @@ type GrafanaReconciler struct {
 // +kubebuilder:rbac:groups=aiven.io,resources=grafanas/finalizers,verbs=update
 
 func (r *GrafanaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newGrafanaAdapter), &v1alpha1.Grafana{})
+	return r.reconcileInstance(ctx, req, newGrafanaHandler(r.Client, r.Recorder), &v1alpha1.Grafana{})
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *GrafanaReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Grafana{}).
+		For(&v1alpha1.Grafana{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, ipFilterConfigMapHandler(r.Client, newGrafanaAdapter, &v1alpha1.GrafanaList{})).
+		Watches(&source.Kind{Type: &v1alpha1.ServiceIntegration{}}, metricsDatasourcesHandler(r.Client)).
 		Owns(&corev1.Secret{}).
+		Owns(&corev1.ConfigMap{}).
 		Complete(r)
 }
 
-func newGrafanaAdapter(_ *aiven.Client, object client.Object) (serviceAdapter, error) {
+// grafanaHandler adds the metrics datasources to the generic service handling
+type grafanaHandler struct {
+	*genericServiceHandler
+}
+
+func newGrafanaHandler(k8s client.Client, rec record.EventRecorder) Handlers {
+	return &grafanaHandler{&genericServiceHandler{fabric: newGrafanaAdapter, k8s: k8s, rec: rec}}
+}
+
+func (h *grafanaHandler) get(a AivenClient, object client.Object) (*corev1.Secret, error) {
+	secret, err := h.genericServiceHandler.get(a, object)
+	if err != nil || secret == nil {
+		return secret, err
+	}
+
+	grafana, ok := object.(*v1alpha1.Grafana)
+	if !ok {
+		return nil, fmt.Errorf("object is not of type v1alpha1.Grafana")
+	}
+
+	if fromAnyPointer(grafana.Spec.MetricsDatasources) {
+		if err = h.addMetricsDatasources(a, grafana); err != nil {
+			return nil, err
+		}
+	}
+	return secret, nil
+}
+
+// addMetricsDatasources adds the missing datasources of the running Grafana.
+// Destination services which are not running yet are added on a later reconcile
+func (h *grafanaHandler) addMetricsDatasources(a AivenClient, grafana *v1alpha1.Grafana) error {
+	list := &v1alpha1.ServiceIntegrationList{}
+	if err := h.k8s.List(context.Background(), list, client.InNamespace(grafana.Namespace)); err != nil {
+		return err
+	}
+
+	datasources := getMetricsDatasources(grafana.Spec.Project, list.Items)
+	if len(datasources) == 0 {
+		return nil
+	}
+
+	integrations, err := a.ServiceIntegrations().List(grafana.Spec.Project, grafana.Name)
+	if err != nil {
+		return fmt.Errorf("failed to list service integrations: %w", err)
+	}
+
+	added := make(map[string]bool)
+	for _, i := range integrations {
+		if i.IntegrationType == "dashboard" && i.DestinationService != nil {
+			added[*i.DestinationService] = true
+		}
+	}
+
+	for _, name := range datasources {
+		if added[name] {
+			continue
+		}
+
+		running, err := checkServiceTypeIsRunning(a, grafana.Spec.Project, name, metricsDestinationTypes...)
+		if err != nil {
+			return err
+		}
+		if !running {
+			continue
+		}
+
+		_, err = a.ServiceIntegrations().Create(grafana.Spec.Project, aiven.CreateServiceIntegrationRequest{
+			IntegrationType:    "dashboard",
+			SourceService:      &grafana.Name,
+			DestinationService: &name,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add metrics datasource %q: %w", name, err)
+		}
+		h.rec.Eventf(grafana, corev1.EventTypeNormal, eventMetricsDatasourceAdded, "service %q is added as a datasource", name)
+	}
+	return nil
+}
+
+// getMetricsDatasources returns the destination services of the project metrics integrations, sorted
+func getMetricsDatasources(project string, integrations []v1alpha1.ServiceIntegration) []string {
+	names := make([]string, 0)
+	for _, si := range integrations {
+		name := si.Spec.DestinationServiceName
+		if si.Spec.IntegrationType != "metrics" || si.Spec.Project != project || name == "" {
+			continue
+		}
+		names = appendOnce(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// metricsDatasourcesHandler requeues the Grafana services with metricsDatasources when a metrics integration changes
+func metricsDatasourcesHandler(k8s client.Client) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(o client.Object) []reconcile.Request {
+		si, ok := o.(*v1alpha1.ServiceIntegration)
+		if !ok || si.Spec.IntegrationType != "metrics" {
+			return nil
+		}
+
+		list := &v1alpha1.GrafanaList{}
+		if err := k8s.List(context.Background(), list, client.InNamespace(si.Namespace)); err != nil {
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, g := range list.Items {
+			if fromAnyPointer(g.Spec.MetricsDatasources) && g.Spec.Project == si.Spec.Project {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&g)})
+			}
+		}
+		return requests
+	})
+}
+
+func newGrafanaAdapter(_ AivenClient, object client.Object) (serviceAdapter, error) {
 	grafana, ok := object.(*v1alpha1.Grafana)
 	if !ok {
 		return nil, fmt.Errorf("object is not of type v1alpha1.Grafana")
@@ -0,0 +1,32 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+func Test_getMetricsDatasources(t *testing.T) {
+	newIntegration := func(integrationType, project, destination string) v1alpha1.ServiceIntegration {
+		return v1alpha1.ServiceIntegration{Spec: v1alpha1.ServiceIntegrationSpec{
+			IntegrationType:        integrationType,
+			Project:                project,
+			DestinationServiceName: destination,
+		}}
+	}
+	integrations := []v1alpha1.ServiceIntegration{
+		newIntegration("metrics", "project", "thanos"),
+		newIntegration("metrics", "project", "influx"),
+		newIntegration("metrics", "project", "thanos"),
+		newIntegration("metrics", "other", "m3db"),
+		newIntegration("logs", "project", "opensearch"),
+	}
+
+	got := getMetricsDatasources("project", integrations)
+	if want := []string{"influx", "thanos"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("getMetricsDatasources() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+// inventoryPath metrics server path of the managed resources inventory
+const inventoryPath = "/inventory"
+
+// inventoryItem is a resource managed by the operator
+type inventoryItem struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Project   string `json:"project,omitempty"`
+	AivenID   string `json:"aivenId"`
+	State     string `json:"state,omitempty"`
+}
+
+// inventoryHandler lists all Aiven resources from the manager cache, so the inventory is always up-to-date
+type inventoryHandler struct {
+	k8s    client.Client
+	scheme *runtime.Scheme
+}
+
+// setupInventoryEndpoint serves the inventory on the metrics server
+func setupInventoryEndpoint(mgr ctrl.Manager) error {
+	return mgr.AddMetricsExtraHandler(inventoryPath, &inventoryHandler{k8s: mgr.GetClient(), scheme: mgr.GetScheme()})
+}
+
+func (h *inventoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	items := make([]inventoryItem, 0)
+	for kind := range h.scheme.KnownTypes(v1alpha1.GroupVersion) {
+		if !strings.HasSuffix(kind, "List") {
+			continue
+		}
+
+		obj, err := h.scheme.New(v1alpha1.GroupVersion.WithKind(kind))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		list := obj.(client.ObjectList)
+		if err = h.k8s.List(r.Context(), list); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		objects, err := meta.ExtractList(list)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		for _, o := range objects {
+			item, err := newInventoryItem(strings.TrimSuffix(kind, "List"), o.(client.Object))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(items)
+}
+
+// newInventoryItem reads the common fields of the resource.
+// Aiven ID is status.id if the resource has one, otherwise the name is used on Aiven side
+func newInventoryItem(kind string, o client.Object) (inventoryItem, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return inventoryItem{}, err
+	}
+
+	item := inventoryItem{Kind: kind, Namespace: o.GetNamespace(), Name: o.GetName(), AivenID: o.GetName()}
+	item.Project, _, _ = unstructured.NestedString(u, "spec", "project")
+	if kind == "Project" {
+		item.Project = o.GetName()
+	}
+	if id, _, _ := unstructured.NestedString(u, "status", "id"); id != "" {
+		item.AivenID = id
+	}
+
+	item.State, _, _ = unstructured.NestedString(u, "status", "state")
+	if item.State == "" && IsAlreadyRunning(o) {
+		item.State = "RUNNING"
+	}
+	return item, nil
+}
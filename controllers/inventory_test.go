@@ -0,0 +1,55 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+func Test_inventoryHandler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	kafka := &v1alpha1.Kafka{ObjectMeta: metav1.ObjectMeta{Name: "my-kafka", Namespace: "default"}}
+	kafka.Spec.Project = "my-project"
+	kafka.Status.State = "RUNNING"
+
+	integration := &v1alpha1.ServiceIntegration{ObjectMeta: metav1.ObjectMeta{
+		Name:        "my-integration",
+		Namespace:   "default",
+		Annotations: map[string]string{instanceIsRunningAnnotation: "true"},
+	}}
+	integration.Spec.Project = "my-project"
+	integration.Status.ID = "a1b2c3"
+
+	h := &inventoryHandler{k8s: fake.NewClientBuilder().WithScheme(scheme).WithObjects(kafka, integration).Build(), scheme: scheme}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, inventoryPath, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body)
+	}
+
+	var got []inventoryItem
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []inventoryItem{
+		{Kind: "Kafka", Namespace: "default", Name: "my-kafka", Project: "my-project", AivenID: "my-kafka", State: "RUNNING"},
+		{Kind: "ServiceIntegration", Namespace: "default", Name: "my-integration", Project: "my-project", AivenID: "a1b2c3", State: "RUNNING"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("inventory = %+v, want %+v", got, want)
+	}
+}
@@ -5,12 +5,16 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net"
+	"strconv"
 
 	"github.com/aiven/aiven-go-client"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
 )
@@ -24,17 +28,19 @@ type KafkaReconciler struct {
 // +kubebuilder:rbac:groups=aiven.io,resources=kafkas/status,verbs=get;update;patch
 
 func (r *KafkaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newKafkaAdapter), &v1alpha1.Kafka{})
+	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newKafkaAdapter, r.Client, r.Recorder), &v1alpha1.Kafka{})
 }
 
 func (r *KafkaReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Kafka{}).
+		For(&v1alpha1.Kafka{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, ipFilterConfigMapHandler(r.Client, newKafkaAdapter, &v1alpha1.KafkaList{})).
 		Owns(&corev1.Secret{}).
+		Owns(&corev1.ConfigMap{}).
 		Complete(r)
 }
 
-func newKafkaAdapter(avn *aiven.Client, object client.Object) (serviceAdapter, error) {
+func newKafkaAdapter(avn AivenClient, object client.Object) (serviceAdapter, error) {
 	kafka, ok := object.(*v1alpha1.Kafka)
 	if !ok {
 		return nil, fmt.Errorf("object is not of type v1alpha1.Kafka")
@@ -44,7 +50,7 @@ func newKafkaAdapter(avn *aiven.Client, object client.Object) (serviceAdapter, e
 
 // kafkaAdapter handles an Aiven Kafka service
 type kafkaAdapter struct {
-	avn *aiven.Client
+	avn AivenClient
 	*v1alpha1.Kafka
 }
 
@@ -76,7 +82,7 @@ func (a *kafkaAdapter) newSecret(s *aiven.Service) (*corev1.Secret, error) {
 		password = s.Users[0].Password
 	}
 
-	caCert, err := a.avn.CA.Get(a.getServiceCommonSpec().Project)
+	caCert, err := a.avn.CA().Get(a.getServiceCommonSpec().Project)
 	if err != nil {
 		return nil, fmt.Errorf("aiven client error %w", err)
 	}
@@ -91,17 +97,37 @@ func (a *kafkaAdapter) newSecret(s *aiven.Service) (*corev1.Secret, error) {
 		"CA_CERT":     caCert,
 	}
 
-	// Removes empties
-	for k, v := range stringData {
-		if v == "" {
-			delete(stringData, k)
+	if fromAnyPointer(a.Spec.ConnInfoSecretTarget.KafkaConnect) {
+		for k, v := range newKafkaConnectConnInfo(s.Components, userName, password) {
+			stringData[k] = v
 		}
 	}
 
-	return &corev1.Secret{
+	// Removes empties, for instance, the Connect keys when kafka_connect is not enabled
+	return ensureSecretDataIsNotEmpty(nil, &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: a.Namespace},
 		StringData: stringData,
-	}, nil
+	}), nil
+}
+
+// newKafkaConnectConnInfo returns the Kafka Connect REST API URI of the kafka_connect component,
+// which uses the service user credentials. Values are empty if the component doesn't exist
+func newKafkaConnectConnInfo(components []*aiven.ServiceComponents, userName, password string) map[string]string {
+	var uri string
+	for _, c := range components {
+		if c.Component == "kafka_connect" && c.Usage == "primary" {
+			uri = fmt.Sprintf("https://%s", net.JoinHostPort(c.Host, strconv.Itoa(c.Port)))
+			break
+		}
+	}
+	if uri == "" {
+		userName, password = "", ""
+	}
+	return map[string]string{
+		"KAFKA_CONNECT_URI":      uri,
+		"KAFKA_CONNECT_USERNAME": userName,
+		"KAFKA_CONNECT_PASSWORD": password,
+	}
 }
 
 func (a *kafkaAdapter) getServiceType() string {
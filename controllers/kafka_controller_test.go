@@ -5,8 +5,10 @@ package controllers
 import (
 	"context"
 	"os"
+	"testing"
 	"time"
 
+	"github.com/aiven/aiven-go-client"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -128,3 +130,50 @@ func kafkaSpec(serviceName, namespace string) *v1alpha1.Kafka {
 		},
 	}
 }
+
+func Test_kafkaConnectConnInfo(t *testing.T) {
+	avn := &mockAivenClient{ca: &mockCA{GetFunc: func(project string) (string, error) { return "ca", nil }}}
+	kafka := &v1alpha1.Kafka{ObjectMeta: metav1.ObjectMeta{Name: "my-kafka", Namespace: "default"}}
+	kafka.Spec.ConnInfoSecretTarget.KafkaConnect = anyPointer(true)
+	o, err := newKafkaAdapter(avn, kafka)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &aiven.Service{
+		Name:  "my-kafka",
+		Users: []*aiven.ServiceUser{{Username: "avnadmin", Password: "secret"}},
+		Components: []*aiven.ServiceComponents{
+			{Component: "kafka", Host: "kafka.aiven", Port: 1, Usage: "primary"},
+			{Component: "kafka_connect", Host: "connect.aiven", Port: 2, Usage: "primary"},
+		},
+	}
+	secret, err := o.newSecret(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"KAFKA_CONNECT_URI":      "https://connect.aiven:2",
+		"KAFKA_CONNECT_USERNAME": "avnadmin",
+		"KAFKA_CONNECT_PASSWORD": "secret",
+	}
+	for k, v := range want {
+		if secret.StringData[k] != v {
+			t.Errorf("%s = %q, want %q", k, secret.StringData[k], v)
+		}
+	}
+
+	// Connect is not enabled
+	s.Components = s.Components[:1]
+	if secret, err = o.newSecret(s); err != nil {
+		t.Fatal(err)
+	}
+	for k := range want {
+		if _, ok := secret.StringData[k]; ok {
+			t.Errorf("%s must be omitted when kafka_connect is not enabled", k)
+		}
+	}
+	if secret.StringData["USERNAME"] != "avnadmin" {
+		t.Errorf("USERNAME = %q, want avnadmin", secret.StringData["USERNAME"])
+	}
+}
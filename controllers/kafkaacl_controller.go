@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
@@ -34,11 +35,11 @@ func (r *KafkaACLReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 func (r *KafkaACLReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.KafkaACL{}).
+		For(&v1alpha1.KafkaACL{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
 		Complete(r)
 }
 
-func (h KafkaACLHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs []client.Object) error {
+func (h KafkaACLHandler) createOrUpdate(avn AivenClient, i client.Object, refs []client.Object) error {
 	acl, err := h.convert(i)
 	if err != nil {
 		return err
@@ -52,7 +53,7 @@ func (h KafkaACLHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs
 	}
 
 	// Creates it from scratch
-	r, err := avn.KafkaACLs.Create(
+	r, err := avn.KafkaACLs().Create(
 		acl.Spec.Project,
 		acl.Spec.ServiceName,
 		aiven.CreateKafkaACLRequest{
@@ -81,7 +82,7 @@ func (h KafkaACLHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs
 	return nil
 }
 
-func (h KafkaACLHandler) delete(avn *aiven.Client, i client.Object) (bool, error) {
+func (h KafkaACLHandler) delete(avn AivenClient, i client.Object) (bool, error) {
 	acl, err := h.convert(i)
 	if err != nil {
 		return false, err
@@ -89,7 +90,7 @@ func (h KafkaACLHandler) delete(avn *aiven.Client, i client.Object) (bool, error
 
 	id, err := h.getID(avn, acl)
 	if err == nil {
-		err = avn.KafkaACLs.Delete(acl.Spec.Project, acl.Spec.ServiceName, id)
+		err = avn.KafkaACLs().Delete(acl.Spec.Project, acl.Spec.ServiceName, id)
 	}
 
 	if err != nil && !aiven.IsNotFound(err) {
@@ -101,7 +102,7 @@ func (h KafkaACLHandler) delete(avn *aiven.Client, i client.Object) (bool, error
 
 // todo: remove in v1
 // getID returns ACL's ID in < v0.5.1 compatible mode
-func (h KafkaACLHandler) getID(avn *aiven.Client, acl *v1alpha1.KafkaACL) (string, error) {
+func (h KafkaACLHandler) getID(avn AivenClient, acl *v1alpha1.KafkaACL) (string, error) {
 	// ACLs made prior to v0.5.1 doesn't have an ID.
 	// This block is for fresh made ACLs only
 	// The rest of this function tries to guess it filtering the list.
@@ -110,7 +111,7 @@ func (h KafkaACLHandler) getID(avn *aiven.Client, acl *v1alpha1.KafkaACL) (strin
 	}
 
 	// For old ACLs only
-	list, err := avn.KafkaACLs.List(acl.Spec.Project, acl.Spec.ServiceName)
+	list, err := avn.KafkaACLs().List(acl.Spec.Project, acl.Spec.ServiceName)
 	if err != nil {
 		return "", err
 	}
@@ -125,7 +126,7 @@ func (h KafkaACLHandler) getID(avn *aiven.Client, acl *v1alpha1.KafkaACL) (strin
 	return "", aiven.Error{Status: http.StatusNotFound, Message: fmt.Sprintf("Kafka ACL %q not found", acl.Name)}
 }
 
-func (h KafkaACLHandler) get(avn *aiven.Client, i client.Object) (*corev1.Secret, error) {
+func (h KafkaACLHandler) get(avn AivenClient, i client.Object) (*corev1.Secret, error) {
 	acl, err := h.convert(i)
 	if err != nil {
 		return nil, err
@@ -136,7 +137,7 @@ func (h KafkaACLHandler) get(avn *aiven.Client, i client.Object) (*corev1.Secret
 		return nil, err
 	}
 
-	_, err = avn.KafkaACLs.Get(acl.Spec.Project, acl.Spec.ServiceName, id)
+	_, err = avn.KafkaACLs().Get(acl.Spec.Project, acl.Spec.ServiceName, id)
 	if err != nil {
 		return nil, err
 	}
@@ -150,7 +151,7 @@ func (h KafkaACLHandler) get(avn *aiven.Client, i client.Object) (*corev1.Secret
 	return nil, nil
 }
 
-func (h KafkaACLHandler) checkPreconditions(avn *aiven.Client, i client.Object) (bool, error) {
+func (h KafkaACLHandler) checkPreconditions(avn AivenClient, i client.Object) (bool, error) {
 	acl, err := h.convert(i)
 	if err != nil {
 		return false, err
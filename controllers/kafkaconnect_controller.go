@@ -10,7 +10,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
 )
@@ -24,16 +26,17 @@ type KafkaConnectReconciler struct {
 // +kubebuilder:rbac:groups=aiven.io,resources=kafkaconnects/status,verbs=get;update;patch
 
 func (r *KafkaConnectReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newKafkaConnectAdapter), &v1alpha1.KafkaConnect{})
+	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newKafkaConnectAdapter, r.Client, r.Recorder), &v1alpha1.KafkaConnect{})
 }
 
 func (r *KafkaConnectReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.KafkaConnect{}).
+		For(&v1alpha1.KafkaConnect{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, ipFilterConfigMapHandler(r.Client, newKafkaConnectAdapter, &v1alpha1.KafkaConnectList{})).
 		Complete(r)
 }
 
-func newKafkaConnectAdapter(_ *aiven.Client, object client.Object) (serviceAdapter, error) {
+func newKafkaConnectAdapter(_ AivenClient, object client.Object) (serviceAdapter, error) {
 	kafkaConnect, ok := object.(*v1alpha1.KafkaConnect)
 	if !ok {
 		return nil, fmt.Errorf("object is not of type v1alpha1.KafkaConnect")
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/aiven/aiven-go-client"
@@ -15,6 +16,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
@@ -29,6 +31,12 @@ type KafkaConnectorHandler struct {
 	k8s client.Client
 }
 
+const (
+	// s3AccessKeyIDKey and s3SecretAccessKeyKey are keys of the s3 sink credentials secret
+	s3AccessKeyIDKey     = "AWS_ACCESS_KEY_ID"
+	s3SecretAccessKeyKey = "AWS_SECRET_ACCESS_KEY"
+)
+
 //+kubebuilder:rbac:groups=aiven.io,resources=kafkaconnectors,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=aiven.io,resources=kafkaconnectors/status,verbs=get;update;patch
 
@@ -39,11 +47,11 @@ func (r *KafkaConnectorReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 // SetupWithManager sets up the controller with the Manager.
 func (r *KafkaConnectorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.KafkaConnector{}).
+		For(&v1alpha1.KafkaConnector{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
 		Complete(r)
 }
 
-func (h KafkaConnectorHandler) createOrUpdate(avn *aiven.Client, o client.Object, refs []client.Object) error {
+func (h KafkaConnectorHandler) createOrUpdate(avn AivenClient, o client.Object, refs []client.Object) error {
 	conn, err := h.convert(o)
 	if err != nil {
 		return err
@@ -54,21 +62,21 @@ func (h KafkaConnectorHandler) createOrUpdate(avn *aiven.Client, o client.Object
 		return fmt.Errorf("unable to check if kafka connector exists: %w", err)
 	}
 
-	connCfg, err := h.buildConnectorConfig(conn)
+	connCfg, err := h.buildConnectorConfig(avn, conn)
 	if err != nil {
 		return fmt.Errorf("unable to build connector config: %w", err)
 	}
 
 	var reason string
 	if !exists {
-		err = avn.KafkaConnectors.Create(conn.Spec.Project, conn.Spec.ServiceName, connCfg)
+		err = avn.KafkaConnectors().Create(conn.Spec.Project, conn.Spec.ServiceName, connCfg)
 		if err != nil && !aiven.IsAlreadyExists(err) {
 			return err
 		}
 		reason = "Created"
 	} else {
-		_, err := avn.KafkaConnectors.Update(conn.Spec.Project, conn.Spec.ServiceName, conn.Name, connCfg)
-		if err != nil {
+		_, err := avn.KafkaConnectors().Update(conn.Spec.Project, conn.Spec.ServiceName, conn.Name, connCfg)
+		if err != nil && !isNotChangedError(err) {
 			return err
 		}
 		reason = "Updated"
@@ -90,7 +98,7 @@ func (h KafkaConnectorHandler) createOrUpdate(avn *aiven.Client, o client.Object
 }
 
 // buildConnectorConfig joins mandatory fields with additional conncetor specific config
-func (h KafkaConnectorHandler) buildConnectorConfig(conn *v1alpha1.KafkaConnector) (aiven.KafkaConnectorConfig, error) {
+func (h KafkaConnectorHandler) buildConnectorConfig(avn AivenClient, conn *v1alpha1.KafkaConnector) (aiven.KafkaConnectorConfig, error) {
 	const (
 		configFieldConnectorName  = "name"
 		configFieldConnectorClass = "connector.class"
@@ -115,9 +123,33 @@ func (h KafkaConnectorHandler) buildConnectorConfig(conn *v1alpha1.KafkaConnecto
 	)
 
 	m := make(map[string]string)
+	if sink := conn.Spec.JDBCSink; sink != nil {
+		pg, err := avn.Services().Get(conn.Spec.Project, sink.PostgreSQLRef.Name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get jdbc sink postgresql service: %w", err)
+		}
+		m = jdbcSinkConfig(sink, pg.URIParams)
+	}
+	if sink := conn.Spec.S3Sink; sink != nil {
+		var secret corev1.Secret
+		err := h.k8s.Get(context.Background(), types.NamespacedName{Namespace: conn.GetNamespace(), Name: sink.CredentialsSecretRef.Name}, &secret)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get s3 sink credentials secret: %w", err)
+		}
+		m = s3SinkConfig(sink, string(secret.Data[s3AccessKeyIDKey]), string(secret.Data[s3SecretAccessKeyKey]))
+	}
+	if sink := conn.Spec.OpenSearchSink; sink != nil {
+		opensearch, err := avn.Services().Get(conn.Spec.Project, sink.OpenSearchRef.Name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get opensearch sink service: %w", err)
+		}
+		m = openSearchSinkConfig(sink, opensearch.URIParams)
+	}
 
 	m[configFieldConnectorName] = conn.GetName()
-	m[configFieldConnectorClass] = conn.Spec.ConnectorClass
+	if conn.Spec.ConnectorClass != "" {
+		m[configFieldConnectorClass] = conn.Spec.ConnectorClass
+	}
 
 	for k, v := range conn.Spec.UserConfig {
 		t, err := template.New(k).Funcs(funcMap).Parse(v)
@@ -133,33 +165,136 @@ func (h KafkaConnectorHandler) buildConnectorConfig(conn *v1alpha1.KafkaConnecto
 	return aiven.KafkaConnectorConfig(m), nil
 }
 
-func (h KafkaConnectorHandler) delete(avn *aiven.Client, o client.Object) (bool, error) {
+// jdbcSinkConfig builds JDBC sink connector config with PostgreSQL service connection
+func jdbcSinkConfig(sink *v1alpha1.KafkaConnectorJDBCSink, pgParams map[string]string) map[string]string {
+	m := map[string]string{
+		"connector.class": "io.aiven.connect.jdbc.JdbcSinkConnector",
+		"connection.url": fmt.Sprintf("jdbc:postgresql://%s:%s/%s?sslmode=%s",
+			pgParams["host"], pgParams["port"], pgParams["dbname"], pgParams["sslmode"]),
+		"connection.user":     pgParams["user"],
+		"connection.password": pgParams["password"],
+		"topics":              strings.Join(sink.Topics, ","),
+		"insert.mode":         "insert",
+		"auto.create":         strconv.FormatBool(sink.AutoCreate),
+	}
+
+	if sink.InsertMode != "" {
+		m["insert.mode"] = sink.InsertMode
+	}
+	if len(sink.PKFields) > 0 {
+		m["pk.mode"] = "record_key"
+		m["pk.fields"] = strings.Join(sink.PKFields, ",")
+	}
+	return m
+}
+
+// s3SinkConfig builds Aiven S3 sink connector config
+func s3SinkConfig(sink *v1alpha1.KafkaConnectorS3Sink, accessKeyID, secretAccessKey string) map[string]string {
+	m := map[string]string{
+		"connector.class":       "io.aiven.kafka.connect.s3.AivenKafkaConnectS3SinkConnector",
+		"topics":                strings.Join(sink.Topics, ","),
+		"aws.access.key.id":     accessKeyID,
+		"aws.secret.access.key": secretAccessKey,
+		"aws.s3.bucket.name":    sink.BucketName,
+		"aws.s3.region":         sink.Region,
+		"format.output.type":    "csv",
+	}
+
+	if sink.Prefix != "" {
+		m["aws.s3.prefix"] = sink.Prefix
+	}
+	if sink.OutputFormat != "" {
+		m["format.output.type"] = sink.OutputFormat
+	}
+	if sink.DeadLetterQueueTopic != "" {
+		m["errors.tolerance"] = "all"
+		m["errors.deadletterqueue.topic.name"] = sink.DeadLetterQueueTopic
+		m["errors.deadletterqueue.context.headers.enable"] = "true"
+	}
+	return m
+}
+
+// openSearchSinkConfig builds Aiven OpenSearch sink connector config with OpenSearch service connection.
+// Topics are mapped to indexes with TimestampRouter, which supports both ${topic} and ${timestamp}
+func openSearchSinkConfig(sink *v1alpha1.KafkaConnectorOpenSearchSink, osParams map[string]string) map[string]string {
+	m := map[string]string{
+		"connector.class":     "io.aiven.kafka.connect.opensearch.OpensearchSinkConnector",
+		"connection.url":      fmt.Sprintf("https://%s:%s", osParams["host"], osParams["port"]),
+		"connection.username": osParams["user"],
+		"connection.password": osParams["password"],
+		"topics":              strings.Join(sink.Topics, ","),
+		"key.ignore":          strconv.FormatBool(!sink.DocumentIDFromKey),
+		"schema.ignore":       "true",
+	}
+
+	if sink.IndexPattern != "" {
+		timestampFormat := sink.TimestampFormat
+		if timestampFormat == "" {
+			timestampFormat = "yyyy.MM.dd"
+		}
+		m["transforms"] = "index"
+		m["transforms.index.type"] = "org.apache.kafka.connect.transforms.TimestampRouter"
+		m["transforms.index.topic.format"] = sink.IndexPattern
+		m["transforms.index.timestamp.format"] = timestampFormat
+	}
+	if sink.DeadLetterQueueTopic != "" {
+		m["errors.tolerance"] = "all"
+		m["errors.deadletterqueue.topic.name"] = sink.DeadLetterQueueTopic
+		m["errors.deadletterqueue.context.headers.enable"] = "true"
+	}
+	return m
+}
+
+// newSinkStatus returns the sink of jdbcSink, s3Sink and opensearchSink connectors
+func newSinkStatus(spec *v1alpha1.KafkaConnectorSpec) *v1alpha1.KafkaConnectorSinkStatus {
+	switch {
+	case spec.JDBCSink != nil:
+		return &v1alpha1.KafkaConnectorSinkStatus{Destination: "postgresql://" + spec.JDBCSink.PostgreSQLRef.Name}
+	case spec.S3Sink != nil:
+		return &v1alpha1.KafkaConnectorSinkStatus{
+			Destination:          fmt.Sprintf("s3://%s/%s", spec.S3Sink.BucketName, spec.S3Sink.Prefix),
+			DeadLetterQueueTopic: spec.S3Sink.DeadLetterQueueTopic,
+		}
+	case spec.OpenSearchSink != nil:
+		index := spec.OpenSearchSink.IndexPattern
+		if index == "" {
+			index = "${topic}"
+		}
+		return &v1alpha1.KafkaConnectorSinkStatus{
+			Destination:          fmt.Sprintf("opensearch://%s/%s", spec.OpenSearchSink.OpenSearchRef.Name, index),
+			DeadLetterQueueTopic: spec.OpenSearchSink.DeadLetterQueueTopic,
+		}
+	}
+	return nil
+}
+
+func (h KafkaConnectorHandler) delete(avn AivenClient, o client.Object) (bool, error) {
 	conn, err := h.convert(o)
 	if err != nil {
 		return false, err
 	}
-	err = avn.KafkaConnectors.Delete(conn.Spec.Project, conn.Spec.ServiceName, conn.Name)
+	err = avn.KafkaConnectors().Delete(conn.Spec.Project, conn.Spec.ServiceName, conn.Name)
 	if err != nil && !aiven.IsNotFound(err) {
 		return false, fmt.Errorf("unable to delete kafka connector: %w", err)
 	}
 	return true, nil
 }
 
-func (h KafkaConnectorHandler) exists(avn *aiven.Client, conn *v1alpha1.KafkaConnector) (bool, error) {
-	connector, err := avn.KafkaConnectors.Status(conn.Spec.Project, conn.Spec.ServiceName, conn.Name)
+func (h KafkaConnectorHandler) exists(avn AivenClient, conn *v1alpha1.KafkaConnector) (bool, error) {
+	connector, err := avn.KafkaConnectors().Status(conn.Spec.Project, conn.Spec.ServiceName, conn.Name)
 	if err != nil && !aiven.IsNotFound(err) {
 		return false, err
 	}
 	return connector != nil, nil
 }
 
-func (h KafkaConnectorHandler) get(avn *aiven.Client, o client.Object) (*corev1.Secret, error) {
+func (h KafkaConnectorHandler) get(avn AivenClient, o client.Object) (*corev1.Secret, error) {
 	conn, err := h.convert(o)
 	if err != nil {
 		return nil, err
 	}
 
-	connAtAiven, err := avn.KafkaConnectors.GetByName(conn.Spec.Project, conn.Spec.ServiceName, conn.Name)
+	connAtAiven, err := avn.KafkaConnectors().GetByName(conn.Spec.Project, conn.Spec.ServiceName, conn.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -172,11 +307,12 @@ func (h KafkaConnectorHandler) get(avn *aiven.Client, o client.Object) (*corev1.
 		Version: connAtAiven.Plugin.Version,
 	}
 
-	connStat, err := avn.KafkaConnectors.Status(conn.Spec.Project, conn.Spec.ServiceName, conn.Name)
+	connStat, err := avn.KafkaConnectors().Status(conn.Spec.Project, conn.Spec.ServiceName, conn.Name)
 	if err != nil {
 		return nil, err
 	}
 	conn.Status.State = connStat.Status.State
+	conn.Status.Sink = newSinkStatus(&conn.Spec)
 	conn.Status.TasksStatus = v1alpha1.KafkaConnectorTasksStatus{}
 	for i := range connStat.Status.Tasks {
 		conn.Status.TasksStatus.Total++
@@ -205,7 +341,7 @@ func (h KafkaConnectorHandler) get(avn *aiven.Client, o client.Object) (*corev1.
 	return nil, nil
 }
 
-func (h KafkaConnectorHandler) checkPreconditions(avn *aiven.Client, o client.Object) (bool, error) {
+func (h KafkaConnectorHandler) checkPreconditions(avn AivenClient, o client.Object) (bool, error) {
 	conn, err := h.convert(o)
 	if err != nil {
 		return false, err
@@ -214,7 +350,26 @@ func (h KafkaConnectorHandler) checkPreconditions(avn *aiven.Client, o client.Ob
 	meta.SetStatusCondition(&conn.Status.Conditions,
 		getInitializedCondition("Preconditions", "Checking preconditions"))
 
-	return checkServiceIsRunning(avn, conn.Spec.Project, conn.Spec.ServiceName)
+	if sink := conn.Spec.S3Sink; sink != nil {
+		err = checkSecretKeys(h.k8s, conn.Namespace, sink.CredentialsSecretRef.Name, s3AccessKeyIDKey, s3SecretAccessKeyKey)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	check, err := checkServiceIsRunning(avn, conn.Spec.Project, conn.Spec.ServiceName)
+	if err != nil || !check {
+		return check, err
+	}
+
+	switch {
+	case conn.Spec.JDBCSink != nil:
+		// JDBC sink writes to the PostgreSQL service
+		return checkServiceTypeIsRunning(avn, conn.Spec.Project, conn.Spec.JDBCSink.PostgreSQLRef.Name, "pg")
+	case conn.Spec.OpenSearchSink != nil:
+		return checkServiceTypeIsRunning(avn, conn.Spec.Project, conn.Spec.OpenSearchSink.OpenSearchRef.Name, "opensearch")
+	}
+	return true, nil
 }
 
 func (h KafkaConnectorHandler) convert(o client.Object) (*v1alpha1.KafkaConnector, error) {
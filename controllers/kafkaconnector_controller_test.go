@@ -0,0 +1,102 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+func Test_jdbcSinkConfig(t *testing.T) {
+	sink := &v1alpha1.KafkaConnectorJDBCSink{
+		Topics:     []string{"foo", "bar"},
+		InsertMode: "upsert",
+		PKFields:   []string{"id"},
+		AutoCreate: true,
+	}
+	params := map[string]string{"host": "pg.aiven.io", "port": "12345", "dbname": "defaultdb", "sslmode": "require", "user": "avnadmin", "password": "secret"}
+	want := map[string]string{
+		"connector.class":     "io.aiven.connect.jdbc.JdbcSinkConnector",
+		"connection.url":      "jdbc:postgresql://pg.aiven.io:12345/defaultdb?sslmode=require",
+		"connection.user":     "avnadmin",
+		"connection.password": "secret",
+		"topics":              "foo,bar",
+		"insert.mode":         "upsert",
+		"auto.create":         "true",
+		"pk.mode":             "record_key",
+		"pk.fields":           "id",
+	}
+	if got := jdbcSinkConfig(sink, params); !reflect.DeepEqual(got, want) {
+		t.Errorf("jdbcSinkConfig() = %v, want %v", got, want)
+	}
+}
+
+func Test_openSearchSinkConfig(t *testing.T) {
+	sink := &v1alpha1.KafkaConnectorOpenSearchSink{
+		OpenSearchRef:        v1alpha1.ResourceReference{Name: "my-os"},
+		Topics:               []string{"foo", "bar"},
+		IndexPattern:         "logs-${topic}-${timestamp}",
+		DeadLetterQueueTopic: "failed",
+	}
+	params := map[string]string{"host": "os.aivencloud.com", "port": "12345", "user": "avnadmin", "password": "secret"}
+	want := map[string]string{
+		"connector.class":                   "io.aiven.kafka.connect.opensearch.OpensearchSinkConnector",
+		"connection.url":                    "https://os.aivencloud.com:12345",
+		"connection.username":               "avnadmin",
+		"connection.password":               "secret",
+		"topics":                            "foo,bar",
+		"key.ignore":                        "true",
+		"schema.ignore":                     "true",
+		"transforms":                        "index",
+		"transforms.index.type":             "org.apache.kafka.connect.transforms.TimestampRouter",
+		"transforms.index.topic.format":     "logs-${topic}-${timestamp}",
+		"transforms.index.timestamp.format": "yyyy.MM.dd",
+		"errors.tolerance":                  "all",
+		"errors.deadletterqueue.topic.name": "failed",
+		"errors.deadletterqueue.context.headers.enable": "true",
+	}
+	if got := openSearchSinkConfig(sink, params); !reflect.DeepEqual(got, want) {
+		t.Errorf("openSearchSinkConfig() = %v, want %v", got, want)
+	}
+
+	spec := &v1alpha1.KafkaConnectorSpec{OpenSearchSink: &v1alpha1.KafkaConnectorOpenSearchSink{OpenSearchRef: sink.OpenSearchRef}}
+	wantStatus := &v1alpha1.KafkaConnectorSinkStatus{Destination: "opensearch://my-os/${topic}"}
+	if got := newSinkStatus(spec); !reflect.DeepEqual(got, wantStatus) {
+		t.Errorf("newSinkStatus() = %v, want %v", got, wantStatus)
+	}
+}
+
+func Test_s3SinkConfig(t *testing.T) {
+	sink := &v1alpha1.KafkaConnectorS3Sink{
+		Topics:               []string{"foo", "bar"},
+		BucketName:           "my-bucket",
+		Region:               "eu-west-1",
+		Prefix:               "events/",
+		OutputFormat:         "jsonl",
+		DeadLetterQueueTopic: "failed",
+	}
+	want := map[string]string{
+		"connector.class":                   "io.aiven.kafka.connect.s3.AivenKafkaConnectS3SinkConnector",
+		"topics":                            "foo,bar",
+		"aws.access.key.id":                 "key",
+		"aws.secret.access.key":             "secret",
+		"aws.s3.bucket.name":                "my-bucket",
+		"aws.s3.region":                     "eu-west-1",
+		"aws.s3.prefix":                     "events/",
+		"format.output.type":                "jsonl",
+		"errors.tolerance":                  "all",
+		"errors.deadletterqueue.topic.name": "failed",
+		"errors.deadletterqueue.context.headers.enable": "true",
+	}
+	if got := s3SinkConfig(sink, "key", "secret"); !reflect.DeepEqual(got, want) {
+		t.Errorf("s3SinkConfig() = %v, want %v", got, want)
+	}
+
+	spec := &v1alpha1.KafkaConnectorSpec{S3Sink: sink}
+	wantStatus := &v1alpha1.KafkaConnectorSinkStatus{Destination: "s3://my-bucket/events/", DeadLetterQueueTopic: "failed"}
+	if got := newSinkStatus(spec); !reflect.DeepEqual(got, wantStatus) {
+		t.Errorf("newSinkStatus() = %v, want %v", got, wantStatus)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
@@ -33,18 +34,18 @@ func (r *KafkaSchemaReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 func (r *KafkaSchemaReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.KafkaSchema{}).
+		For(&v1alpha1.KafkaSchema{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
 		Complete(r)
 }
 
-func (h KafkaSchemaHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs []client.Object) error {
+func (h KafkaSchemaHandler) createOrUpdate(avn AivenClient, i client.Object, refs []client.Object) error {
 	schema, err := h.convert(i)
 	if err != nil {
 		return err
 	}
 
 	// createOrUpdate Kafka Schema Subject
-	_, err = avn.KafkaSubjectSchemas.Add(
+	_, err = avn.KafkaSubjectSchemas().Add(
 		schema.Spec.Project,
 		schema.Spec.ServiceName,
 		schema.Spec.SubjectName,
@@ -58,7 +59,7 @@ func (h KafkaSchemaHandler) createOrUpdate(avn *aiven.Client, i client.Object, r
 
 	// set compatibility level if defined for a newly created Kafka Schema Subject
 	if schema.Spec.CompatibilityLevel != "" {
-		_, err := avn.KafkaSubjectSchemas.UpdateConfiguration(
+		_, err := avn.KafkaSubjectSchemas().UpdateConfiguration(
 			schema.Spec.Project,
 			schema.Spec.ServiceName,
 			schema.Spec.SubjectName,
@@ -91,13 +92,13 @@ func (h KafkaSchemaHandler) createOrUpdate(avn *aiven.Client, i client.Object, r
 	return nil
 }
 
-func (h KafkaSchemaHandler) delete(avn *aiven.Client, i client.Object) (bool, error) {
+func (h KafkaSchemaHandler) delete(avn AivenClient, i client.Object) (bool, error) {
 	schema, err := h.convert(i)
 	if err != nil {
 		return false, err
 	}
 
-	err = avn.KafkaSubjectSchemas.Delete(schema.Spec.Project, schema.Spec.ServiceName, schema.Spec.SubjectName)
+	err = avn.KafkaSubjectSchemas().Delete(schema.Spec.Project, schema.Spec.ServiceName, schema.Spec.SubjectName)
 	if err != nil && !aiven.IsNotFound(err) {
 		return false, fmt.Errorf("aiven client delete Kafka Schema error: %w", err)
 	}
@@ -105,7 +106,7 @@ func (h KafkaSchemaHandler) delete(avn *aiven.Client, i client.Object) (bool, er
 	return true, nil
 }
 
-func (h KafkaSchemaHandler) get(_ *aiven.Client, i client.Object) (*corev1.Secret, error) {
+func (h KafkaSchemaHandler) get(_ AivenClient, i client.Object) (*corev1.Secret, error) {
 	schema, err := h.convert(i)
 	if err != nil {
 		return nil, err
@@ -120,7 +121,7 @@ func (h KafkaSchemaHandler) get(_ *aiven.Client, i client.Object) (*corev1.Secre
 	return nil, nil
 }
 
-func (h KafkaSchemaHandler) checkPreconditions(avn *aiven.Client, i client.Object) (bool, error) {
+func (h KafkaSchemaHandler) checkPreconditions(avn AivenClient, i client.Object) (bool, error) {
 	schema, err := h.convert(i)
 	if err != nil {
 		return false, err
@@ -138,8 +139,8 @@ func (h KafkaSchemaHandler) convert(i client.Object) (*v1alpha1.KafkaSchema, err
 	return schema, nil
 }
 
-func (h KafkaSchemaHandler) getLastVersion(avn *aiven.Client, schema *v1alpha1.KafkaSchema) (int, error) {
-	ver, err := avn.KafkaSubjectSchemas.GetVersions(
+func (h KafkaSchemaHandler) getLastVersion(avn AivenClient, schema *v1alpha1.KafkaSchema) (int, error) {
+	ver, err := avn.KafkaSubjectSchemas().GetVersions(
 		schema.Spec.Project,
 		schema.Spec.ServiceName,
 		schema.Spec.SubjectName)
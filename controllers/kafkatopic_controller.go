@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
@@ -33,11 +34,11 @@ func (r *KafkaTopicReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 func (r *KafkaTopicReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.KafkaTopic{}).
+		For(&v1alpha1.KafkaTopic{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
 		Complete(r)
 }
 
-func (h KafkaTopicHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs []client.Object) error {
+func (h KafkaTopicHandler) createOrUpdate(avn AivenClient, i client.Object, refs []client.Object) error {
 	topic, err := h.convert(i)
 	if err != nil {
 		return err
@@ -58,7 +59,7 @@ func (h KafkaTopicHandler) createOrUpdate(avn *aiven.Client, i client.Object, re
 
 	var reason string
 	if !exists {
-		err = avn.KafkaTopics.Create(topic.Spec.Project, topic.Spec.ServiceName, aiven.CreateKafkaTopicRequest{
+		err = avn.KafkaTopics().Create(topic.Spec.Project, topic.Spec.ServiceName, aiven.CreateKafkaTopicRequest{
 			Partitions:  &topic.Spec.Partitions,
 			Replication: &topic.Spec.Replication,
 			TopicName:   topic.GetTopicName(),
@@ -71,14 +72,14 @@ func (h KafkaTopicHandler) createOrUpdate(avn *aiven.Client, i client.Object, re
 
 		reason = "Created"
 	} else {
-		err = avn.KafkaTopics.Update(topic.Spec.Project, topic.Spec.ServiceName, topic.GetTopicName(),
+		err = avn.KafkaTopics().Update(topic.Spec.Project, topic.Spec.ServiceName, topic.GetTopicName(),
 			aiven.UpdateKafkaTopicRequest{
 				Partitions:  &topic.Spec.Partitions,
 				Replication: &topic.Spec.Replication,
 				Tags:        tags,
 				Config:      convertKafkaTopicConfig(topic),
 			})
-		if err != nil {
+		if err != nil && !isNotChangedError(err) {
 			return fmt.Errorf("cannot update Kafka Topic: %w", err)
 		}
 
@@ -99,7 +100,7 @@ func (h KafkaTopicHandler) createOrUpdate(avn *aiven.Client, i client.Object, re
 	return nil
 }
 
-func (h KafkaTopicHandler) delete(avn *aiven.Client, i client.Object) (bool, error) {
+func (h KafkaTopicHandler) delete(avn AivenClient, i client.Object) (bool, error) {
 	topic, err := h.convert(i)
 	if err != nil {
 		return false, err
@@ -109,8 +110,19 @@ func (h KafkaTopicHandler) delete(avn *aiven.Client, i client.Object) (bool, err
 		return false, errTerminationProtectionOn
 	}
 
+	if fromAnyPointer(topic.Spec.DeleteProtectionIfNotEmpty) && !isForceDelete(topic) {
+		t, err := avn.KafkaTopics().Get(topic.Spec.Project, topic.Spec.ServiceName, topic.GetTopicName())
+		if err != nil && !aiven.IsNotFound(err) {
+			return false, err
+		}
+
+		if n := kafkaTopicMessageCount(t); n > 0 {
+			return false, fmt.Errorf("%w: topic has %d messages", errNotEmpty, n)
+		}
+	}
+
 	// Delete project on Aiven side
-	err = avn.KafkaTopics.Delete(topic.Spec.Project, topic.Spec.ServiceName, topic.GetTopicName())
+	err = avn.KafkaTopics().Delete(topic.Spec.Project, topic.Spec.ServiceName, topic.GetTopicName())
 	if err != nil && !aiven.IsNotFound(err) {
 		return false, err
 	}
@@ -118,8 +130,8 @@ func (h KafkaTopicHandler) delete(avn *aiven.Client, i client.Object) (bool, err
 	return true, nil
 }
 
-func (h KafkaTopicHandler) exists(avn *aiven.Client, topic *v1alpha1.KafkaTopic) (bool, error) {
-	t, err := avn.KafkaTopics.Get(topic.Spec.Project, topic.Spec.ServiceName, topic.GetTopicName())
+func (h KafkaTopicHandler) exists(avn AivenClient, topic *v1alpha1.KafkaTopic) (bool, error) {
+	t, err := avn.KafkaTopics().Get(topic.Spec.Project, topic.Spec.ServiceName, topic.GetTopicName())
 	if err != nil && !aiven.IsNotFound(err) {
 		if aivenError, ok := err.(aiven.Error); ok {
 			// Getting topic info can sometimes temporarily fail with 501 and 502. Don't
@@ -135,7 +147,7 @@ func (h KafkaTopicHandler) exists(avn *aiven.Client, topic *v1alpha1.KafkaTopic)
 	return t != nil, nil
 }
 
-func (h KafkaTopicHandler) get(avn *aiven.Client, i client.Object) (*corev1.Secret, error) {
+func (h KafkaTopicHandler) get(avn AivenClient, i client.Object) (*corev1.Secret, error) {
 	topic, err := h.convert(i)
 	if err != nil {
 		return nil, err
@@ -159,7 +171,7 @@ func (h KafkaTopicHandler) get(avn *aiven.Client, i client.Object) (*corev1.Secr
 	return nil, err
 }
 
-func (h KafkaTopicHandler) checkPreconditions(avn *aiven.Client, i client.Object) (bool, error) {
+func (h KafkaTopicHandler) checkPreconditions(avn AivenClient, i client.Object) (bool, error) {
 	topic, err := h.convert(i)
 	if err != nil {
 		return false, err
@@ -171,8 +183,8 @@ func (h KafkaTopicHandler) checkPreconditions(avn *aiven.Client, i client.Object
 	return checkServiceIsRunning(avn, topic.Spec.Project, topic.Spec.ServiceName)
 }
 
-func (h KafkaTopicHandler) getState(avn *aiven.Client, topic *v1alpha1.KafkaTopic) (string, error) {
-	t, err := avn.KafkaTopics.Get(topic.Spec.Project, topic.Spec.ServiceName, topic.GetTopicName())
+func (h KafkaTopicHandler) getState(avn AivenClient, topic *v1alpha1.KafkaTopic) (string, error) {
+	t, err := avn.KafkaTopics().Get(topic.Spec.Project, topic.Spec.ServiceName, topic.GetTopicName())
 	if err != nil {
 		if aivenError, ok := err.(aiven.Error); ok {
 			// Getting topic info can sometimes temporarily fail with 501 and 502. Don't
@@ -186,6 +198,19 @@ func (h KafkaTopicHandler) getState(avn *aiven.Client, topic *v1alpha1.KafkaTopi
 	return t.State, nil
 }
 
+// kafkaTopicMessageCount returns the number of messages available in all partitions of the topic
+func kafkaTopicMessageCount(t *aiven.KafkaTopic) int64 {
+	if t == nil {
+		return 0
+	}
+
+	var count int64
+	for _, p := range t.Partitions {
+		count += p.LatestOffset - p.EarliestOffset
+	}
+	return count
+}
+
 func (h KafkaTopicHandler) convert(i client.Object) (*v1alpha1.KafkaTopic, error) {
 	topic, ok := i.(*v1alpha1.KafkaTopic)
 	if !ok {
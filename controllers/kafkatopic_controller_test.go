@@ -4,9 +4,12 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"os"
+	"testing"
 	"time"
 
+	"github.com/aiven/aiven-go-client"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -124,3 +127,59 @@ func kafkaTopicSpec(service, topic, namespace string) *v1alpha1.KafkaTopic {
 		},
 	}
 }
+
+func Test_kafkaTopicDeleteProtectionIfNotEmpty(t *testing.T) {
+	cases := []struct {
+		name        string
+		messages    int64
+		protection  bool
+		forceDelete bool
+		wantDeleted bool
+	}{
+		{name: "empty topic", protection: true, wantDeleted: true},
+		{name: "topic with messages", messages: 10, protection: true},
+		{name: "topic with messages, force delete", messages: 10, protection: true, forceDelete: true, wantDeleted: true},
+		{name: "topic with messages, protection off", messages: 10, wantDeleted: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			deleted := false
+			avn := &mockAivenClient{kafkaTopics: &mockKafkaTopics{
+				GetFunc: func(project, service, topic string) (*aiven.KafkaTopic, error) {
+					// Two partitions share the messages
+					return &aiven.KafkaTopic{TopicName: topic, Partitions: []*aiven.Partition{
+						{EarliestOffset: 5, LatestOffset: 5 + c.messages/2},
+						{EarliestOffset: 0, LatestOffset: c.messages - c.messages/2},
+					}}, nil
+				},
+				DeleteFunc: func(project, service, topic string) error {
+					deleted = true
+					return nil
+				},
+			}}
+			topic := &v1alpha1.KafkaTopic{
+				ObjectMeta: metav1.ObjectMeta{Name: "topic", Namespace: "default"},
+				Spec: v1alpha1.KafkaTopicSpec{
+					Project:                    "project",
+					ServiceName:                "kafka",
+					DeleteProtectionIfNotEmpty: anyPointer(c.protection),
+				},
+			}
+			if c.forceDelete {
+				topic.Annotations = map[string]string{forceDeleteAnnotation: "true"}
+			}
+
+			ok, err := KafkaTopicHandler{}.delete(avn, topic)
+			if ok != c.wantDeleted || deleted != c.wantDeleted {
+				t.Errorf("delete() = %t, deleted on Aiven side %t, want %t", ok, deleted, c.wantDeleted)
+			}
+			if !c.wantDeleted && !errors.Is(err, errNotEmpty) {
+				t.Errorf("delete() error = %v, want errNotEmpty", err)
+			}
+			if c.wantDeleted && err != nil {
+				t.Errorf("delete() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Reconcile outcomes
+const (
+	reconcileOutcomeSuccess = "success"
+	reconcileOutcomeRequeue = "requeue"
+	reconcileOutcomeError   = "error"
+)
+
+// preconditionWaitSeconds how long resources wait for preconditions before they are met.
+// The reason is the last reason preconditions were not met, for instance, "KafkaNotReady"
+var preconditionWaitSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "aiven_operator_precondition_wait_seconds",
+	Help:    "Time resources spent waiting for preconditions before they were met",
+	Buckets: []float64{5, 15, 30, 60, 120, 300, 600, 900, 1800, 3600},
+}, []string{"kind", "reason"})
+
+// reconcileResultsTotal reconciles by the outcome: success, requeue or error
+var reconcileResultsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "aiven_operator_reconcile_results_total",
+	Help: "Number of resource reconciles by outcome: success, requeue or error",
+}, []string{"kind", "outcome"})
+
+// handlerCallSeconds time spent in the handler createOrUpdate, get and delete, which call Aiven API
+var handlerCallSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "aiven_operator_aiven_api_duration_seconds",
+	Help:    "Time spent in Aiven API calls of resource create or update, get and delete",
+	Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+}, []string{"kind", "operation"})
+
+// instancesNotRunning resources which are not running on Aiven side yet
+var instancesNotRunning = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "aiven_operator_instances_not_running",
+	Help: "Number of resources which are not running on Aiven side yet",
+}, []string{"kind"})
+
+// apiThrottled is 1 while the reconciles are slowed down because of the Aiven API error rate
+var apiThrottled = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "aiven_operator_api_throttled",
+	Help: "Is 1 while reconciles are slowed down because of the high Aiven API error rate (5xx and 429), otherwise 0",
+})
+
+func init() {
+	metrics.Registry.MustRegister(preconditionWaitSeconds, reconcileResultsTotal, handlerCallSeconds, instancesNotRunning, apiThrottled)
+}
+
+// observeReconcileResult counts the reconcile outcome
+func observeReconcileResult(kind string, res ctrl.Result, err error) {
+	outcome := reconcileOutcomeSuccess
+	switch {
+	case err != nil:
+		outcome = reconcileOutcomeError
+	case res.Requeue || res.RequeueAfter > 0:
+		outcome = reconcileOutcomeRequeue
+	}
+	reconcileResultsTotal.WithLabelValues(kind, outcome).Inc()
+}
+
+// observeHandlerCall records the time spent in the handler operation since start
+func observeHandlerCall(kind, operation string, start time.Time) {
+	handlerCallSeconds.WithLabelValues(kind, operation).Observe(time.Since(start).Seconds())
+}
+
+// notRunningInstances keeps the not running instances per kind, so the gauge is not changed twice for the same instance
+type notRunningInstances struct {
+	mu        sync.Mutex
+	instances map[string]map[types.NamespacedName]bool
+}
+
+var notRunning = &notRunningInstances{instances: make(map[string]map[types.NamespacedName]bool)}
+
+// set marks the instance as not running or forgets it, updates the gauge of the kind
+func (n *notRunningInstances) set(kind string, key types.NamespacedName, isNotRunning bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	keys, ok := n.instances[kind]
+	if !ok {
+		keys = make(map[types.NamespacedName]bool)
+		n.instances[kind] = keys
+	}
+	if isNotRunning {
+		keys[key] = true
+	} else {
+		delete(keys, key)
+	}
+	instancesNotRunning.WithLabelValues(kind).Set(float64(len(keys)))
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func Test_reconcileMetrics(t *testing.T) {
+	observeReconcileResult("MetricsTest", ctrl.Result{}, nil)
+	observeReconcileResult("MetricsTest", ctrl.Result{RequeueAfter: time.Second}, nil)
+	observeReconcileResult("MetricsTest", ctrl.Result{}, fmt.Errorf("failed"))
+	observeReconcileResult("MetricsTest", ctrl.Result{Requeue: true}, fmt.Errorf("failed"))
+	for outcome, want := range map[string]float64{reconcileOutcomeSuccess: 1, reconcileOutcomeRequeue: 1, reconcileOutcomeError: 2} {
+		if got := testutil.ToFloat64(reconcileResultsTotal.WithLabelValues("MetricsTest", outcome)); got != want {
+			t.Errorf("%s reconciles = %v, want %v", outcome, got, want)
+		}
+	}
+
+	// The same instance is counted once
+	a, b := types.NamespacedName{Name: "a"}, types.NamespacedName{Name: "b"}
+	notRunning.set("MetricsTest", a, true)
+	notRunning.set("MetricsTest", a, true)
+	notRunning.set("MetricsTest", b, true)
+	notRunning.set("MetricsTest", b, false)
+	if got := testutil.ToFloat64(instancesNotRunning.WithLabelValues("MetricsTest")); got != 1 {
+		t.Errorf("not running instances = %v, want 1", got)
+	}
+}
@@ -10,7 +10,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
 )
@@ -25,18 +27,20 @@ type MySQLReconciler struct {
 //+kubebuilder:rbac:groups=aiven.io,resources=mysqls/finalizers,verbs=update
 
 func (r *MySQLReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newMySQLAdapter), &v1alpha1.MySQL{})
+	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newMySQLAdapter, r.Client, r.Recorder), &v1alpha1.MySQL{})
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *MySQLReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.MySQL{}).
+		For(&v1alpha1.MySQL{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, ipFilterConfigMapHandler(r.Client, newMySQLAdapter, &v1alpha1.MySQLList{})).
 		Owns(&corev1.Secret{}).
+		Owns(&corev1.ConfigMap{}).
 		Complete(r)
 }
 
-func newMySQLAdapter(_ *aiven.Client, object client.Object) (serviceAdapter, error) {
+func newMySQLAdapter(_ AivenClient, object client.Object) (serviceAdapter, error) {
 	mysql, ok := object.(*v1alpha1.MySQL)
 	if !ok {
 		return nil, fmt.Errorf("object is not of type v1alpha1.MySQL")
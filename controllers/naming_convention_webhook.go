@@ -0,0 +1,140 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+const (
+	namingConventionWebhookPath = "/validate-aiven-io-v1alpha1-naming-convention"
+
+	// namingConventionDefaultKey ConfigMap key with the regex for the kinds without own key
+	namingConventionDefaultKey = "default"
+)
+
+//+kubebuilder:webhook:path=/validate-aiven-io-v1alpha1-naming-convention,mutating=false,failurePolicy=ignore,groups=aiven.io,resources=projects;cassandras;clickhouses;grafanas;kafkas;kafkaconnects;mysqls;opensearches;postgresqls;redis;kafkatopics,verbs=create,versions=v1alpha1,name=vnamingconvention.kb.io,sideEffects=none,admissionReviewVersions=v1
+
+// namingConventionValidator rejects Aiven resources which names don't match the naming convention regex.
+// The regex is taken from the ConfigMap (kind key first, then the default key), if set, otherwise from the flag
+type namingConventionValidator struct {
+	reader  client.Reader
+	pattern *regexp.Regexp
+
+	// configMap the ConfigMap with the regexes, empty name if not set
+	configMap types.NamespacedName
+}
+
+// SetupNamingConventionWebhook registers the naming convention webhook.
+// The webhook is registered in manifests, hence it allows everything when neither pattern nor configMap are set.
+// configMap is "namespace/name", it is read on every request, so changes apply without restart
+func SetupNamingConventionWebhook(mgr ctrl.Manager, pattern, configMap string) error {
+	v := &namingConventionValidator{reader: mgr.GetAPIReader()}
+	if pattern != "" {
+		re, err := compileNamingConvention(pattern)
+		if err != nil {
+			return err
+		}
+		v.pattern = re
+	}
+
+	if configMap != "" {
+		parts := strings.Split(configMap, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid naming convention ConfigMap %q, must be namespace/name", configMap)
+		}
+		v.configMap = types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+	}
+
+	mgr.GetWebhookServer().Register(namingConventionWebhookPath, &webhook.Admission{Handler: v})
+	return nil
+}
+
+func (v *namingConventionValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if v.pattern == nil && v.configMap.Name == "" {
+		return admission.Allowed("naming convention is not set")
+	}
+
+	re, err := v.getPattern(ctx, req.Kind.Kind)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if re == nil {
+		return admission.Allowed("no naming convention for the kind")
+	}
+
+	name, err := aivenResourceName(req)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if !re.MatchString(name) {
+		return admission.Denied(fmt.Sprintf("%s name %q doesn't match the naming convention %q", req.Kind.Kind, name, re.String()))
+	}
+	return admission.Allowed("")
+}
+
+// getPattern returns the regex for the kind, nil if there is none
+func (v *namingConventionValidator) getPattern(ctx context.Context, kind string) (*regexp.Regexp, error) {
+	if v.configMap.Name == "" {
+		return v.pattern, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	err := v.reader.Get(ctx, v.configMap, cm)
+	if apierrors.IsNotFound(err) {
+		return v.pattern, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get naming convention ConfigMap: %w", err)
+	}
+
+	for _, k := range []string{kind, namingConventionDefaultKey} {
+		if p, ok := cm.Data[k]; ok {
+			return compileNamingConvention(p)
+		}
+	}
+	return v.pattern, nil
+}
+
+// compileNamingConvention the whole name must match, so the regex is anchored
+func compileNamingConvention(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid naming convention %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// aivenResourceName returns the name the resource gets on Aiven side
+func aivenResourceName(req admission.Request) (string, error) {
+	if req.Kind.Kind == "KafkaTopic" {
+		topic := &v1alpha1.KafkaTopic{}
+		if err := json.Unmarshal(req.Object.Raw, topic); err != nil {
+			return "", err
+		}
+		return topic.GetTopicName(), nil
+	}
+
+	o := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(req.Object.Raw, o); err != nil {
+		return "", err
+	}
+	return o.Name, nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func Test_namingConventionValidator(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "naming", Namespace: "aiven"},
+		Data:       map[string]string{"KafkaTopic": `[a-z]+\.[a-z]+`},
+	}
+	k8s := fake.NewClientBuilder().WithObjects(cm).Build()
+	re, err := compileNamingConvention(`prod-[a-z]+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		kind    string
+		object  string
+		allowed bool
+	}{
+		{"PostgreSQL", `{"metadata": {"name": "prod-pg"}}`, true},
+		{"PostgreSQL", `{"metadata": {"name": "dev-pg"}}`, false},
+		{"PostgreSQL", `{"metadata": {"name": "prod-pg-1"}}`, false}, // the whole name must match
+		{"KafkaTopic", `{"metadata": {"name": "dev-topic"}, "spec": {"topicName": "orders.created"}}`, true},
+		{"KafkaTopic", `{"metadata": {"name": "orders-created"}}`, false},
+	}
+
+	v := &namingConventionValidator{reader: k8s, pattern: re, configMap: types.NamespacedName{Name: "naming", Namespace: "aiven"}}
+	for _, c := range cases {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Kind:   metav1.GroupVersionKind{Group: "aiven.io", Version: "v1alpha1", Kind: c.kind},
+			Object: runtime.RawExtension{Raw: []byte(c.object)},
+		}}
+		if got := v.Handle(context.Background(), req); got.Allowed != c.allowed {
+			t.Errorf("%s %s: allowed = %v, want %v: %s", c.kind, c.object, got.Allowed, c.allowed, got.Result.Message)
+		}
+	}
+
+	// Nothing is enforced without the regex
+	empty := &namingConventionValidator{reader: k8s}
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Kind:   metav1.GroupVersionKind{Kind: "PostgreSQL"},
+		Object: runtime.RawExtension{Raw: []byte(`{"metadata": {"name": "dev-pg"}}`)},
+	}}
+	if !empty.Handle(context.Background(), req).Allowed {
+		t.Error("not configured validator must allow everything")
+	}
+}
@@ -10,7 +10,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
 )
@@ -26,18 +28,20 @@ type OpenSearchHandler struct{}
 //+kubebuilder:rbac:groups=aiven.io,resources=opensearches/status,verbs=get;update;patch
 
 func (r *OpenSearchReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newOpenSearchAdapter), &v1alpha1.OpenSearch{})
+	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newOpenSearchAdapter, r.Client, r.Recorder), &v1alpha1.OpenSearch{})
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *OpenSearchReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.OpenSearch{}).
+		For(&v1alpha1.OpenSearch{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, ipFilterConfigMapHandler(r.Client, newOpenSearchAdapter, &v1alpha1.OpenSearchList{})).
 		Owns(&corev1.Secret{}).
+		Owns(&corev1.ConfigMap{}).
 		Complete(r)
 }
 
-func newOpenSearchAdapter(_ *aiven.Client, object client.Object) (serviceAdapter, error) {
+func newOpenSearchAdapter(_ AivenClient, object client.Object) (serviceAdapter, error) {
 	opensearch, ok := object.(*v1alpha1.OpenSearch)
 	if !ok {
 		return nil, fmt.Errorf("object is not of type v1alpha1.OpenSearch")
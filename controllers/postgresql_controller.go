@@ -10,7 +10,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
 )
@@ -24,17 +26,19 @@ type PostgreSQLReconciler struct {
 // +kubebuilder:rbac:groups=aiven.io,resources=postgresqls/status,verbs=get;update;patch
 
 func (r *PostgreSQLReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newPostgresSQLAdapter), &v1alpha1.PostgreSQL{})
+	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newPostgresSQLAdapter, r.Client, r.Recorder), &v1alpha1.PostgreSQL{})
 }
 
 func (r *PostgreSQLReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.PostgreSQL{}).
+		For(&v1alpha1.PostgreSQL{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, ipFilterConfigMapHandler(r.Client, newPostgresSQLAdapter, &v1alpha1.PostgreSQLList{})).
 		Owns(&corev1.Secret{}).
+		Owns(&corev1.ConfigMap{}).
 		Complete(r)
 }
 
-func newPostgresSQLAdapter(_ *aiven.Client, object client.Object) (serviceAdapter, error) {
+func newPostgresSQLAdapter(_ AivenClient, object client.Object) (serviceAdapter, error) {
 	pg, ok := object.(*v1alpha1.PostgreSQL)
 	if !ok {
 		return nil, fmt.Errorf("object is not of type v1alpha1.PostgresSQL")
@@ -0,0 +1,81 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// preconditionBackoff doubles the requeue interval for every failed precondition check of an instance,
+// so slow dependencies (like a service that takes minutes to provision) are not polled too often
+type preconditionBackoff struct {
+	mu       sync.Mutex
+	backoff  *requeueBackoff
+	attempts map[types.NamespacedName]int
+	waits    map[types.NamespacedName]preconditionWait
+}
+
+// preconditionWait the time of the first failed check and the reason of the last one
+type preconditionWait struct {
+	since  time.Time
+	reason string
+}
+
+func newPreconditionBackoff(base, max time.Duration) *preconditionBackoff {
+	return &preconditionBackoff{
+		backoff:  newRequeueBackoff(base, max),
+		attempts: make(map[types.NamespacedName]int),
+		waits:    make(map[types.NamespacedName]preconditionWait),
+	}
+}
+
+// next registers a failed check with the reason and returns the attempt number and the interval to requeue after
+func (b *preconditionBackoff) next(key types.NamespacedName, reason string, now time.Time) (int, time.Duration) {
+	if b == nil {
+		return 1, requeueTimeout
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	w, ok := b.waits[key]
+	if !ok {
+		w.since = now
+	}
+	w.reason = reason
+	b.waits[key] = w
+
+	b.attempts[key]++
+	attempt := b.attempts[key]
+	return attempt, b.backoff.after(attempt)
+}
+
+// reset forgets failed checks of the instance
+func (b *preconditionBackoff) reset(key types.NamespacedName) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.attempts, key)
+	delete(b.waits, key)
+}
+
+// met forgets failed checks of the instance and returns how long it waited and the reason of the last failed check.
+// Returns false if the first check succeeded
+func (b *preconditionBackoff) met(key types.NamespacedName, now time.Time) (time.Duration, string, bool) {
+	if b == nil {
+		return 0, "", false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	w, ok := b.waits[key]
+	delete(b.attempts, key)
+	delete(b.waits, key)
+	return now.Sub(w.since), w.reason, ok
+}
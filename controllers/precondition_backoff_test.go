@@ -0,0 +1,39 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_preconditionBackoff(t *testing.T) {
+	b := newPreconditionBackoff(time.Minute, 10*time.Minute)
+	b.backoff.jitter = nil
+	key := types.NamespacedName{Namespace: "default", Name: "foo"}
+	now := time.Now()
+	want := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute, 10 * time.Minute}
+	for i, w := range want {
+		attempt, got := b.next(key, "KafkaNotReady", now.Add(time.Duration(i)*time.Minute))
+		if attempt != i+1 || got != w {
+			t.Errorf("next() = %d, %s, want %d, %s", attempt, got, i+1, w)
+		}
+	}
+
+	b.next(key, "PreconditionsNotMet", now.Add(10*time.Minute))
+	waited, reason, ok := b.met(key, now.Add(12*time.Minute))
+	if !ok || waited != 12*time.Minute || reason != "PreconditionsNotMet" {
+		t.Errorf("met() = %s, %s, %v, want %s, PreconditionsNotMet, true", waited, reason, ok, 12*time.Minute)
+	}
+	if _, _, ok = b.met(key, now); ok {
+		t.Error("met() after met, want false")
+	}
+
+	b.next(key, "KafkaNotReady", now)
+	b.reset(key)
+	if _, got := b.next(key, "KafkaNotReady", now); got != time.Minute {
+		t.Errorf("next() after reset = %s, want %s", got, time.Minute)
+	}
+}
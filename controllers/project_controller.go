@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
@@ -35,17 +36,17 @@ func (r *ProjectReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 func (r *ProjectReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Project{}).
+		For(&v1alpha1.Project{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
 		Owns(&corev1.Secret{}).
 		Complete(r)
 }
 
-func (h ProjectHandler) getLongCardID(client *aiven.Client, cardID string) (*string, error) {
+func (h ProjectHandler) getLongCardID(client AivenClient, cardID string) (*string, error) {
 	if cardID == "" {
 		return nil, nil
 	}
 
-	card, err := client.CardsHandler.Get(cardID)
+	card, err := client.Cards().Get(cardID)
 	if err != nil {
 		return nil, err
 	}
@@ -58,7 +59,7 @@ func (h ProjectHandler) getLongCardID(client *aiven.Client, cardID string) (*str
 }
 
 // create creates a project on Aiven side
-func (h ProjectHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs []client.Object) error {
+func (h ProjectHandler) createOrUpdate(avn AivenClient, i client.Object, refs []client.Object) error {
 	project, err := h.convert(i)
 	if err != nil {
 		return err
@@ -87,7 +88,7 @@ func (h ProjectHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs
 	var reason string
 	var p *aiven.Project
 	if !exists {
-		p, err = avn.Projects.Create(aiven.CreateProjectRequest{
+		p, err = avn.Projects().Create(aiven.CreateProjectRequest{
 			BillingAddress:   toOptionalStringPointer(project.Spec.BillingAddress),
 			BillingEmails:    billingEmails,
 			BillingExtraText: toOptionalStringPointer(project.Spec.BillingExtraText),
@@ -110,7 +111,7 @@ func (h ProjectHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs
 
 		reason = "Created"
 	} else {
-		p, err = avn.Projects.Update(project.Name, aiven.UpdateProjectRequest{
+		p, err = avn.Projects().Update(project.Name, aiven.UpdateProjectRequest{
 			BillingAddress:   toOptionalStringPointer(project.Spec.BillingAddress),
 			BillingEmails:    billingEmails,
 			BillingExtraText: toOptionalStringPointer(project.Spec.BillingExtraText),
@@ -122,6 +123,9 @@ func (h ProjectHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs
 			BillingCurrency:  project.Spec.BillingCurrency,
 			Tags:             project.Spec.Tags,
 		})
+		if isNotChangedError(err) {
+			p, err = avn.Projects().Get(project.Name)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to update project on aiven side: %w", err)
 		}
@@ -149,13 +153,13 @@ func (h ProjectHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs
 	return nil
 }
 
-func (h ProjectHandler) get(avn *aiven.Client, i client.Object) (*corev1.Secret, error) {
+func (h ProjectHandler) get(avn AivenClient, i client.Object) (*corev1.Secret, error) {
 	project, err := h.convert(i)
 	if err != nil {
 		return nil, err
 	}
 
-	cert, err := avn.CA.Get(project.Name)
+	cert, err := avn.CA().Get(project.Name)
 	if err != nil {
 		return nil, fmt.Errorf("aiven client error %w", err)
 	}
@@ -178,8 +182,8 @@ func (h ProjectHandler) get(avn *aiven.Client, i client.Object) (*corev1.Secret,
 }
 
 // exists checks if project already exists on Aiven side
-func (h ProjectHandler) exists(avn *aiven.Client, project *v1alpha1.Project) (bool, error) {
-	pr, err := avn.Projects.Get(project.Name)
+func (h ProjectHandler) exists(avn AivenClient, project *v1alpha1.Project) (bool, error) {
+	pr, err := avn.Projects().Get(project.Name)
 	if aiven.IsNotFound(err) {
 		return false, nil
 	}
@@ -188,14 +192,14 @@ func (h ProjectHandler) exists(avn *aiven.Client, project *v1alpha1.Project) (bo
 }
 
 // delete deletes Aiven project
-func (h ProjectHandler) delete(avn *aiven.Client, i client.Object) (bool, error) {
+func (h ProjectHandler) delete(avn AivenClient, i client.Object) (bool, error) {
 	project, err := h.convert(i)
 	if err != nil {
 		return false, err
 	}
 
 	// Delete project on Aiven side
-	if err := avn.Projects.Delete(project.Name); err != nil {
+	if err := avn.Projects().Delete(project.Name); err != nil {
 		var skip bool
 
 		// If project not found then there is nothing to delete
@@ -235,6 +239,6 @@ func (h ProjectHandler) convert(i client.Object) (*v1alpha1.Project, error) {
 	return p, nil
 }
 
-func (h ProjectHandler) checkPreconditions(_ *aiven.Client, _ client.Object) (bool, error) {
+func (h ProjectHandler) checkPreconditions(_ AivenClient, _ client.Object) (bool, error) {
 	return true, nil
 }
@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
@@ -38,17 +39,17 @@ func (r *ProjectVPCReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 func (r *ProjectVPCReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.ProjectVPC{}).
+		For(&v1alpha1.ProjectVPC{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
 		Complete(r)
 }
 
-func (h ProjectVPCHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs []client.Object) error {
+func (h ProjectVPCHandler) createOrUpdate(avn AivenClient, i client.Object, refs []client.Object) error {
 	projectVPC, err := h.convert(i)
 	if err != nil {
 		return err
 	}
 
-	vpc, err := avn.VPCs.Create(projectVPC.Spec.Project, aiven.CreateVPCRequest{
+	vpc, err := avn.VPCs().Create(projectVPC.Spec.Project, aiven.CreateVPCRequest{
 		CloudName:   projectVPC.Spec.CloudName,
 		NetworkCIDR: projectVPC.Spec.NetworkCidr,
 	})
@@ -72,7 +73,7 @@ func (h ProjectVPCHandler) createOrUpdate(avn *aiven.Client, i client.Object, re
 	return nil
 }
 
-func (h ProjectVPCHandler) delete(avn *aiven.Client, i client.Object) (bool, error) {
+func (h ProjectVPCHandler) delete(avn AivenClient, i client.Object) (bool, error) {
 	projectVPC, err := h.convert(i)
 	if err != nil {
 		return false, err
@@ -95,7 +96,7 @@ func (h ProjectVPCHandler) delete(avn *aiven.Client, i client.Object) (bool, err
 			vpcID = vpc.ProjectVPCID
 		}
 
-		err := avn.VPCs.Delete(projectVPC.Spec.Project, vpcID)
+		err := avn.VPCs().Delete(projectVPC.Spec.Project, vpcID)
 		if isDependencyError(err) {
 			return false, fmt.Errorf("%w: %s", v1alpha1.ErrDeleteDependencies, err)
 		}
@@ -112,8 +113,8 @@ func (h ProjectVPCHandler) delete(avn *aiven.Client, i client.Object) (bool, err
 	return false, nil
 }
 
-func (h ProjectVPCHandler) getVPC(avn *aiven.Client, projectVPC *v1alpha1.ProjectVPC) (*aiven.VPC, error) {
-	vpcs, err := avn.VPCs.List(projectVPC.Spec.Project)
+func (h ProjectVPCHandler) getVPC(avn AivenClient, projectVPC *v1alpha1.ProjectVPC) (*aiven.VPC, error) {
+	vpcs, err := avn.VPCs().List(projectVPC.Spec.Project)
 	if err != nil {
 		return nil, err
 	}
@@ -127,7 +128,7 @@ func (h ProjectVPCHandler) getVPC(avn *aiven.Client, projectVPC *v1alpha1.Projec
 	return nil, nil
 }
 
-func (h ProjectVPCHandler) get(avn *aiven.Client, i client.Object) (*corev1.Secret, error) {
+func (h ProjectVPCHandler) get(avn AivenClient, i client.Object) (*corev1.Secret, error) {
 	projectVPC, err := h.convert(i)
 	if err != nil {
 		return nil, err
@@ -150,7 +151,7 @@ func (h ProjectVPCHandler) get(avn *aiven.Client, i client.Object) (*corev1.Secr
 	return nil, nil
 }
 
-func (h ProjectVPCHandler) checkPreconditions(_ *aiven.Client, _ client.Object) (bool, error) {
+func (h ProjectVPCHandler) checkPreconditions(_ AivenClient, _ client.Object) (bool, error) {
 	return true, nil
 }
 
@@ -0,0 +1,49 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// stateChangeEvents normal events that are recorded in the quiet mode
+var stateChangeEvents = map[string]bool{
+	eventCreatedAtAiven:             true,
+	eventUpdatedAtAiven:             true,
+	eventSuccessfullyDeletedAtAiven: true,
+	eventGenerationProcessed:        true,
+}
+
+// quietRecorder drops routine normal events, which are emitted on every reconcile,
+// keeps warnings and state changes
+type quietRecorder struct {
+	record.EventRecorder
+}
+
+func newQuietRecorder(rec record.EventRecorder) record.EventRecorder {
+	return &quietRecorder{EventRecorder: rec}
+}
+
+func (r *quietRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if r.keep(eventtype, reason) {
+		r.EventRecorder.Event(object, eventtype, reason, message)
+	}
+}
+
+func (r *quietRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if r.keep(eventtype, reason) {
+		r.EventRecorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	}
+}
+
+func (r *quietRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if r.keep(eventtype, reason) {
+		r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	}
+}
+
+func (r *quietRecorder) keep(eventtype, reason string) bool {
+	return eventtype != corev1.EventTypeNormal || stateChangeEvents[reason]
+}
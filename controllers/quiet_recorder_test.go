@@ -0,0 +1,37 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+func Test_quietRecorder(t *testing.T) {
+	fakeRecorder := record.NewFakeRecorder(10)
+	rec := newQuietRecorder(fakeRecorder)
+	o := &v1alpha1.Kafka{}
+
+	rec.Event(o, corev1.EventTypeNormal, eventReconciliationStarted, "starting reconciliation")
+	rec.Event(o, corev1.EventTypeNormal, eventCreatedAtAiven, "created")
+	rec.Eventf(o, corev1.EventTypeWarning, eventUnableToDelete, "unable to delete: %s", "error")
+	rec.Eventf(o, corev1.EventTypeNormal, eventPreconditionsAreMet, "preconditions are met")
+	close(fakeRecorder.Events)
+
+	var got []string
+	for e := range fakeRecorder.Events {
+		got = append(got, e)
+	}
+	want := []string{
+		"Normal CreatedAtAiven created",
+		"Warning UnableToDelete unable to delete: error",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("recorded events = %v, want %v", got, want)
+	}
+}
@@ -10,7 +10,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
 )
@@ -26,18 +28,20 @@ type RedisHandler struct{}
 //+kubebuilder:rbac:groups=aiven.io,resources=redis/status,verbs=get;update;patch
 
 func (r *RedisReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newRedisAdapter), &v1alpha1.Redis{})
+	return r.reconcileInstance(ctx, req, newGenericServiceHandler(newRedisAdapter, r.Client, r.Recorder), &v1alpha1.Redis{})
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *RedisReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Redis{}).
+		For(&v1alpha1.Redis{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, ipFilterConfigMapHandler(r.Client, newRedisAdapter, &v1alpha1.RedisList{})).
 		Owns(&corev1.Secret{}).
+		Owns(&corev1.ConfigMap{}).
 		Complete(r)
 }
 
-func newRedisAdapter(_ *aiven.Client, object client.Object) (serviceAdapter, error) {
+func newRedisAdapter(_ AivenClient, object client.Object) (serviceAdapter, error) {
 	redis, ok := object.(*v1alpha1.Redis)
 	if !ok {
 		return nil, fmt.Errorf("object is not of type v1alpha1.Redis")
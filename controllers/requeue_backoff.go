@@ -0,0 +1,89 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultRequeueMaxInterval caps the backoff if the maximum is not set
+const defaultRequeueMaxInterval = 10 * time.Minute
+
+// requeueBackoff doubles the requeue interval on every attempt up to the maximum.
+// Jitter spreads the requeues of the instances created together, so they don't poll Aiven API at once
+type requeueBackoff struct {
+	base time.Duration
+	max  time.Duration
+
+	// jitter returns a random value in [0, n), no jitter if nil
+	jitter func(n int64) int64
+}
+
+func newRequeueBackoff(base, max time.Duration) *requeueBackoff {
+	if base <= 0 {
+		base = requeueTimeout
+	}
+	if max <= 0 {
+		max = defaultRequeueMaxInterval
+	}
+	if max < base {
+		max = base
+	}
+	return &requeueBackoff{base: base, max: max, jitter: rand.Int63n}
+}
+
+// after returns the interval to requeue after for the attempt, starting from 1.
+// Jitter takes up to a fifth off the interval, so it never exceeds the maximum
+func (b *requeueBackoff) after(attempt int) time.Duration {
+	if b == nil {
+		return requeueTimeout
+	}
+
+	d := b.base
+	for i := 1; i < attempt && d < b.max; i++ {
+		d *= 2
+	}
+	if d > b.max {
+		d = b.max
+	}
+	if b.jitter != nil && d/5 > 0 {
+		d -= time.Duration(b.jitter(int64(d / 5)))
+	}
+	return d
+}
+
+// requeueAttempt returns the number of requeues of the instance waiting to be running.
+// The counter is stored with the instance UID and generation, so a new generation or a recreated instance starts over
+func requeueAttempt(o client.Object) int {
+	parts := strings.Split(o.GetAnnotations()[requeueAttemptAnnotation], "/")
+	if len(parts) != 3 || parts[0] != string(o.GetUID()) || parts[1] != strconv.FormatInt(o.GetGeneration(), formatIntBaseDecimal) {
+		return 0
+	}
+
+	attempt, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return 0
+	}
+	return attempt
+}
+
+// setRequeueAttempt stores the attempt in the annotation, zero removes it
+func setRequeueAttempt(o client.Object, attempt int) {
+	annotations := o.GetAnnotations()
+	if attempt == 0 {
+		delete(annotations, requeueAttemptAnnotation)
+		return
+	}
+
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[requeueAttemptAnnotation] = fmt.Sprintf("%s/%d/%d", o.GetUID(), o.GetGeneration(), attempt)
+	o.SetAnnotations(annotations)
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+func Test_requeueBackoff(t *testing.T) {
+	b := newRequeueBackoff(10*time.Second, time.Minute)
+	b.jitter = nil
+	want := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	for i, w := range want {
+		if got := b.after(i + 1); got != w {
+			t.Errorf("after(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+
+	// Jitter never exceeds the maximum
+	b.jitter = func(n int64) int64 { return n - 1 }
+	if got := b.after(10); got > time.Minute || got < 48*time.Second {
+		t.Errorf("after() with jitter = %s, want within [48s, 1m]", got)
+	}
+
+	pg := &v1alpha1.PostgreSQL{ObjectMeta: metav1.ObjectMeta{Name: "pg", UID: "uid", Generation: 1}}
+	setRequeueAttempt(pg, requeueAttempt(pg)+1)
+	setRequeueAttempt(pg, requeueAttempt(pg)+1)
+	if got := requeueAttempt(pg); got != 2 {
+		t.Errorf("requeueAttempt() = %d, want 2", got)
+	}
+
+	// A new generation starts over
+	pg.Generation = 2
+	if got := requeueAttempt(pg); got != 0 {
+		t.Errorf("requeueAttempt() of the new generation = %d, want 0", got)
+	}
+
+	setRequeueAttempt(pg, 0)
+	if _, ok := pg.Annotations[requeueAttemptAnnotation]; ok {
+		t.Error("zero attempt must remove the annotation")
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// scrapeConfigGVK Prometheus Operator ScrapeConfig, which scrapes targets outside the cluster.
+// ServiceMonitor and PodMonitor select in-cluster Services and Pods only, hence can't target Aiven services
+var scrapeConfigGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1alpha1", Kind: "ScrapeConfig"}
+
+// hasScrapeConfigCRD returns true if the ScrapeConfig CRD is installed
+func hasScrapeConfigCRD(mapper meta.RESTMapper) bool {
+	_, err := mapper.RESTMapping(scrapeConfigGVK.GroupKind(), scrapeConfigGVK.Version)
+	return err == nil
+}
+
+// createOrUpdateScrapeConfig creates the ScrapeConfig for the service metrics endpoint,
+// deletes it when the option is turned off
+func (i instanceReconcilerHelper) createOrUpdateScrapeConfig(ctx context.Context, owner client.Object, secret *corev1.Secret) error {
+	o, ok := owner.(serviceConnInfoSecretTargetObject)
+	if !ok || !i.sc {
+		return nil
+	}
+
+	want := newUnstructured(scrapeConfigGVK, secret.Name, owner.GetNamespace())
+	target := o.GetServiceConnInfoSecretTarget()
+	if !fromAnyPointer(target.Prometheus) || !fromAnyPointer(target.PrometheusScrapeConfig) {
+		// Deletes the config created before the option was turned off, never touches foreign objects
+		err := i.k8s.Get(ctx, client.ObjectKeyFromObject(want), want)
+		if err != nil || !metav1.IsControlledBy(want, owner) {
+			return client.IgnoreNotFound(ignoreNoMatch(err))
+		}
+		return client.IgnoreNotFound(i.k8s.Delete(ctx, want))
+	}
+
+	// The service waits for the prometheus integration
+	if secret.StringData["PROMETHEUS_URL"] == "" {
+		return nil
+	}
+
+	spec, err := newScrapeConfigSpec(secret)
+	if err != nil {
+		return err
+	}
+
+	_, err = controllerutil.CreateOrUpdate(ctx, i.k8s, want, func() error {
+		want.Object["spec"] = spec
+		return ctrl.SetControllerReference(owner, want, i.k8s.Scheme())
+	})
+	if meta.IsNoMatchError(err) {
+		// The CRD has been removed since the operator start
+		i.log.Info("unable to create scrape config, CRD is not installed")
+		return nil
+	}
+	return err
+}
+
+// ignoreNoMatch returns nil on "no matches for kind" error, which means the CRD is not installed
+func ignoreNoMatch(err error) error {
+	if meta.IsNoMatchError(err) {
+		return nil
+	}
+	return err
+}
+
+// newScrapeConfigSpec returns ScrapeConfig spec with the metrics endpoint and the secret basic auth keys
+func newScrapeConfigSpec(secret *corev1.Secret) (map[string]interface{}, error) {
+	u, err := url.Parse(secret.StringData["PROMETHEUS_URL"])
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid prometheus url %q", secret.StringData["PROMETHEUS_URL"])
+	}
+
+	return map[string]interface{}{
+		"staticConfigs": []interface{}{
+			map[string]interface{}{"targets": []interface{}{u.Host}},
+		},
+		"scheme":      "HTTPS",
+		"metricsPath": u.Path,
+		"basicAuth": map[string]interface{}{
+			"username": map[string]interface{}{"name": secret.Name, "key": "PROMETHEUS_USERNAME"},
+			"password": map[string]interface{}{"name": secret.Name, "key": "PROMETHEUS_PASSWORD"},
+		},
+	}, nil
+}
+
+func newUnstructured(gvk schema.GroupVersionKind, name, namespace string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetName(name)
+	u.SetNamespace(namespace)
+	return u
+}
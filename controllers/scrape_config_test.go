@@ -0,0 +1,96 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+func Test_newScrapeConfigSpec(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-kafka"},
+		StringData: map[string]string{"PROMETHEUS_URL": "https://kafka.aivencloud.com:9273/metrics"},
+	}
+	got, err := newScrapeConfigSpec(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"staticConfigs": []interface{}{
+			map[string]interface{}{"targets": []interface{}{"kafka.aivencloud.com:9273"}},
+		},
+		"scheme":      "HTTPS",
+		"metricsPath": "/metrics",
+		"basicAuth": map[string]interface{}{
+			"username": map[string]interface{}{"name": "my-kafka", "key": "PROMETHEUS_USERNAME"},
+			"password": map[string]interface{}{"name": "my-kafka", "key": "PROMETHEUS_PASSWORD"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("newScrapeConfigSpec() = %v, want %v", got, want)
+	}
+
+	if _, err = newScrapeConfigSpec(&corev1.Secret{}); err == nil {
+		t.Error("newScrapeConfigSpec() expected error for secret without prometheus url")
+	}
+}
+
+func Test_createOrUpdateScrapeConfigWithSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	scheme.AddKnownTypeWithName(scrapeConfigGVK, &unstructured.Unstructured{})
+
+	pg := &v1alpha1.PostgreSQL{ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "default", UID: "uid"}}
+	pg.Spec.ConnInfoSecretTarget.Prometheus = anyPointer(true)
+	pg.Spec.ConnInfoSecretTarget.PrometheusScrapeConfig = anyPointer(true)
+	k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pg).Build()
+
+	h := &mockHandler{running: true, secret: &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "default"},
+		StringData: map[string]string{
+			"PROMETHEUS_URL":      "https://pg.aivencloud.com:9273/metrics",
+			"PROMETHEUS_USERNAME": "user",
+			"PROMETHEUS_PASSWORD": "password",
+		},
+	}}
+	helper := instanceReconcilerHelper{k8s: k8s, h: h, log: logr.Discard(), rec: record.NewFakeRecorder(10), sc: true}
+	running, _, err := helper.updateInstanceStateAndSecretUntilRunning(context.Background(), pg)
+	if err != nil || !running {
+		t.Fatalf("updateInstanceStateAndSecretUntilRunning() = %t, %v, want running", running, err)
+	}
+
+	scrapeConfig := newUnstructured(scrapeConfigGVK, "pg", "default")
+	if err = k8s.Get(context.Background(), client.ObjectKeyFromObject(scrapeConfig), scrapeConfig); err != nil {
+		t.Fatalf("scrape config is not created: %s", err)
+	}
+	targets, _, _ := unstructured.NestedSlice(scrapeConfig.Object, "spec", "staticConfigs")
+	if len(targets) != 1 || !reflect.DeepEqual(targets[0], map[string]interface{}{"targets": []interface{}{"pg.aivencloud.com:9273"}}) {
+		t.Errorf("scrape config targets = %v", targets)
+	}
+
+	secret := &corev1.Secret{}
+	if err = k8s.Get(context.Background(), client.ObjectKeyFromObject(scrapeConfig), secret); err != nil {
+		t.Fatalf("secret is not created: %s", err)
+	}
+	if string(secret.Data["PROMETHEUS_URL"]) != "https://pg.aivencloud.com:9273/metrics" {
+		t.Errorf("PROMETHEUS_URL = %q", secret.Data["PROMETHEUS_URL"])
+	}
+}
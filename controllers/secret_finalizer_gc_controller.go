@@ -58,16 +58,9 @@ func (c *SecretFinalizerGCController) SetupWithManager(mgr ctrl.Manager, hasDefa
 			&source.Kind{Type: aivenManagedTypes[i]},
 			handler.EnqueueRequestsFromMapFunc(func(a client.Object) []reconcile.Request {
 				ao := a.(aivenManagedObject)
-				if auth := ao.AuthSecretRef(); auth != nil {
-					return []reconcile.Request{
-						{
-							NamespacedName: types.NamespacedName{
-								Name:      auth.Name,
-								Namespace: ao.GetNamespace(),
-							},
-						},
-					}
-				} else if !hasDefaultToken {
+				if auth := ao.AuthSecretRef(); auth != nil && auth.Name != "" {
+					return []reconcile.Request{{NamespacedName: authSecretKey(ao.GetNamespace(), auth)}}
+				} else if auth == nil && !hasDefaultToken {
 					gvk := ao.GetObjectKind().GroupVersionKind().String()
 					namespacedName := types.NamespacedName{
 						Name:      ao.GetName(),
@@ -150,16 +143,17 @@ func (c *SecretFinalizerGCController) secretIsStillNeeded(ctx context.Context, s
 }
 
 const (
-	// secretRefIndexKey is the key we index the name of the secret with
-	// so we can efficiently list all resources that use this secret
+	// secretRefIndexKey is the key we index the namespaced name of the secret with
+	// so we can efficiently list all resources that use this secret, in any namespace
 	secretRefIndexKey = "spec.auth_secret_ref.name"
 )
 
-// secretRefIndexFunc indexes the client token secret names of aiven managed objects
+// secretRefIndexFunc indexes the client token secret "namespace/name" of aiven managed objects
 func secretRefIndexFunc(o client.Object) []string {
 	if aivenObj, ok := o.(aivenManagedObject); ok {
-		if auth := aivenObj.AuthSecretRef(); auth != nil {
-			return []string{auth.Name}
+		// Token files and environment variables are not secrets
+		if auth := aivenObj.AuthSecretRef(); auth != nil && auth.Name != "" {
+			return []string{authSecretKey(aivenObj.GetNamespace(), auth).String()}
 		}
 	}
 	return nil
@@ -177,8 +171,7 @@ func indexClientSecretRefFields(ctx context.Context, mgr ctrl.Manager, objs ...a
 // check if an instance uses this secret
 func instancesThatUseThisSecret(secret *corev1.Secret) *client.ListOptions {
 	return &client.ListOptions{
-		Namespace:     secret.GetNamespace(),
-		FieldSelector: fields.OneTermEqualSelector(secretRefIndexKey, secret.GetName()),
+		FieldSelector: fields.OneTermEqualSelector(secretRefIndexKey, client.ObjectKeyFromObject(secret).String()),
 		Limit:         1,
 	}
 }
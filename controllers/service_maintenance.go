@@ -0,0 +1,137 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aiven/aiven-go-client"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+const eventMaintenanceStarted = "MaintenanceStarted"
+
+// maintenanceCheckInterval how often the running service is checked while the updates are pending within a window
+const maintenanceCheckInterval = 5 * time.Minute
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// checkMaintenance sets the maintenance status and starts the pending updates, if autoApply is on and now is within a window.
+// The maintenance is started once per window
+func (h *genericServiceHandler) checkMaintenance(a AivenClient, object client.Object, o serviceAdapter, s *aiven.Service, now time.Time) error {
+	spec := o.getServiceCommonSpec()
+	status := o.getServiceStatus()
+
+	pending := len(s.MaintenanceWindow.Updates)
+	if pending == 0 && spec.Maintenance == nil {
+		status.Maintenance = nil
+		return nil
+	}
+
+	var startedAt *metav1.Time
+	if status.Maintenance != nil {
+		startedAt = status.Maintenance.StartedAt
+	}
+	status.Maintenance = &v1alpha1.ServiceMaintenanceStatus{PendingUpdates: pending, StartedAt: startedAt}
+	if pending == 0 || spec.Maintenance == nil || !fromAnyPointer(spec.Maintenance.AutoApply) {
+		return nil
+	}
+
+	start, within, err := nextMaintenanceWindow(spec.Maintenance.AllowedWindows, now)
+	if err != nil {
+		return err
+	}
+	if !within {
+		status.Maintenance.NextWindow = &metav1.Time{Time: start}
+		return nil
+	}
+
+	// The service is not running while the maintenance is applied
+	if s.State != "RUNNING" || (startedAt != nil && !startedAt.Time.Before(start)) {
+		return nil
+	}
+
+	err = a.RawPut(fmt.Sprintf("/v1/project/%s/service/%s/maintenance/start", url.PathEscape(spec.Project), url.PathEscape(s.Name)), nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start maintenance: %w", err)
+	}
+	status.Maintenance.StartedAt = &metav1.Time{Time: now}
+	h.rec.Eventf(object, corev1.EventTypeNormal, eventMaintenanceStarted, "started %d pending maintenance updates", pending)
+	return nil
+}
+
+// nextCheck requeues the running service to apply the pending updates when the next window starts,
+// or to check the prometheus integration it waits for
+func (h *genericServiceHandler) nextCheck(object client.Object, now time.Time) time.Duration {
+	o, err := h.fabric(nil, object)
+	if err != nil {
+		return 0
+	}
+
+	status := o.getServiceStatus()
+	next := nextMaintenanceCheck(o.getServiceCommonSpec(), status, now)
+	if meta.IsStatusConditionFalse(status.Conditions, conditionTypePrometheusReady) && (next <= 0 || next > prometheusCheckInterval) {
+		return prometheusCheckInterval
+	}
+	return next
+}
+
+func nextMaintenanceCheck(spec *v1alpha1.ServiceCommonSpec, status *v1alpha1.ServiceStatus, now time.Time) time.Duration {
+	if spec.Maintenance == nil || !fromAnyPointer(spec.Maintenance.AutoApply) || status.Maintenance == nil || status.Maintenance.PendingUpdates == 0 {
+		return 0
+	}
+	if status.Maintenance.NextWindow != nil {
+		return status.Maintenance.NextWindow.Sub(now)
+	}
+	return maintenanceCheckInterval
+}
+
+// nextMaintenanceWindow returns the start of the window now is within or of the next window.
+// Any time is allowed if there are no windows
+func nextMaintenanceWindow(windows []v1alpha1.MaintenanceAllowedWindow, now time.Time) (time.Time, bool, error) {
+	now = now.UTC()
+	if len(windows) == 0 {
+		return now, true, nil
+	}
+
+	var next time.Time
+	for _, w := range windows {
+		day, ok := weekdays[strings.ToLower(w.DayOfWeek)]
+		if !ok {
+			return time.Time{}, false, fmt.Errorf("invalid maintenance window day %q", w.DayOfWeek)
+		}
+		t, err := time.Parse("15:04", w.StartTime)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid maintenance window start time %q: %w", w.StartTime, err)
+		}
+
+		// The window of this week, the previous week's one may still last
+		start := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC).
+			AddDate(0, 0, int(day-now.Weekday()))
+		for _, s := range []time.Time{start.AddDate(0, 0, -7), start, start.AddDate(0, 0, 7)} {
+			if !now.Before(s) && now.Before(s.Add(w.Duration.Duration)) {
+				return s, true, nil
+			}
+			if s.After(now) && (next.IsZero() || s.Before(next)) {
+				next = s
+			}
+		}
+	}
+	return next, false, nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aiven/aiven-go-client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+func Test_checkMaintenance(t *testing.T) {
+	// Wednesday
+	now := time.Date(2023, 3, 22, 10, 30, 0, 0, time.UTC)
+	windows := []v1alpha1.MaintenanceAllowedWindow{
+		{DayOfWeek: "wednesday", StartTime: "10:00", Duration: metav1.Duration{Duration: time.Hour}},
+		{DayOfWeek: "monday", StartTime: "23:00", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+	}
+
+	start, within, err := nextMaintenanceWindow(windows, now)
+	if err != nil || !within || !start.Equal(time.Date(2023, 3, 22, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("nextMaintenanceWindow() = %s, %t, %v, want within the wednesday window", start, within, err)
+	}
+
+	// The monday window lasts until tuesday
+	start, within, _ = nextMaintenanceWindow(windows, time.Date(2023, 3, 21, 0, 30, 0, 0, time.UTC))
+	if !within || !start.Equal(time.Date(2023, 3, 20, 23, 0, 0, 0, time.UTC)) {
+		t.Errorf("nextMaintenanceWindow() = %s, %t, want within the monday window", start, within)
+	}
+
+	start, within, _ = nextMaintenanceWindow(windows, now.Add(time.Hour))
+	if within || !start.Equal(time.Date(2023, 3, 27, 23, 0, 0, 0, time.UTC)) {
+		t.Errorf("nextMaintenanceWindow() = %s, %t, want the next monday window", start, within)
+	}
+
+	var started []string
+	avn := &mockAivenClient{rawPut: func(path string, body, v interface{}) error {
+		started = append(started, path)
+		return nil
+	}}
+	pg := &v1alpha1.PostgreSQL{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg"},
+		Spec: v1alpha1.PostgreSQLSpec{ServiceCommonSpec: v1alpha1.ServiceCommonSpec{
+			Project:     "foo",
+			Maintenance: &v1alpha1.ServiceMaintenance{AutoApply: anyPointer(true), AllowedWindows: windows},
+		}},
+	}
+	s := &aiven.Service{Name: "pg", State: "RUNNING", MaintenanceWindow: aiven.MaintenanceWindow{
+		Updates: []*aiven.MaintenanceUpdate{{Description: "os update"}},
+	}}
+
+	h := &genericServiceHandler{fabric: newPostgresSQLAdapter, rec: record.NewFakeRecorder(10)}
+	o, _ := newPostgresSQLAdapter(nil, pg)
+
+	// Out of the windows, waits for the next one
+	if err = h.checkMaintenance(avn, pg, o, s, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if len(started) != 0 || pg.Status.Maintenance.PendingUpdates != 1 || pg.Status.Maintenance.NextWindow == nil {
+		t.Errorf("maintenance must wait for the next window, status %+v", pg.Status.Maintenance)
+	}
+	if after := h.nextCheck(pg, now.Add(time.Hour)); after != pg.Status.Maintenance.NextWindow.Sub(now.Add(time.Hour)) {
+		t.Errorf("nextCheck() = %s, want the next window start", after)
+	}
+
+	// Within the window, starts the maintenance once
+	for i := 0; i < 2; i++ {
+		if err = h.checkMaintenance(avn, pg, o, s, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(started) != 1 || started[0] != "/v1/project/foo/service/pg/maintenance/start" {
+		t.Errorf("maintenance must be started once, got %v", started)
+	}
+	if after := h.nextCheck(pg, now); after != maintenanceCheckInterval {
+		t.Errorf("nextCheck() = %s, want %s", after, maintenanceCheckInterval)
+	}
+
+	// No pending updates, no requeue
+	s.MaintenanceWindow.Updates = nil
+	if err = h.checkMaintenance(avn, pg, o, s, now); err != nil {
+		t.Fatal(err)
+	}
+	if pg.Status.Maintenance.PendingUpdates != 0 || h.nextCheck(pg, now) != 0 {
+		t.Errorf("no pending updates must not requeue, status %+v", pg.Status.Maintenance)
+	}
+}
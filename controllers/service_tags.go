@@ -0,0 +1,89 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aiven/aiven-go-client"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateServiceTags applies the desired tags to the service.
+// Aiven replaces the whole tag set, so the set is sent only if it differs from the live one.
+// Tags set outside the operator are kept, unless they are set in the spec
+func updateServiceTags(a AivenClient, project, service string, o client.Object, desired map[string]string) error {
+	lastApplied, err := getLastAppliedTags(o)
+	if err != nil {
+		return err
+	}
+
+	// Nothing was ever applied, nothing to remove
+	if len(desired) == 0 && len(lastApplied) == 0 {
+		return nil
+	}
+
+	live, err := a.ServiceTags().Get(project, service)
+	if err != nil {
+		return fmt.Errorf("failed to get service tags: %w", err)
+	}
+
+	if tags, changed := serviceTagsDiff(desired, live.Tags, lastApplied); changed {
+		_, err = a.ServiceTags().Set(project, service, aiven.ServiceTagsRequest{Tags: tags})
+		if err != nil {
+			return fmt.Errorf("failed to set service tags: %w", err)
+		}
+	}
+
+	b, err := json.Marshal(desired)
+	if err != nil {
+		return err
+	}
+	annotations := o.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[lastAppliedTagsAnnotation] = string(b)
+	o.SetAnnotations(annotations)
+	return nil
+}
+
+// serviceTagsDiff returns the live tags with the desired ones added or changed,
+// and the last applied ones removed if they are missing in the desired tags.
+// Returns false if the result equals the live tags
+func serviceTagsDiff(desired, live, lastApplied map[string]string) (map[string]string, bool) {
+	tags := make(map[string]string, len(live)+len(desired))
+	changed := false
+	for k, v := range live {
+		if _, ok := desired[k]; !ok {
+			if _, ok = lastApplied[k]; ok {
+				changed = true
+				continue
+			}
+		}
+		tags[k] = v
+	}
+
+	for k, v := range desired {
+		if lv, ok := live[k]; !ok || lv != v {
+			changed = true
+		}
+		tags[k] = v
+	}
+	return tags, changed
+}
+
+// getLastAppliedTags returns the tags stored in the annotation
+func getLastAppliedTags(o client.Object) (map[string]string, error) {
+	v, ok := o.GetAnnotations()[lastAppliedTagsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var m map[string]string
+	if err := json.Unmarshal([]byte(v), &m); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", lastAppliedTagsAnnotation, err)
+	}
+	return m, nil
+}
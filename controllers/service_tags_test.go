@@ -0,0 +1,50 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_serviceTagsDiff(t *testing.T) {
+	cases := []struct {
+		name        string
+		desired     map[string]string
+		live        map[string]string
+		lastApplied map[string]string
+		want        map[string]string
+		changed     bool
+	}{
+		{
+			name:    "no changes",
+			desired: map[string]string{"env": "prod"},
+			live:    map[string]string{"env": "prod", "team": "data"},
+			want:    map[string]string{"env": "prod", "team": "data"},
+		},
+		{
+			name:    "added and changed",
+			desired: map[string]string{"env": "dev", "owner": "me"},
+			live:    map[string]string{"env": "prod"},
+			want:    map[string]string{"env": "dev", "owner": "me"},
+			changed: true,
+		},
+		{
+			name:        "removed from spec",
+			desired:     map[string]string{"env": "prod"},
+			live:        map[string]string{"env": "prod", "owner": "me", "team": "data"},
+			lastApplied: map[string]string{"env": "prod", "owner": "me"},
+			want:        map[string]string{"env": "prod", "team": "data"},
+			changed:     true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := serviceTagsDiff(tt.desired, tt.live, tt.lastApplied)
+			if changed != tt.changed || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("serviceTagsDiff() = %v, %v, want %v, %v", got, changed, tt.want, tt.changed)
+			}
+		})
+	}
+}
@@ -7,13 +7,21 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aiven/aiven-go-client"
+	"github.com/ghodss/yaml"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
 )
@@ -23,59 +31,195 @@ type ServiceIntegrationReconciler struct {
 	Controller
 }
 
-type ServiceIntegrationHandler struct{}
+type ServiceIntegrationHandler struct {
+	k8s client.Client
+
+	// rec records the integration recreation
+	rec record.EventRecorder
+}
+
+const (
+	conditionTypeDataFlowing   = "DataFlowing"
+	eventIntegrationIsInactive = "IntegrationIsInactive"
+	eventServiceRecreated      = "ServiceRecreated"
+	eventOrphanedEndpoint      = "OrphanedEndpoint"
+
+	endpointTypeExternalSchemaRegistry = "external_schema_registry"
+	endpointTypeDatadog                = "datadog"
+
+	// managedEndpointPrefix the names of the endpoints managed along with integrations start with
+	managedEndpointPrefix = "k8s-"
+
+	// templateUserConfigKey template ConfigMap key with the base user config
+	templateUserConfigKey = "userConfig"
+
+	// serviceNameIndexKey indexes the service resources by name, so integrations find the services which are not created yet
+	serviceNameIndexKey = "metadata.name"
+)
+
+// integrationServiceTypes source and destination service types of integrations that require them
+var integrationServiceTypes = map[string][2]string{
+	// MirrorMaker integration connects a Kafka service to a Kafka MirrorMaker service under a cluster alias
+	"kafka_mirrormaker": {"kafka", "kafka_mirrormaker"},
+	// ClickHouse reads PostgreSQL tables
+	"clickhouse_postgresql": {"pg", "clickhouse"},
+}
+
+// isIntegrationNotReadyError Aiven errors returned for a while after the integrated services become running
+var isIntegrationNotReadyError = v1alpha1.ErrorSubstrChecker(
+	"is not running",
+	"not available",
+	"Service not found",
+	"Try again later",
+)
+
+// metricsDestinationTypes time series databases the metrics integration writes to
+var metricsDestinationTypes = []string{"influxdb", "m3db", "pg", "thanos"}
 
 // +kubebuilder:rbac:groups=aiven.io,resources=serviceintegrations,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=aiven.io,resources=serviceintegrations/status,verbs=get;update;patch
 
 func (r *ServiceIntegrationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	return r.reconcileInstance(ctx, req, ServiceIntegrationHandler{}, &v1alpha1.ServiceIntegration{})
+	si := &v1alpha1.ServiceIntegration{}
+	res, err := r.reconcileInstance(ctx, req, ServiceIntegrationHandler{k8s: r.Client, rec: r.Recorder}, si)
+	if err != nil || res.Requeue || si.Spec.InactiveThreshold == nil || isMarkedForDeletion(si) {
+		return res, err
+	}
+
+	// Running integration is checked periodically
+	c := meta.FindStatusCondition(si.Status.Conditions, conditionTypeDataFlowing)
+	if c != nil && c.Reason == "Inactive" {
+		r.Recorder.Event(si, corev1.EventTypeWarning, eventIntegrationIsInactive, c.Message)
+	}
+	return ctrl.Result{RequeueAfter: si.Spec.InactiveThreshold.Duration}, nil
 }
 
 func (r *ServiceIntegrationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	err := mgr.Add(&endpointGC{
+		k8s: r.Client,
+		log: r.Log.WithName("endpoint-gc"),
+		rec: r.Recorder,
+		newClient: func(ctx context.Context, si *v1alpha1.ServiceIntegration) (AivenClient, error) {
+			return r.objectAivenClient(ctx, si)
+		},
+		gc:       r.gcOrphanedEndpoints,
+		interval: endpointGCInterval,
+	})
+	if err != nil {
+		return err
+	}
+
+	for kind := range serviceKinds {
+		o, err := mgr.GetScheme().New(v1alpha1.GroupVersion.WithKind(kind))
+		if err != nil {
+			return err
+		}
+		err = mgr.GetFieldIndexer().IndexField(context.Background(), o.(client.Object), serviceNameIndexKey, serviceNameIndexFunc)
+		if err != nil {
+			return err
+		}
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.ServiceIntegration{}).
+		For(&v1alpha1.ServiceIntegration{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.integrationsForSecret)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.integrationsForConfigMap)).
 		Complete(r)
 }
 
-func (h ServiceIntegrationHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs []client.Object) error {
+// integrationsForConfigMap returns integrations which use the ConfigMap as the user config template
+func (r *ServiceIntegrationReconciler) integrationsForConfigMap(cm client.Object) []reconcile.Request {
+	list := &v1alpha1.ServiceIntegrationList{}
+	if err := r.List(context.Background(), list, client.InNamespace(cm.GetNamespace())); err != nil {
+		r.Log.Error(err, "unable to list service integrations")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, si := range list.Items {
+		if si.Spec.TemplateRef != nil && si.Spec.TemplateRef.Name == cm.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&si)})
+		}
+	}
+	return requests
+}
+
+// integrationsForSecret returns integrations which read the Datadog API key or the external schema registry credentials
+// from the secret, so the changes are applied
+func (r *ServiceIntegrationReconciler) integrationsForSecret(secret client.Object) []reconcile.Request {
+	list := &v1alpha1.ServiceIntegrationList{}
+	if err := r.List(context.Background(), list, client.InNamespace(secret.GetNamespace())); err != nil {
+		r.Log.Error(err, "unable to list service integrations")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, si := range list.Items {
+		datadog := si.Spec.DatadogEndpoint != nil && si.Spec.DatadogEndpoint.APIKeySecretRef.Name == secret.GetName()
+		registry := si.Spec.ExternalSchemaRegistry != nil && si.Spec.ExternalSchemaRegistry.BasicAuthSecretRef != nil &&
+			si.Spec.ExternalSchemaRegistry.BasicAuthSecretRef.Name == secret.GetName()
+		if datadog || registry {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&si)})
+		}
+	}
+	return requests
+}
+
+func (h ServiceIntegrationHandler) createOrUpdate(avn AivenClient, i client.Object, refs []client.Object) error {
 	si, err := h.convert(i)
 	if err != nil {
 		return err
 	}
 
+	if si.Spec.ExternalSchemaRegistry != nil {
+		err = h.createOrUpdateExternalSchemaRegistry(avn, si)
+		if err != nil {
+			return err
+		}
+	}
+
+	if si.Spec.DatadogEndpoint != nil {
+		err = h.createOrUpdateDatadogEndpoint(avn, si)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The template is read once, so the applied user config and the hash match
+	template, err := h.getTemplate(si)
+	if err != nil {
+		return err
+	}
+
 	var integration *aiven.ServiceIntegration
 
 	var reason string
 	if si.Status.ID == "" {
-		userConfig, err := h.getUserConfig(si, []string{"create", "update"})
+		userConfig, err := h.getTemplatedUserConfig(si, template, []string{"create", "update"})
 		if err != nil {
 			return err
 		}
 
-		integration, err = avn.ServiceIntegrations.Create(
-			si.Spec.Project,
-			aiven.CreateServiceIntegrationRequest{
-				DestinationEndpointID: toOptionalStringPointer(si.Spec.DestinationEndpointID),
-				DestinationService:    toOptionalStringPointer(si.Spec.DestinationServiceName),
-				IntegrationType:       si.Spec.IntegrationType,
-				SourceEndpointID:      toOptionalStringPointer(si.Spec.SourceEndpointID),
-				SourceService:         toOptionalStringPointer(si.Spec.SourceServiceName),
-				UserConfig:            userConfig,
-			},
-		)
+		integration, err = h.createIntegration(avn, si.Spec.Project, aiven.CreateServiceIntegrationRequest{
+			DestinationEndpointID: toOptionalStringPointer(h.destinationEndpointID(si)),
+			DestinationService:    toOptionalStringPointer(si.Spec.DestinationServiceName),
+			IntegrationType:       si.Spec.IntegrationType,
+			SourceEndpointID:      toOptionalStringPointer(si.Spec.SourceEndpointID),
+			SourceService:         toOptionalStringPointer(si.Spec.SourceServiceName),
+			UserConfig:            userConfig,
+		})
 		if err != nil {
 			return fmt.Errorf("cannot createOrUpdate service integration: %w", err)
 		}
 
 		reason = "Created"
 	} else {
-		userConfig, err := h.getUserConfig(si, []string{"update"})
+		userConfig, err := h.getTemplatedUserConfig(si, template, []string{"update"})
 		if err != nil {
 			return err
 		}
 
-		integration, err = avn.ServiceIntegrations.Update(
+		integration, err = avn.ServiceIntegrations().Update(
 			si.Spec.Project,
 			si.Status.ID,
 			aiven.UpdateServiceIntegrationRequest{
@@ -83,15 +227,38 @@ func (h ServiceIntegrationHandler) createOrUpdate(avn *aiven.Client, i client.Ob
 			},
 		)
 		reason = "Updated"
+		if isNotChangedError(err) {
+			// The status and the processed generation are set as for any other update
+			integration, err = avn.ServiceIntegrations().Get(si.Spec.Project, si.Status.ID)
+		}
 		if err != nil {
-			if strings.Contains(err.Error(), "user config not changed") {
-				return nil
-			}
 			return err
 		}
 	}
 
+	// The update group is stored, create only options can't be changed
+	appliedUserConfig, err := h.getTemplatedUserConfig(si, template, []string{"update"})
+	if err != nil {
+		return err
+	}
+	if err = setLastAppliedUserConfig(si, appliedUserConfig); err != nil {
+		return err
+	}
+	if si.Spec.TemplateRef != nil {
+		metav1.SetMetaDataAnnotation(&si.ObjectMeta, templateHashAnnotation, hashValue(template))
+	} else {
+		delete(si.Annotations, templateHashAnnotation)
+	}
+
 	si.Status.ID = integration.ServiceIntegrationID
+	si.Status.ClusterAlias = ""
+	if c := si.Spec.KafkaMirrormakerUserConfig; si.Spec.IntegrationType == "kafka_mirrormaker" && c != nil {
+		si.Status.ClusterAlias = fromAnyPointer(c.ClusterAlias)
+	}
+	si.Status.Databases = nil
+	if si.Spec.IntegrationType == "clickhouse_postgresql" {
+		si.Status.Databases = clickhousePostgreSQLDatabases(integration.UserConfig)
+	}
 
 	meta.SetStatusCondition(&si.Status.Conditions,
 		getInitializedCondition(reason,
@@ -107,26 +274,141 @@ func (h ServiceIntegrationHandler) createOrUpdate(avn *aiven.Client, i client.Ob
 	return nil
 }
 
-func (h ServiceIntegrationHandler) delete(avn *aiven.Client, i client.Object) (bool, error) {
+func (h ServiceIntegrationHandler) delete(avn AivenClient, i client.Object) (bool, error) {
 	si, err := h.convert(i)
 	if err != nil {
 		return false, err
 	}
 
-	err = avn.ServiceIntegrations.Delete(si.Spec.Project, si.Status.ID)
+	dependents, err := h.dependents(si)
+	if err != nil {
+		return false, err
+	}
+	if len(dependents) > 0 && !isForceDelete(si) {
+		return false, fmt.Errorf("%w: %s", errHasDependents, strings.Join(dependents, ", "))
+	}
+
+	err = avn.ServiceIntegrations().Delete(si.Spec.Project, si.Status.ID)
 	if err != nil && !aiven.IsNotFound(err) {
 		return false, fmt.Errorf("aiven client delete service ingtegration error: %w", err)
 	}
 
+	if si.Status.EndpointID != "" {
+		err = avn.ServiceIntegrationEndpoints().Delete(si.Spec.Project, si.Status.EndpointID)
+		if err != nil && !aiven.IsNotFound(err) {
+			return false, fmt.Errorf("aiven client delete service integration endpoint error: %w", err)
+		}
+	}
+
 	return true, nil
 }
 
-func (h ServiceIntegrationHandler) get(_ *aiven.Client, i client.Object) (*corev1.Secret, error) {
+// dependents returns resources that rely on the integration and must be deleted before it:
+// KafkaConnectors running on the kafka_connect integration destination service
+// and ServiceIntegrations which use the endpoint managed along with the integration
+func (h ServiceIntegrationHandler) dependents(si *v1alpha1.ServiceIntegration) ([]string, error) {
+	var names []string
+	if si.Spec.IntegrationType == "kafka_connect" {
+		list := &v1alpha1.KafkaConnectorList{}
+		err := h.k8s.List(context.Background(), list, client.InNamespace(si.Namespace))
+		if err != nil {
+			return nil, fmt.Errorf("unable to list kafka connectors: %w", err)
+		}
+		names = kafkaConnectorsOnService(list.Items, si.Spec.Project, si.Spec.DestinationServiceName)
+	}
+
+	if si.Status.EndpointID != "" {
+		list := &v1alpha1.ServiceIntegrationList{}
+		err := h.k8s.List(context.Background(), list)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list service integrations: %w", err)
+		}
+		names = append(names, integrationsUsingEndpoint(list.Items, si)...)
+	}
+	return names, nil
+}
+
+// integrationsUsingEndpoint returns names of the other integrations which use the endpoint managed by si
+func integrationsUsingEndpoint(integrations []v1alpha1.ServiceIntegration, si *v1alpha1.ServiceIntegration) []string {
+	var names []string
+	for _, other := range integrations {
+		if other.UID == si.UID || !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if other.Spec.SourceEndpointID == si.Status.EndpointID || other.Spec.DestinationEndpointID == si.Status.EndpointID {
+			names = append(names, fmt.Sprintf("ServiceIntegration/%s/%s", other.Namespace, other.Name))
+		}
+	}
+	return names
+}
+
+// kafkaConnectorsOnService returns names of the connectors which run on the given service
+func kafkaConnectorsOnService(connectors []v1alpha1.KafkaConnector, project, serviceName string) []string {
+	var names []string
+	for _, c := range connectors {
+		if c.Spec.Project == project && c.Spec.ServiceName == serviceName {
+			names = append(names, "KafkaConnector/"+c.Name)
+		}
+	}
+	return names
+}
+
+// createIntegration creates the integration, the errors of Aiven state lagging behind the running services
+// are wrapped with errIntegrationNotReady, so the reconcile is requeued instead of failed
+func (h ServiceIntegrationHandler) createIntegration(avn AivenClient, project string, req aiven.CreateServiceIntegrationRequest) (*aiven.ServiceIntegration, error) {
+	integration, err := avn.ServiceIntegrations().Create(project, req)
+	if err != nil && isIntegrationNotReadyError(err) {
+		return nil, fmt.Errorf("%w: %s", errIntegrationNotReady, err)
+	}
+	return integration, err
+}
+
+func (h ServiceIntegrationHandler) get(avn AivenClient, i client.Object) (*corev1.Secret, error) {
 	si, err := h.convert(i)
 	if err != nil {
 		return nil, err
 	}
 
+	// The generation doesn't change when the API key is changed in the secret
+	if si.Spec.DatadogEndpoint != nil {
+		apiKey, err := h.getDatadogAPIKey(si)
+		if err != nil {
+			return nil, err
+		}
+		if si.GetAnnotations()[datadogAPIKeyHashAnnotation] != hashValue(apiKey) {
+			if err = h.createOrUpdateDatadogEndpoint(avn, si); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Same for the external schema registry credentials
+	if registry := si.Spec.ExternalSchemaRegistry; registry != nil && registry.BasicAuthSecretRef != nil {
+		userConfig, err := h.getExternalSchemaRegistryUserConfig(si)
+		if err != nil {
+			return nil, err
+		}
+		if si.GetAnnotations()[schemaRegistryAuthHashAnnotation] != schemaRegistryAuthHash(userConfig) {
+			if err = h.createOrUpdateExternalSchemaRegistry(avn, si); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	integration, err := avn.ServiceIntegrations().Get(si.Spec.Project, si.Status.ID)
+	if aiven.IsNotFound(err) {
+		// Aiven removes the integrations of a deleted service,
+		// so the integration is gone when the service is recreated with the same name
+		return nil, h.recreate(avn, si)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if si.Spec.InactiveThreshold != nil {
+		setDataFlowingCondition(&si.Status.Conditions, integration.Active, si.Spec.InactiveThreshold.Duration)
+	}
+
 	meta.SetStatusCondition(&si.Status.Conditions,
 		getRunningCondition(metav1.ConditionTrue, "CheckRunning",
 			"Instance is running on Aiven side"))
@@ -136,7 +418,17 @@ func (h ServiceIntegrationHandler) get(_ *aiven.Client, i client.Object) (*corev
 	return nil, nil
 }
 
-func (h ServiceIntegrationHandler) checkPreconditions(avn *aiven.Client, i client.Object) (bool, error) {
+// recreate creates the integration which is gone on Aiven side, the status remains unknown until the next get
+func (h ServiceIntegrationHandler) recreate(avn AivenClient, si *v1alpha1.ServiceIntegration) error {
+	h.rec.Eventf(si, corev1.EventTypeWarning, eventServiceRecreated,
+		"integration %q is not found on Aiven side, the integrated service might be recreated, creating the integration again", si.Status.ID)
+
+	si.Status.ID = ""
+	delete(si.Annotations, instanceIsRunningAnnotation)
+	return h.createOrUpdate(avn, si, nil)
+}
+
+func (h ServiceIntegrationHandler) checkPreconditions(avn AivenClient, i client.Object) (bool, error) {
 	si, err := h.convert(i)
 	if err != nil {
 		return false, err
@@ -145,12 +437,62 @@ func (h ServiceIntegrationHandler) checkPreconditions(avn *aiven.Client, i clien
 	meta.SetStatusCondition(&si.Status.Conditions,
 		getInitializedCondition("Preconditions", "Checking preconditions"))
 
-	sourceCheck, err := checkServiceIsRunning(avn, si.Spec.Project, si.Spec.SourceServiceName)
+	// External Schema Registry is used by a Kafka service, the endpoint is managed by the operator
+	if registry := si.Spec.ExternalSchemaRegistry; registry != nil {
+		if registry.BasicAuthSecretRef != nil {
+			err = checkSecretKeys(h.k8s, si.Namespace, registry.BasicAuthSecretRef.Name, "username", "password")
+			if err != nil {
+				return false, err
+			}
+		}
+		return h.checkIntegratedService(avn, si.Spec.Project, si.Spec.SourceServiceName, "kafka")
+	}
+
+	// Datadog endpoint is managed by the operator, the API key must be in the secret
+	if endpoint := si.Spec.DatadogEndpoint; endpoint != nil {
+		err = checkSecretKeys(h.k8s, si.Namespace, endpoint.APIKeySecretRef.Name, endpoint.APIKeySecretRef.Key)
+		if err != nil {
+			return false, err
+		}
+		return h.checkIntegratedService(avn, si.Spec.Project, si.Spec.SourceServiceName)
+	}
+
+	// Metrics go to a time series database service, unless sent to an endpoint
+	if si.Spec.IntegrationType == "metrics" && si.Spec.DestinationServiceName != "" {
+		sourceCheck, err := h.checkIntegratedService(avn, si.Spec.Project, si.Spec.SourceServiceName)
+		if err != nil {
+			return false, err
+		}
+
+		destinationCheck, err := h.checkIntegratedService(avn, si.Spec.Project, si.Spec.DestinationServiceName, metricsDestinationTypes...)
+		if err != nil {
+			return false, err
+		}
+
+		return sourceCheck && destinationCheck, nil
+	}
+
+	// Some integrations connect services of the given types only
+	if serviceTypes, ok := integrationServiceTypes[si.Spec.IntegrationType]; ok {
+		sourceCheck, err := h.checkIntegratedService(avn, si.Spec.Project, si.Spec.SourceServiceName, serviceTypes[0])
+		if err != nil {
+			return false, err
+		}
+
+		destinationCheck, err := h.checkIntegratedService(avn, si.Spec.Project, si.Spec.DestinationServiceName, serviceTypes[1])
+		if err != nil {
+			return false, err
+		}
+
+		return sourceCheck && destinationCheck, nil
+	}
+
+	sourceCheck, err := h.checkIntegratedService(avn, si.Spec.Project, si.Spec.SourceServiceName)
 	if err != nil {
 		return false, err
 	}
 
-	destinationCheck, err := checkServiceIsRunning(avn, si.Spec.Project, si.Spec.DestinationServiceName)
+	destinationCheck, err := h.checkIntegratedService(avn, si.Spec.Project, si.Spec.DestinationServiceName)
 	if err != nil {
 		return false, err
 	}
@@ -158,6 +500,275 @@ func (h ServiceIntegrationHandler) checkPreconditions(avn *aiven.Client, i clien
 	return sourceCheck && destinationCheck, nil
 }
 
+// checkIntegratedService checks the integrated service is running and has one of the types, any type if none given.
+// A service which is neither on Aiven side nor managed by a resource is errServiceNotFound, most likely the name is wrong.
+// Aiven server errors are retried with the precondition backoff. No service name means an endpoint is integrated instead
+func (h ServiceIntegrationHandler) checkIntegratedService(avn AivenClient, project, serviceName string, serviceTypes ...string) (bool, error) {
+	if serviceName == "" {
+		return true, nil
+	}
+
+	s, err := avn.Services().Get(project, serviceName)
+	switch {
+	case aiven.IsNotFound(err):
+		managed, err := h.isServiceManaged(project, serviceName)
+		if err != nil || managed {
+			return false, err
+		}
+		return false, fmt.Errorf("%w: %q in project %q, check the service name", errServiceNotFound, serviceName, project)
+	case isAivenServerError(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	if len(serviceTypes) == 0 {
+		return s.State == "RUNNING", nil
+	}
+	for _, t := range serviceTypes {
+		if s.Type == t {
+			return s.State == "RUNNING", nil
+		}
+	}
+	return false, fmt.Errorf("service %q has type %q, expected %s", serviceName, s.Type, strings.Join(serviceTypes, " or "))
+}
+
+// serviceNameIndexFunc indexes the service resources by name
+func serviceNameIndexFunc(o client.Object) []string {
+	return []string{o.GetName()}
+}
+
+// isServiceManaged returns true if a service resource has the name, the service may be not created yet
+func (h ServiceIntegrationHandler) isServiceManaged(project, serviceName string) (bool, error) {
+	for kind, service := range serviceKinds {
+		list := service.newList()
+		if err := h.k8s.List(context.Background(), list, client.MatchingFields{serviceNameIndexKey: serviceName}); err != nil {
+			return false, fmt.Errorf("unable to list %s: %w", kind, err)
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return false, err
+		}
+		for _, item := range items {
+			o, err := service.fabric(nil, item.(client.Object))
+			if err != nil {
+				return false, err
+			}
+
+			// The project is not set yet if it is referenced
+			p := o.getServiceCommonSpec().Project
+			if p == "" || p == project {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// clickhousePostgreSQLDatabases returns PostgreSQL databases exposed in ClickHouse in "database.schema" format.
+// Aiven exposes the "defaultdb" database "public" schema when none is set
+func clickhousePostgreSQLDatabases(userConfig map[string]interface{}) []string {
+	databases, _ := userConfig["databases"].([]interface{})
+	if len(databases) == 0 {
+		return []string{"defaultdb.public"}
+	}
+
+	result := make([]string, 0, len(databases))
+	for _, v := range databases {
+		d, _ := v.(map[string]interface{})
+		database, ok := d["database"].(string)
+		if !ok {
+			database = "defaultdb"
+		}
+		schema, ok := d["schema"].(string)
+		if !ok {
+			schema = "public"
+		}
+		result = append(result, database+"."+schema)
+	}
+	return result
+}
+
+// setDataFlowingCondition sets DataFlowing condition.
+// Reason turns to "Inactive" when the integration is not active for longer than the threshold
+func setDataFlowingCondition(conditions *[]metav1.Condition, active bool, threshold time.Duration) {
+	if active {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:    conditionTypeDataFlowing,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Active",
+			Message: "Integration is active on Aiven side",
+		})
+		return
+	}
+
+	// Keeps the transition time if the condition is already false
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    conditionTypeDataFlowing,
+		Status:  metav1.ConditionFalse,
+		Reason:  "NotActive",
+		Message: "Integration is not active on Aiven side",
+	})
+
+	c := meta.FindStatusCondition(*conditions, conditionTypeDataFlowing)
+	if time.Since(c.LastTransitionTime.Time) > threshold {
+		c.Reason = "Inactive"
+		c.Message = fmt.Sprintf("Integration is not active on Aiven side for more than %s", threshold)
+	}
+}
+
+// createOrUpdateExternalSchemaRegistry creates or updates external_schema_registry endpoint
+// and sets its ID to the status. The credentials hash is stored to apply the secret changes
+func (h ServiceIntegrationHandler) createOrUpdateExternalSchemaRegistry(avn AivenClient, si *v1alpha1.ServiceIntegration) error {
+	userConfig, err := h.getExternalSchemaRegistryUserConfig(si)
+	if err != nil {
+		return err
+	}
+
+	err = h.createOrUpdateEndpoint(avn, si, endpointTypeExternalSchemaRegistry, userConfig)
+	if err != nil {
+		return err
+	}
+	if si.Spec.ExternalSchemaRegistry.BasicAuthSecretRef != nil {
+		metav1.SetMetaDataAnnotation(&si.ObjectMeta, schemaRegistryAuthHashAnnotation, schemaRegistryAuthHash(userConfig))
+	}
+	return nil
+}
+
+// schemaRegistryAuthHash returns the hash of the external schema registry basic auth credentials
+func schemaRegistryAuthHash(userConfig map[string]interface{}) string {
+	return hashValue(fmt.Sprintf("%v:%v", userConfig["basic_auth_username"], userConfig["basic_auth_password"]))
+}
+
+// managedEndpointName returns the name of the endpoint managed along with the integration.
+// Endpoints are project-wide, so the name has the namespace and the name hash,
+// otherwise the same-named integrations of different namespaces would share the endpoint
+func managedEndpointName(si *v1alpha1.ServiceIntegration) string {
+	return fmt.Sprintf("%s%s-%s", managedEndpointPrefix, si.Name, hashValue(si.Namespace + "/" + si.Name)[:8])
+}
+
+// createOrUpdateDatadogEndpoint creates or updates datadog endpoint with the API key from the secret
+// and stores the key hash to apply the key changes
+func (h ServiceIntegrationHandler) createOrUpdateDatadogEndpoint(avn AivenClient, si *v1alpha1.ServiceIntegration) error {
+	apiKey, err := h.getDatadogAPIKey(si)
+	if err != nil {
+		return err
+	}
+
+	userConfig := map[string]interface{}{"datadog_api_key": apiKey}
+	if si.Spec.DatadogEndpoint.Site != "" {
+		userConfig["site"] = si.Spec.DatadogEndpoint.Site
+	}
+
+	err = h.createOrUpdateEndpoint(avn, si, endpointTypeDatadog, userConfig)
+	if err != nil {
+		return err
+	}
+	metav1.SetMetaDataAnnotation(&si.ObjectMeta, datadogAPIKeyHashAnnotation, hashValue(apiKey))
+	return nil
+}
+
+// createOrUpdateEndpoint creates or updates the endpoint managed along with the integration
+// and sets its ID to the status
+func (h ServiceIntegrationHandler) createOrUpdateEndpoint(avn AivenClient, si *v1alpha1.ServiceIntegration, endpointType string, userConfig map[string]interface{}) error {
+	// The status is not saved if the integration creation fails, looks up the endpoint by name to not create it twice
+	if si.Status.EndpointID == "" {
+		endpoints, err := avn.ServiceIntegrationEndpoints().List(si.Spec.Project)
+		if err != nil {
+			return err
+		}
+		for _, e := range endpoints {
+			if e.EndpointType == endpointType && e.EndpointName == managedEndpointName(si) {
+				si.Status.EndpointID = e.EndpointID
+				break
+			}
+		}
+	}
+
+	if si.Status.EndpointID == "" {
+		endpoint, err := avn.ServiceIntegrationEndpoints().Create(
+			si.Spec.Project,
+			aiven.CreateServiceIntegrationEndpointRequest{
+				EndpointName: managedEndpointName(si),
+				EndpointType: endpointType,
+				UserConfig:   userConfig,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("cannot create service integration endpoint: %w", err)
+		}
+		si.Status.EndpointID = endpoint.EndpointID
+		return nil
+	}
+
+	_, err := avn.ServiceIntegrationEndpoints().Update(
+		si.Spec.Project,
+		si.Status.EndpointID,
+		aiven.UpdateServiceIntegrationEndpointRequest{
+			UserConfig: userConfig,
+		},
+	)
+	if err != nil && !isNotChangedError(err) {
+		return fmt.Errorf("cannot update service integration endpoint: %w", err)
+	}
+	return nil
+}
+
+// getDatadogAPIKey returns the Datadog API key from the secret, the key must not get to the status or events
+func (h ServiceIntegrationHandler) getDatadogAPIKey(si *v1alpha1.ServiceIntegration) (string, error) {
+	ref := si.Spec.DatadogEndpoint.APIKeySecretRef
+	secret := &corev1.Secret{}
+	err := h.k8s.Get(context.Background(), types.NamespacedName{Namespace: si.Namespace, Name: ref.Name}, secret)
+	if err != nil {
+		return "", fmt.Errorf("cannot get datadog api key secret: %w", err)
+	}
+
+	apiKey := string(secret.Data[ref.Key])
+	if apiKey == "" {
+		return "", fmt.Errorf("datadog api key secret %q has no %q key", ref.Name, ref.Key)
+	}
+	return apiKey, nil
+}
+
+// getExternalSchemaRegistryUserConfig returns endpoint user config with basic auth credentials from the secret
+func (h ServiceIntegrationHandler) getExternalSchemaRegistryUserConfig(si *v1alpha1.ServiceIntegration) (map[string]interface{}, error) {
+	registry := si.Spec.ExternalSchemaRegistry
+	userConfig := map[string]interface{}{
+		"url":            registry.URL,
+		"authentication": "none",
+	}
+
+	if registry.BasicAuthSecretRef == nil {
+		return userConfig, nil
+	}
+
+	secret := &corev1.Secret{}
+	err := h.k8s.Get(context.Background(), types.NamespacedName{Namespace: si.Namespace, Name: registry.BasicAuthSecretRef.Name}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get basic auth secret: %w", err)
+	}
+
+	for _, k := range []string{"username", "password"} {
+		if len(secret.Data[k]) == 0 {
+			return nil, fmt.Errorf("basic auth secret %q has no %q key", secret.Name, k)
+		}
+	}
+
+	userConfig["authentication"] = "basic"
+	userConfig["basic_auth_username"] = string(secret.Data["username"])
+	userConfig["basic_auth_password"] = string(secret.Data["password"])
+	return userConfig, nil
+}
+
+// destinationEndpointID returns the managed endpoint ID if the integration uses the external schema registry or Datadog
+func (h ServiceIntegrationHandler) destinationEndpointID(si *v1alpha1.ServiceIntegration) string {
+	if si.Spec.ExternalSchemaRegistry != nil || si.Spec.DatadogEndpoint != nil {
+		return si.Status.EndpointID
+	}
+	return si.Spec.DestinationEndpointID
+}
+
 func (h ServiceIntegrationHandler) convert(i client.Object) (*v1alpha1.ServiceIntegration, error) {
 	si, ok := i.(*v1alpha1.ServiceIntegration)
 	if !ok {
@@ -167,6 +778,67 @@ func (h ServiceIntegrationHandler) convert(i client.Object) (*v1alpha1.ServiceIn
 	return si, nil
 }
 
+// isOutdated returns true if the template ConfigMap has changed since the last update
+func (h ServiceIntegrationHandler) isOutdated(i client.Object) (bool, error) {
+	si, err := h.convert(i)
+	if err != nil || si.Spec.TemplateRef == nil {
+		return false, err
+	}
+
+	template, err := h.getTemplate(si)
+	if err != nil {
+		return false, err
+	}
+	return si.GetAnnotations()[templateHashAnnotation] != hashValue(template), nil
+}
+
+// getTemplate returns the base user config from the template ConfigMap, empty if there is no template
+func (h ServiceIntegrationHandler) getTemplate(si *v1alpha1.ServiceIntegration) (string, error) {
+	if si.Spec.TemplateRef == nil {
+		return "", nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	err := h.k8s.Get(context.Background(), types.NamespacedName{Namespace: si.Namespace, Name: si.Spec.TemplateRef.Name}, cm)
+	if err != nil {
+		return "", fmt.Errorf("cannot get template config map: %w", err)
+	}
+
+	data, ok := cm.Data[templateUserConfigKey]
+	if !ok {
+		return "", fmt.Errorf("template config map %q has no %q key", cm.Name, templateUserConfigKey)
+	}
+	return data, nil
+}
+
+// getTemplatedUserConfig merges the user config on top of the template user config
+func (h ServiceIntegrationHandler) getTemplatedUserConfig(si *v1alpha1.ServiceIntegration, template string, groups []string) (map[string]interface{}, error) {
+	userConfig, err := h.getUserConfig(si, groups)
+	if err != nil || si.Spec.TemplateRef == nil {
+		return userConfig, err
+	}
+
+	base := make(map[string]interface{})
+	if err = yaml.Unmarshal([]byte(template), &base); err != nil {
+		return nil, fmt.Errorf("cannot parse template user config: %w", err)
+	}
+	return mergeUserConfig(base, userConfig), nil
+}
+
+// mergeUserConfig merges overrides into base recursively, overrides win
+func mergeUserConfig(base, overrides map[string]interface{}) map[string]interface{} {
+	for k, v := range overrides {
+		bm, okBase := base[k].(map[string]interface{})
+		om, okOverride := v.(map[string]interface{})
+		if okBase && okOverride {
+			base[k] = mergeUserConfig(bm, om)
+			continue
+		}
+		base[k] = v
+	}
+	return base
+}
+
 func (h ServiceIntegrationHandler) getUserConfig(int *v1alpha1.ServiceIntegration, groups []string) (map[string]interface{}, error) {
 	switch int.Spec.IntegrationType {
 	case "datadog":
@@ -185,6 +857,10 @@ func (h ServiceIntegrationHandler) getUserConfig(int *v1alpha1.ServiceIntegratio
 		return UserConfigurationToAPIV2(int.Spec.KafkaMirrormakerUserConfig, groups)
 	case "logs":
 		return UserConfigurationToAPIV2(int.Spec.LogsUserConfig, groups)
+	case "prometheus":
+		return UserConfigurationToAPIV2(int.Spec.PrometheusUserConfig, groups)
+	case "rsyslog":
+		return UserConfigurationToAPIV2(int.Spec.RsyslogUserConfig, groups)
 	default:
 		return nil, nil
 	}
@@ -0,0 +1,731 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aiven/aiven-go-client"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+	kafkamirrormakeruserconfig "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/kafka_mirrormaker"
+	metricsintegration "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/metrics"
+	prometheususerconfig "github.com/aiven/aiven-operator/api/v1alpha1/userconfig/integration/prometheus"
+)
+
+func Test_clickhousePostgreSQLDatabases(t *testing.T) {
+	tests := []struct {
+		name       string
+		userConfig map[string]interface{}
+		want       []string
+	}{
+		{
+			name:       "defaults",
+			userConfig: map[string]interface{}{},
+			want:       []string{"defaultdb.public"},
+		},
+		{
+			name: "databases",
+			userConfig: map[string]interface{}{
+				"databases": []interface{}{
+					map[string]interface{}{"database": "foo", "schema": "bar"},
+					map[string]interface{}{"database": "baz"},
+				},
+			},
+			want: []string{"foo.bar", "baz.public"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clickhousePostgreSQLDatabases(tt.userConfig); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("clickhousePostgreSQLDatabases() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_mergeUserConfig(t *testing.T) {
+	base := map[string]interface{}{
+		"datadog_tags":   []interface{}{"team:foo"},
+		"kafka_consumer": map[string]interface{}{"check_instances": 1.0, "stats_timeout": 5.0},
+	}
+	overrides := map[string]interface{}{
+		"datadog_tags":   []interface{}{"team:bar"},
+		"kafka_consumer": map[string]interface{}{"stats_timeout": 10.0},
+	}
+	want := map[string]interface{}{
+		"datadog_tags":   []interface{}{"team:bar"},
+		"kafka_consumer": map[string]interface{}{"check_instances": 1.0, "stats_timeout": 10.0},
+	}
+	if got := mergeUserConfig(base, overrides); !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeUserConfig() = %v, want %v", got, want)
+	}
+}
+
+func Test_kafkaConnectorsOnService(t *testing.T) {
+	newConnector := func(name, project, serviceName string) v1alpha1.KafkaConnector {
+		c := v1alpha1.KafkaConnector{}
+		c.Name = name
+		c.Spec.Project = project
+		c.Spec.ServiceName = serviceName
+		return c
+	}
+	connectors := []v1alpha1.KafkaConnector{
+		newConnector("a", "foo", "connect"),
+		newConnector("b", "bar", "connect"),
+		newConnector("c", "foo", "kafka"),
+		newConnector("d", "foo", "connect"),
+	}
+
+	got := kafkaConnectorsOnService(connectors, "foo", "connect")
+	want := []string{"KafkaConnector/a", "KafkaConnector/d"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("kafkaConnectorsOnService() = %v, want %v", got, want)
+	}
+}
+
+func Test_createOrUpdateDatadogEndpoint(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "datadog", Namespace: "default"},
+		Data:       map[string][]byte{"apiKey": []byte("foo")},
+	}
+	si := &v1alpha1.ServiceIntegration{
+		ObjectMeta: metav1.ObjectMeta{Name: "datadog", Namespace: "default"},
+		Spec: v1alpha1.ServiceIntegrationSpec{
+			Project:         "project",
+			IntegrationType: "datadog",
+			DatadogEndpoint: &v1alpha1.DatadogEndpoint{
+				Site:            "datadoghq.eu",
+				APIKeySecretRef: v1alpha1.DatadogAPIKeySecretReference{Name: "datadog", Key: "apiKey"},
+			},
+		},
+	}
+
+	var created map[string]interface{}
+	raw := &aiven.Client{Client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		body := `{"service_integration_endpoints": []}`
+		if r.Method == http.MethodPost {
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				return nil, err
+			}
+			body = `{"service_integration_endpoint": {"endpoint_id": "id"}}`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}}
+	raw.Init()
+	avn := newGoClient(raw)
+
+	h := ServiceIntegrationHandler{k8s: fake.NewClientBuilder().WithObjects(secret).Build()}
+	if err := h.createOrUpdateDatadogEndpoint(avn, si); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{"datadog_api_key": "foo", "site": "datadoghq.eu"}
+	if !reflect.DeepEqual(created["user_config"], want) {
+		t.Errorf("endpoint user config = %v, want %v", created["user_config"], want)
+	}
+	if si.Status.EndpointID != "id" {
+		t.Errorf("endpoint ID = %q, want %q", si.Status.EndpointID, "id")
+	}
+	if si.Annotations[datadogAPIKeyHashAnnotation] != hashValue("foo") {
+		t.Errorf("datadog api key hash annotation is not set")
+	}
+}
+
+func Test_serviceIntegrationServiceRefs(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	kafka := &v1alpha1.Kafka{ObjectMeta: metav1.ObjectMeta{Name: "my-kafka", Namespace: "default"}}
+	connect := &v1alpha1.KafkaConnect{ObjectMeta: metav1.ObjectMeta{Name: "my-connect", Namespace: "default"}}
+	si := &v1alpha1.ServiceIntegration{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Spec: v1alpha1.ServiceIntegrationSpec{
+			IntegrationType:       "kafka_connect",
+			SourceServiceRef:      &v1alpha1.ServiceReference{Kind: "Kafka", ResourceReference: v1alpha1.ResourceReference{Name: "my-kafka"}},
+			DestinationServiceRef: &v1alpha1.ServiceReference{Kind: "KafkaConnect", ResourceReference: v1alpha1.ResourceReference{Name: "my-connect"}},
+		},
+	}
+
+	h := instanceReconcilerHelper{k8s: fake.NewClientBuilder().WithScheme(scheme).WithObjects(kafka, connect).Build()}
+	refs, err := h.getObjectRefs(context.Background(), si)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refs) != 2 || refs[0].GetName() != "my-kafka" || refs[1].GetName() != "my-connect" {
+		t.Errorf("getObjectRefs() = %v", refs)
+	}
+
+	si.SetServiceNames()
+	if si.Spec.SourceServiceName != "my-kafka" || si.Spec.DestinationServiceName != "my-connect" {
+		t.Errorf("SetServiceNames() = %q, %q", si.Spec.SourceServiceName, si.Spec.DestinationServiceName)
+	}
+}
+
+func Test_serviceIntegrationRecreated(t *testing.T) {
+	si := &v1alpha1.ServiceIntegration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "foo",
+			Namespace:   "default",
+			Generation:  1,
+			Annotations: map[string]string{instanceIsRunningAnnotation: "true"},
+		},
+		Spec: v1alpha1.ServiceIntegrationSpec{
+			Project:                "project",
+			IntegrationType:        "metrics",
+			SourceServiceName:      "pg",
+			DestinationServiceName: "thanos",
+			MetricsUserConfig:      &metricsintegration.MetricsUserConfig{},
+		},
+		Status: v1alpha1.ServiceIntegrationStatus{ID: "old"},
+	}
+
+	raw := &aiven.Client{Client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Method == http.MethodGet {
+			return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(`{"message": "not found"}`))}, nil
+		}
+		body := `{"service_integration": {"service_integration_id": "new"}}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}}
+	raw.Init()
+	avn := newGoClient(raw)
+
+	rec := record.NewFakeRecorder(10)
+	h := ServiceIntegrationHandler{k8s: fake.NewClientBuilder().Build(), rec: rec}
+	if _, err := h.get(avn, si); err != nil {
+		t.Fatal(err)
+	}
+
+	if si.Status.ID != "new" {
+		t.Errorf("integration ID = %q, want %q", si.Status.ID, "new")
+	}
+	if IsAlreadyRunning(si) {
+		t.Error("recreated integration is running, want unknown until the next get")
+	}
+	if e := <-rec.Events; !strings.Contains(e, eventServiceRecreated) {
+		t.Errorf("event = %q, want %s", e, eventServiceRecreated)
+	}
+}
+
+func Test_createIntegrationNotReady(t *testing.T) {
+	cases := []struct {
+		name        string
+		err         error
+		wantErr     bool
+		wantRequeue bool
+	}{
+		{"created", nil, false, false},
+		{"service is not running yet", aiven.Error{Message: "Service foo is not running", Status: 409}, true, true},
+		{"aiven asks to try again", aiven.Error{Message: "Try again later", Status: 503}, true, true},
+		{"other error is not requeued", aiven.Error{Message: "Invalid integration type", Status: 400}, true, false},
+	}
+
+	for _, c := range cases {
+		calls := 0
+		avn := &mockAivenClient{serviceIntegrations: &mockServiceIntegrations{
+			CreateFunc: func(project string, req aiven.CreateServiceIntegrationRequest) (*aiven.ServiceIntegration, error) {
+				calls++
+				if c.err != nil {
+					return nil, c.err
+				}
+				return &aiven.ServiceIntegration{ServiceIntegrationID: "id"}, nil
+			},
+		}}
+
+		_, err := ServiceIntegrationHandler{}.createIntegration(avn, "project", aiven.CreateServiceIntegrationRequest{})
+		if (err != nil) != c.wantErr || errors.Is(err, errIntegrationNotReady) != c.wantRequeue {
+			t.Errorf("%s: error = %v, want error %v, requeue %v", c.name, err, c.wantErr, c.wantRequeue)
+		}
+		if calls != 1 {
+			t.Errorf("%s: create calls = %d, want 1, the requeue retries", c.name, calls)
+		}
+	}
+}
+
+func Test_serviceIntegrationNotChangedUpdate(t *testing.T) {
+	si := &v1alpha1.ServiceIntegration{
+		ObjectMeta: metav1.ObjectMeta{Name: "si", Namespace: "default", Generation: 2},
+		Spec: v1alpha1.ServiceIntegrationSpec{
+			Project:           "project",
+			IntegrationType:   "metrics",
+			MetricsUserConfig: &metricsintegration.MetricsUserConfig{},
+		},
+		Status: v1alpha1.ServiceIntegrationStatus{ID: "id"},
+	}
+	avn := &mockAivenClient{serviceIntegrations: &mockServiceIntegrations{
+		UpdateFunc: func(project, integrationID string, req aiven.UpdateServiceIntegrationRequest) (*aiven.ServiceIntegration, error) {
+			return nil, aiven.Error{Message: "user config not changed", Status: 400}
+		},
+		GetFunc: func(project, integrationID string) (*aiven.ServiceIntegration, error) {
+			return &aiven.ServiceIntegration{ServiceIntegrationID: integrationID}, nil
+		},
+	}}
+
+	h := ServiceIntegrationHandler{k8s: fake.NewClientBuilder().Build()}
+	if err := h.createOrUpdate(avn, si, nil); err != nil {
+		t.Fatal(err)
+	}
+	if si.Annotations[processedGenerationAnnotation] != "2" {
+		t.Errorf("no-op update must process the generation, annotations %v", si.Annotations)
+	}
+	if meta.FindStatusCondition(si.Status.Conditions, conditionTypeInitialized) == nil {
+		t.Errorf("Initialized condition is not set: %v", si.Status.Conditions)
+	}
+}
+
+func Test_managedEndpointNamespaced(t *testing.T) {
+	si := &v1alpha1.ServiceIntegration{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "team-a"},
+		Spec: v1alpha1.ServiceIntegrationSpec{
+			Project:                "project",
+			IntegrationType:        "schema_registry_proxy",
+			ExternalSchemaRegistry: &v1alpha1.ExternalSchemaRegistryEndpoint{URL: "https://registry.example.com"},
+		},
+	}
+	other := si.DeepCopy()
+	other.Namespace = "team-b"
+	if managedEndpointName(si) == managedEndpointName(other) {
+		t.Fatalf("same-named integrations of different namespaces share the endpoint name %q", managedEndpointName(si))
+	}
+
+	var created string
+	avn := &mockAivenClient{serviceIntegrationEndpoints: &mockServiceIntegrationEndpoints{
+		ListFunc: func(project string) ([]*aiven.ServiceIntegrationEndpoint, error) {
+			return []*aiven.ServiceIntegrationEndpoint{
+				{EndpointID: "legacy", EndpointName: "registry", EndpointType: endpointTypeExternalSchemaRegistry},
+				{EndpointID: "team-b", EndpointName: managedEndpointName(other), EndpointType: endpointTypeExternalSchemaRegistry},
+			}, nil
+		},
+		CreateFunc: func(project string, req aiven.CreateServiceIntegrationEndpointRequest) (*aiven.ServiceIntegrationEndpoint, error) {
+			created = req.EndpointName
+			return &aiven.ServiceIntegrationEndpoint{EndpointID: "team-a"}, nil
+		},
+	}}
+	h := ServiceIntegrationHandler{k8s: fake.NewClientBuilder().Build()}
+	if err := h.createOrUpdateExternalSchemaRegistry(avn, si); err != nil {
+		t.Fatal(err)
+	}
+	if si.Status.EndpointID != "team-a" || created != managedEndpointName(si) {
+		t.Errorf("endpoint = %q named %q, want a new endpoint named %q", si.Status.EndpointID, created, managedEndpointName(si))
+	}
+}
+
+func Test_schemaRegistrySecretChanged(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-auth", Namespace: "default"},
+		Data:       map[string][]byte{"username": []byte("user"), "password": []byte("old")},
+	}
+	si := &v1alpha1.ServiceIntegration{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "default"},
+		Spec: v1alpha1.ServiceIntegrationSpec{
+			Project:         "project",
+			IntegrationType: "schema_registry_proxy",
+			ExternalSchemaRegistry: &v1alpha1.ExternalSchemaRegistryEndpoint{
+				URL:                "https://registry.example.com",
+				BasicAuthSecretRef: &v1alpha1.BasicAuthSecretReference{Name: "registry-auth"},
+			},
+		},
+		Status: v1alpha1.ServiceIntegrationStatus{ID: "integration", EndpointID: "endpoint"},
+	}
+	k8s := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret, si.DeepCopy()).Build()
+
+	r := &ServiceIntegrationReconciler{Controller: Controller{Client: k8s}}
+	requests := r.integrationsForSecret(secret)
+	if len(requests) != 1 || requests[0].Name != "registry" {
+		t.Errorf("integrationsForSecret() = %v, want the registry integration", requests)
+	}
+
+	updated := 0
+	var password interface{}
+	avn := &mockAivenClient{
+		serviceIntegrationEndpoints: &mockServiceIntegrationEndpoints{
+			ListFunc: func(project string) ([]*aiven.ServiceIntegrationEndpoint, error) { return nil, nil },
+			UpdateFunc: func(project, endpointID string, req aiven.UpdateServiceIntegrationEndpointRequest) (*aiven.ServiceIntegrationEndpoint, error) {
+				updated++
+				password = req.UserConfig["basic_auth_password"]
+				return &aiven.ServiceIntegrationEndpoint{EndpointID: endpointID}, nil
+			},
+		},
+		serviceIntegrations: &mockServiceIntegrations{GetFunc: func(project, id string) (*aiven.ServiceIntegration, error) {
+			return &aiven.ServiceIntegration{ServiceIntegrationID: id}, nil
+		}},
+	}
+	h := ServiceIntegrationHandler{k8s: k8s, rec: record.NewFakeRecorder(10)}
+	if err := h.createOrUpdateExternalSchemaRegistry(avn, si); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unchanged secret is not applied again
+	if _, err := h.get(avn, si); err != nil {
+		t.Fatal(err)
+	}
+	if updated != 1 {
+		t.Fatalf("endpoint updates = %d, want 1", updated)
+	}
+
+	secret.Data["password"] = []byte("new")
+	if err := k8s.Update(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.get(avn, si); err != nil {
+		t.Fatal(err)
+	}
+	if updated != 2 || password != "new" {
+		t.Errorf("endpoint updates = %d with password %v, want the new password applied", updated, password)
+	}
+}
+
+func Test_integrationsUsingEndpoint(t *testing.T) {
+	integrations := []v1alpha1.ServiceIntegration{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "datadog", UID: "1"},
+			Status:     v1alpha1.ServiceIntegrationStatus{EndpointID: "used"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "mirror", UID: "2"},
+			Spec:       v1alpha1.ServiceIntegrationSpec{DestinationEndpointID: "referenced"},
+			Status:     v1alpha1.ServiceIntegrationStatus{ID: "integration"},
+		},
+	}
+
+	if got := integrationsUsingEndpoint(integrations, &integrations[0]); len(got) != 0 {
+		t.Errorf("integrationsUsingEndpoint() = %v, want none", got)
+	}
+	owner := &v1alpha1.ServiceIntegration{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry", UID: "4"},
+		Status:     v1alpha1.ServiceIntegrationStatus{EndpointID: "referenced"},
+	}
+	if got := integrationsUsingEndpoint(integrations, owner); len(got) != 1 || got[0] != "ServiceIntegration//mirror" {
+		t.Errorf("integrationsUsingEndpoint() = %v, want the mirror integration", got)
+	}
+}
+
+func Test_checkIntegratedService(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	pending := &v1alpha1.PostgreSQL{ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default"}}
+	pending.Spec.Project = "foo"
+	other := &v1alpha1.Kafka{ObjectMeta: metav1.ObjectMeta{Name: "other-project", Namespace: "default"}}
+	other.Spec.Project = "bar"
+	h := ServiceIntegrationHandler{k8s: withServiceNameIndex(t, fake.NewClientBuilder().WithScheme(scheme), scheme).WithObjects(pending, other).Build()}
+
+	avn := &mockAivenClient{services: &mockServices{GetFunc: func(project, service string) (*aiven.Service, error) {
+		switch service {
+		case "running":
+			return &aiven.Service{Name: service, Type: "pg", State: "RUNNING"}, nil
+		case "flaky":
+			return nil, aiven.Error{Status: http.StatusServiceUnavailable}
+		}
+		return nil, aiven.Error{Status: http.StatusNotFound}
+	}}}
+
+	if ok, err := h.checkIntegratedService(avn, "foo", "running", "pg"); !ok || err != nil {
+		t.Errorf("running service: %t, %v", ok, err)
+	}
+	if _, err := h.checkIntegratedService(avn, "foo", "running", "kafka"); err == nil {
+		t.Error("service of another type must fail")
+	}
+
+	// Transient errors and services which are being created are waited for
+	for _, name := range []string{"flaky", "pending"} {
+		if ok, err := h.checkIntegratedService(avn, "foo", name); ok || err != nil {
+			t.Errorf("%s service: %t, %v, want not ready without error", name, ok, err)
+		}
+	}
+
+	for _, name := range []string{"typo", "other-project"} {
+		_, err := h.checkIntegratedService(avn, "foo", name)
+		if !errors.Is(err, errServiceNotFound) || !strings.Contains(err.Error(), `"`+name+`"`) {
+			t.Errorf("missing service error = %v, want errServiceNotFound naming the service", err)
+		}
+	}
+}
+
+// withServiceNameIndex registers the service name index of the service kinds, the fake client lists by indexed fields only
+func withServiceNameIndex(t *testing.T, b *fake.ClientBuilder, scheme *runtime.Scheme) *fake.ClientBuilder {
+	for kind := range serviceKinds {
+		o, err := scheme.New(v1alpha1.GroupVersion.WithKind(kind))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b = b.WithIndex(o, serviceNameIndexKey, serviceNameIndexFunc)
+	}
+	return b
+}
+
+func Test_getUserConfigIntegrationTypes(t *testing.T) {
+	limit := 120
+	si := &v1alpha1.ServiceIntegration{Spec: v1alpha1.ServiceIntegrationSpec{
+		IntegrationType: "prometheus",
+		PrometheusUserConfig: &prometheususerconfig.PrometheusUserConfig{SourceMysql: &prometheususerconfig.SourceMysql{
+			Telegraf: &prometheususerconfig.Telegraf{PerfEventsStatementsDigestTextLimit: &limit},
+		}},
+	}}
+
+	got, err := ServiceIntegrationHandler{}.getUserConfig(si, []string{"create", "update"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"source_mysql": map[string]interface{}{"telegraf": map[string]interface{}{"perf_events_statements_digest_text_limit": 120}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("prometheus user config = %v, want %v", got, want)
+	}
+
+	// Rsyslog is configured in the endpoint, the integration has no options
+	si.Spec = v1alpha1.ServiceIntegrationSpec{IntegrationType: "rsyslog"}
+	got, err = ServiceIntegrationHandler{}.getUserConfig(si, []string{"create", "update"})
+	if err != nil || len(got) != 0 {
+		t.Errorf("rsyslog user config = %v, %v, want empty", got, err)
+	}
+}
+
+// countingClient counts Get calls of the wrapped client
+type countingClient struct {
+	client.Client
+	gets int
+}
+
+func (c *countingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	c.gets++
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func Test_templateConfigMapChanged(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "template", Namespace: "default"},
+		Data:       map[string]string{templateUserConfigKey: "retention_days: 7\nsource_mysql:\n  telegraf:\n    gather_innodb_metrics: true"},
+	}
+	si := &v1alpha1.ServiceIntegration{
+		ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "default"},
+		Spec: v1alpha1.ServiceIntegrationSpec{
+			Project:           "project",
+			IntegrationType:   "metrics",
+			TemplateRef:       &v1alpha1.ServiceIntegrationTemplateReference{Name: "template"},
+			MetricsUserConfig: &metricsintegration.MetricsUserConfig{Database: anyPointer("metrics")},
+		},
+		Status: v1alpha1.ServiceIntegrationStatus{ID: "integration"},
+	}
+	other := &v1alpha1.ServiceIntegration{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+		Spec:       v1alpha1.ServiceIntegrationSpec{Project: "project", IntegrationType: "metrics"},
+	}
+	k8s := &countingClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm, si.DeepCopy(), other).Build()}
+
+	r := &ServiceIntegrationReconciler{Controller: Controller{Client: k8s}}
+	requests := r.integrationsForConfigMap(cm)
+	if len(requests) != 1 || requests[0].Name != "metrics" {
+		t.Errorf("integrationsForConfigMap() = %v, want the templated integration", requests)
+	}
+
+	var userConfig map[string]interface{}
+	avn := &mockAivenClient{serviceIntegrations: &mockServiceIntegrations{
+		UpdateFunc: func(project, integrationID string, req aiven.UpdateServiceIntegrationRequest) (*aiven.ServiceIntegration, error) {
+			userConfig = req.UserConfig
+			return &aiven.ServiceIntegration{ServiceIntegrationID: integrationID}, nil
+		},
+	}}
+	h := ServiceIntegrationHandler{k8s: k8s}
+
+	outdated, err := h.isOutdated(si)
+	if err != nil || !outdated {
+		t.Fatalf("isOutdated() = %t, %v, want the template never applied", outdated, err)
+	}
+
+	k8s.gets = 0
+	if err = h.createOrUpdate(avn, si, nil); err != nil {
+		t.Fatal(err)
+	}
+	if k8s.gets != 1 {
+		t.Errorf("template config map is fetched %d times, want once", k8s.gets)
+	}
+	if userConfig["retention_days"] != float64(7) || userConfig["database"] != "metrics" {
+		t.Errorf("user config = %v, want the spec merged on top of the template", userConfig)
+	}
+	if outdated, err = h.isOutdated(si); err != nil || outdated {
+		t.Errorf("isOutdated() = %t, %v, want the applied template up to date", outdated, err)
+	}
+
+	cm.Data[templateUserConfigKey] = "retention_days: 14"
+	if err = k8s.Update(context.Background(), cm); err != nil {
+		t.Fatal(err)
+	}
+	if outdated, err = h.isOutdated(si); err != nil || !outdated {
+		t.Errorf("isOutdated() = %t, %v, want the changed template outdated", outdated, err)
+	}
+}
+
+func Test_kafkaMirrormakerIntegration(t *testing.T) {
+	avn := &mockAivenClient{
+		services: &mockServices{GetFunc: func(project, service string) (*aiven.Service, error) {
+			types := map[string]string{"kafka": "kafka", "mirrormaker": "kafka_mirrormaker", "pg": "pg"}
+			return &aiven.Service{Name: service, Type: types[service], State: "RUNNING"}, nil
+		}},
+		serviceIntegrations: &mockServiceIntegrations{
+			UpdateFunc: func(project, integrationID string, req aiven.UpdateServiceIntegrationRequest) (*aiven.ServiceIntegration, error) {
+				if req.UserConfig["cluster_alias"] != "source" {
+					t.Errorf("user config = %v, want the cluster alias", req.UserConfig)
+				}
+				return &aiven.ServiceIntegration{ServiceIntegrationID: integrationID}, nil
+			},
+		},
+	}
+	newIntegration := func(source, destination string) *v1alpha1.ServiceIntegration {
+		return &v1alpha1.ServiceIntegration{
+			ObjectMeta: metav1.ObjectMeta{Name: "mirror", Namespace: "default"},
+			Spec: v1alpha1.ServiceIntegrationSpec{
+				Project:                    "project",
+				IntegrationType:            "kafka_mirrormaker",
+				SourceServiceName:          source,
+				DestinationServiceName:     destination,
+				KafkaMirrormakerUserConfig: &kafkamirrormakeruserconfig.KafkaMirrormakerUserConfig{ClusterAlias: anyPointer("source")},
+			},
+			Status: v1alpha1.ServiceIntegrationStatus{ID: "integration"},
+		}
+	}
+	h := ServiceIntegrationHandler{}
+
+	si := newIntegration("kafka", "mirrormaker")
+	if ok, err := h.checkPreconditions(avn, si); !ok || err != nil {
+		t.Errorf("checkPreconditions() = %t, %v, want kafka to mirrormaker accepted", ok, err)
+	}
+	if err := h.createOrUpdate(avn, si, nil); err != nil {
+		t.Fatal(err)
+	}
+	if si.Status.ClusterAlias != "source" {
+		t.Errorf("status cluster alias = %q, want source", si.Status.ClusterAlias)
+	}
+
+	for _, c := range [][2]string{{"pg", "mirrormaker"}, {"kafka", "pg"}} {
+		_, err := h.checkPreconditions(avn, newIntegration(c[0], c[1]))
+		if err == nil || !strings.Contains(err.Error(), `"pg" has type "pg"`) {
+			t.Errorf("%s to %s: checkPreconditions() error = %v, want the service type mismatch", c[0], c[1], err)
+		}
+	}
+}
+
+func Test_setDataFlowingCondition(t *testing.T) {
+	threshold := time.Hour
+	cases := []struct {
+		name       string
+		conditions []metav1.Condition
+		active     bool
+		want       metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:       "active",
+			active:     true,
+			want:       metav1.ConditionTrue,
+			wantReason: "Active",
+		},
+		{
+			name:       "just stopped",
+			want:       metav1.ConditionFalse,
+			wantReason: "NotActive",
+		},
+		{
+			name: "stopped within the threshold",
+			conditions: []metav1.Condition{{
+				Type: conditionTypeDataFlowing, Status: metav1.ConditionFalse, Reason: "NotActive",
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-threshold / 2)),
+			}},
+			want:       metav1.ConditionFalse,
+			wantReason: "NotActive",
+		},
+		{
+			name: "stopped longer than the threshold",
+			conditions: []metav1.Condition{{
+				Type: conditionTypeDataFlowing, Status: metav1.ConditionFalse, Reason: "NotActive",
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-2 * threshold)),
+			}},
+			want:       metav1.ConditionFalse,
+			wantReason: "Inactive",
+		},
+		{
+			name: "inactive integration is active again",
+			conditions: []metav1.Condition{{
+				Type: conditionTypeDataFlowing, Status: metav1.ConditionFalse, Reason: "Inactive",
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-2 * threshold)),
+			}},
+			active:     true,
+			want:       metav1.ConditionTrue,
+			wantReason: "Active",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			conditions := c.conditions
+			setDataFlowingCondition(&conditions, c.active, threshold)
+			got := meta.FindStatusCondition(conditions, conditionTypeDataFlowing)
+			if got == nil || got.Status != c.want || got.Reason != c.wantReason {
+				t.Errorf("condition = %+v, want %s %s", got, c.want, c.wantReason)
+			}
+		})
+	}
+}
+
+func Test_getSetsDataFlowing(t *testing.T) {
+	avn := &mockAivenClient{serviceIntegrations: &mockServiceIntegrations{
+		GetFunc: func(project, integrationID string) (*aiven.ServiceIntegration, error) {
+			return &aiven.ServiceIntegration{ServiceIntegrationID: integrationID, Active: false}, nil
+		},
+	}}
+	si := &v1alpha1.ServiceIntegration{
+		ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: "default"},
+		Spec:       v1alpha1.ServiceIntegrationSpec{Project: "project", IntegrationType: "metrics"},
+		Status:     v1alpha1.ServiceIntegrationStatus{ID: "integration"},
+	}
+	h := ServiceIntegrationHandler{}
+
+	// The check is off without the threshold
+	if _, err := h.get(avn, si); err != nil {
+		t.Fatal(err)
+	}
+	if c := meta.FindStatusCondition(si.Status.Conditions, conditionTypeDataFlowing); c != nil {
+		t.Errorf("condition = %+v, want none without inactiveThreshold", c)
+	}
+
+	si.Spec.InactiveThreshold = &metav1.Duration{Duration: time.Hour}
+	if _, err := h.get(avn, si); err != nil {
+		t.Fatal(err)
+	}
+	if !meta.IsStatusConditionFalse(si.Status.Conditions, conditionTypeDataFlowing) {
+		t.Errorf("conditions = %+v, want DataFlowing False", si.Status.Conditions)
+	}
+}
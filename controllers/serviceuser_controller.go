@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aiven/aiven-operator/api/v1alpha1"
@@ -33,17 +34,18 @@ func (r *ServiceUserReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 func (r *ServiceUserReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.ServiceUser{}).
+		For(&v1alpha1.ServiceUser{}, builder.WithPredicates(ignoreOperatorChangesPredicate)).
+		Owns(&corev1.Secret{}).
 		Complete(r)
 }
 
-func (h ServiceUserHandler) createOrUpdate(avn *aiven.Client, i client.Object, refs []client.Object) error {
+func (h ServiceUserHandler) createOrUpdate(avn AivenClient, i client.Object, refs []client.Object) error {
 	user, err := h.convert(i)
 	if err != nil {
 		return err
 	}
 
-	u, err := avn.ServiceUsers.Create(user.Spec.Project, user.Spec.ServiceName,
+	u, err := avn.ServiceUsers().Create(user.Spec.Project, user.Spec.ServiceName,
 		aiven.CreateServiceUserRequest{
 			Username: user.Name,
 			AccessControl: &aiven.AccessControl{
@@ -75,13 +77,13 @@ func (h ServiceUserHandler) createOrUpdate(avn *aiven.Client, i client.Object, r
 	return nil
 }
 
-func (h ServiceUserHandler) delete(avn *aiven.Client, i client.Object) (bool, error) {
+func (h ServiceUserHandler) delete(avn AivenClient, i client.Object) (bool, error) {
 	user, err := h.convert(i)
 	if err != nil {
 		return false, err
 	}
 
-	err = avn.ServiceUsers.Delete(user.Spec.Project, user.Spec.ServiceName, user.Name)
+	err = avn.ServiceUsers().Delete(user.Spec.Project, user.Spec.ServiceName, user.Name)
 	if !aiven.IsNotFound(err) {
 		return false, err
 	}
@@ -89,25 +91,25 @@ func (h ServiceUserHandler) delete(avn *aiven.Client, i client.Object) (bool, er
 	return true, nil
 }
 
-func (h ServiceUserHandler) get(avn *aiven.Client, i client.Object) (*corev1.Secret, error) {
+func (h ServiceUserHandler) get(avn AivenClient, i client.Object) (*corev1.Secret, error) {
 	user, err := h.convert(i)
 	if err != nil {
 		return nil, err
 	}
 
-	u, err := avn.ServiceUsers.Get(user.Spec.Project, user.Spec.ServiceName, user.Name)
+	u, err := avn.ServiceUsers().Get(user.Spec.Project, user.Spec.ServiceName, user.Name)
 	if err != nil {
 		return nil, err
 	}
 
-	s, err := avn.Services.Get(user.Spec.Project, user.Spec.ServiceName)
+	s, err := avn.Services().Get(user.Spec.Project, user.Spec.ServiceName)
 	if err != nil {
 		return nil, err
 	}
 
 	params := s.URIParams
 
-	caCert, err := avn.CA.Get(user.Spec.Project)
+	caCert, err := avn.CA().Get(user.Spec.Project)
 	if err != nil {
 		return nil, fmt.Errorf("aiven client error %w", err)
 	}
@@ -142,7 +144,7 @@ func (h ServiceUserHandler) getSecretName(user *v1alpha1.ServiceUser) string {
 	return user.Name
 }
 
-func (h ServiceUserHandler) checkPreconditions(avn *aiven.Client, i client.Object) (bool, error) {
+func (h ServiceUserHandler) checkPreconditions(avn AivenClient, i client.Object) (bool, error) {
 	user, err := h.convert(i)
 	if err != nil {
 		return false, err
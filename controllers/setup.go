@@ -3,147 +3,292 @@ package controllers
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/aiven/aiven-go-client"
+	"k8s.io/apimachinery/pkg/labels"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
-func SetupControllers(mgr ctrl.Manager, defaultToken string) error {
+// AivenClientFactory creates Aiven API clients authorized with the token
+type AivenClientFactory func(token string) (AivenClient, error)
+
+// newTokenClient creates aiven-go-client, zero timeout means no timeout
+func newTokenClient(token string, timeout time.Duration) (AivenClient, error) {
+	c, err := aiven.NewTokenClient(token, operatorUserAgent)
+	if err != nil {
+		return nil, err
+	}
+	c.Client.Timeout = timeout
+	return newGoClient(c), nil
+}
+
+// Options are operator wide settings shared by all controllers, webhooks and endpoints
+type Options struct {
+	// DefaultToken is used when a resource has no authSecretRef
+	DefaultToken string
+
+	// AuditLog writes a structured log line for every lifecycle event
+	AuditLog bool
+
+	// QuietEvents records warnings and state change events only (created, updated, deleted),
+	// drops routine events emitted on every reconcile
+	QuietEvents bool
+
+	// PreconditionRequeueTimeout is the initial requeue interval when preconditions are not met,
+	// it is doubled on every failed check
+	PreconditionRequeueTimeout time.Duration
+
+	// RequeueBaseInterval is the initial requeue interval while an instance is not running yet,
+	// it is doubled on every requeue
+	RequeueBaseInterval time.Duration
+
+	// RequeueMaxInterval caps the requeue intervals of not running instances and not met preconditions
+	RequeueMaxInterval time.Duration
+
+	// ProtectedNamespaces selects namespaces which resources are never deleted on Aiven side,
+	// for instance, "aiven.io/protected=true". Disabled if nil
+	ProtectedNamespaces labels.Selector
+
+	// GetCacheTTL is how long the state of a running resource is cached between reconciles.
+	// Disabled if zero
+	GetCacheTTL time.Duration
+
+	// ClientTimeout Aiven API HTTP client timeout, no timeout if zero
+	ClientTimeout time.Duration
+
+	// NewAivenClient creates Aiven API clients instead of the default token client with ClientTimeout,
+	// for instance, mocks to run tests without Aiven account
+	NewAivenClient AivenClientFactory
+
+	// MaxStatusSize larger statuses are trimmed, bytes. Disabled if zero
+	MaxStatusSize int
+
+	// FinalizerTimeout the finalizer is removed anyway, when the deletion on Aiven side doesn't succeed in time.
+	// Disabled if zero
+	FinalizerTimeout time.Duration
+
+	// AdoptSecrets overwrites existing secrets which are not owned by the resource.
+	// Otherwise, such secrets are left untouched and a warning is emitted
+	AdoptSecrets bool
+
+	// AuthTokens the authSecretRef file and environment variable sources, disabled if not set
+	AuthTokens AuthTokenSources
+
+	// GCOrphanedEndpoints deletes the integration endpoints of the types managed along with ServiceIntegrations
+	// (datadog, external_schema_registry), which no ServiceIntegration references. Otherwise, they are reported only
+	GCOrphanedEndpoints bool
+
+	// ValidateUserConfig validates service user configs against the schemas pulled from Aiven before sending
+	ValidateUserConfig bool
+
+	// APIErrorRateThreshold Aiven API error rate (5xx and 429) within APIErrorRateWindow, from 0 to 1,
+	// which slows down all reconciles until the rate recovers. Disabled if zero
+	APIErrorRateThreshold float64
+
+	// APIErrorRateWindow the Aiven API error rate is calculated over. Disabled if zero
+	APIErrorRateWindow time.Duration
+
+	// APIThrottleFactor requeue intervals are multiplied by while the error rate is over the threshold
+	APIThrottleFactor int
+
+	// apiThrottle shared by all controllers, set on setup
+	apiThrottle *apiThrottle
+
+	// userConfigSchemas schemas cache shared by all controllers, set on setup if ValidateUserConfig is true
+	userConfigSchemas *userConfigSchemas
+
+	// scrapeConfigs is true if the Prometheus Operator ScrapeConfig CRD is installed, detected on setup
+	scrapeConfigs bool
+}
+
+// aivenClient returns the client authorized with the token
+func (opts Options) aivenClient(token string) (AivenClient, error) {
+	if opts.NewAivenClient != nil {
+		return opts.NewAivenClient(token)
+	}
+	return newTokenClient(token, opts.ClientTimeout)
+}
+
+func SetupControllers(mgr ctrl.Manager, opts Options) error {
+	opts.scrapeConfigs = hasScrapeConfigCRD(mgr.GetRESTMapper())
+	if !opts.scrapeConfigs {
+		ctrl.Log.Info("prometheus operator ScrapeConfig CRD is not installed, scrape configs are disabled")
+	}
+	if opts.ValidateUserConfig {
+		opts.userConfigSchemas = newUserConfigSchemas()
+	}
+	opts.apiThrottle = newAPIThrottle(opts.APIErrorRateThreshold, opts.APIErrorRateWindow, opts.APIThrottleFactor)
+
 	if err := (&SecretFinalizerGCController{
 		Client: mgr.GetClient(),
 		Log:    ctrl.Log.WithName("controllers").WithName("SecretFinalizerGCController"),
-	}).SetupWithManager(mgr, defaultToken != ""); err != nil {
+	}).SetupWithManager(mgr, opts.DefaultToken != ""); err != nil {
 		return fmt.Errorf("controller SecretFinalizerGCController: %w", err)
 	}
 
 	if err := (&ProjectReconciler{
-		Controller: newController(mgr, "Project", defaultToken),
+		Controller: newController(mgr, "Project", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller Project: %w", err)
 	}
 
 	if err := (&PostgreSQLReconciler{
-		Controller: newController(mgr, "PostgreSQL", defaultToken),
+		Controller: newController(mgr, "PostgreSQL", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller PostgreSQL: %w", err)
 	}
 
 	if err := (&ConnectionPoolReconciler{
-		Controller: newController(mgr, "ConnectionPool", defaultToken),
+		Controller: newController(mgr, "ConnectionPool", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller ConnectionPool: %w", err)
 	}
 
 	if err := (&DatabaseReconciler{
-		Controller: newController(mgr, "Database", defaultToken),
+		Controller: newController(mgr, "Database", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller Database: %w", err)
 	}
 
 	if err := (&KafkaReconciler{
-		Controller: newController(mgr, "Kafka", defaultToken),
+		Controller: newController(mgr, "Kafka", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller Kafka: %w", err)
 	}
 
 	if err := (&ProjectVPCReconciler{
-		Controller: newController(mgr, "ProjectVPC", defaultToken),
+		Controller: newController(mgr, "ProjectVPC", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller ProjectVPC: %w", err)
 	}
 
 	if err := (&KafkaTopicReconciler{
-		Controller: newController(mgr, "KafkaTopic", defaultToken),
+		Controller: newController(mgr, "KafkaTopic", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller KafkaTopic: %w", err)
 	}
 
 	if err := (&KafkaACLReconciler{
-		Controller: newController(mgr, "KafkaACL", defaultToken),
+		Controller: newController(mgr, "KafkaACL", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller KafkaACL: %w", err)
 	}
 
 	if err := (&KafkaConnectReconciler{
-		Controller: newController(mgr, "KafkaConnect", defaultToken),
+		Controller: newController(mgr, "KafkaConnect", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller KafkaConnect: %w", err)
 	}
 
 	if err := (&ServiceUserReconciler{
-		Controller: newController(mgr, "ServiceUser", defaultToken),
+		Controller: newController(mgr, "ServiceUser", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller ServiceUser: %w", err)
 	}
 
 	if err := (&KafkaSchemaReconciler{
-		Controller: newController(mgr, "KafkaSchema", defaultToken),
+		Controller: newController(mgr, "KafkaSchema", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller KafkaSchema: %w", err)
 	}
 
 	if err := (&ServiceIntegrationReconciler{
-		Controller: newController(mgr, "ServiceIntegration", defaultToken),
+		Controller: newController(mgr, "ServiceIntegration", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller ServiceIntegration: %w", err)
 	}
 	if err := (&KafkaConnectorReconciler{
-		Controller: newController(mgr, "KafkaConnector", defaultToken),
+		Controller: newController(mgr, "KafkaConnector", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller KafkaConnector: %w", err)
 	}
 
 	if err := (&RedisReconciler{
-		Controller: newController(mgr, "Redis", defaultToken),
+		Controller: newController(mgr, "Redis", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller Redis: %w", err)
 	}
 
 	if err := (&OpenSearchReconciler{
-		Controller: newController(mgr, "OpenSearch", defaultToken),
+		Controller: newController(mgr, "OpenSearch", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller OpenSearch: %w", err)
 	}
 
 	if err := (&ClickhouseReconciler{
-		Controller: newController(mgr, "Clickhouse", defaultToken),
+		Controller: newController(mgr, "Clickhouse", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller Clickhouse: %w", err)
 	}
 
 	if err := (&ClickhouseUserReconciler{
-		Controller: newController(mgr, "ClickhouseUser", defaultToken),
+		Controller: newController(mgr, "ClickhouseUser", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller ClickhouseUser: %w", err)
 	}
 
 	if err := (&MySQLReconciler{
-		Controller: newController(mgr, "MySQL", defaultToken),
+		Controller: newController(mgr, "MySQL", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller MySQL: %w", err)
 	}
 
 	if err := (&CassandraReconciler{
-		Controller: newController(mgr, "Cassandra", defaultToken),
+		Controller: newController(mgr, "Cassandra", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller Cassandra: %w", err)
 	}
 
 	if err := (&GrafanaReconciler{
-		Controller: newController(mgr, "Grafana", defaultToken),
+		Controller: newController(mgr, "Grafana", opts),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("controller Grafana: %w", err)
 	}
 
+	if err := (&AccountAuthenticationReconciler{
+		Controller: newController(mgr, "AccountAuthentication", opts),
+	}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("controller AccountAuthentication: %w", err)
+	}
+
+	if err := setupInventoryEndpoint(mgr); err != nil {
+		return fmt.Errorf("inventory endpoint: %w", err)
+	}
+
 	//+kubebuilder:scaffold:builder
 	return nil
 }
 
-func newController(mgr ctrl.Manager, name, defaultToken string) Controller {
+func newController(mgr ctrl.Manager, name string, opts Options) Controller {
+	recorder := mgr.GetEventRecorderFor(strings.ToLower(name) + "-reconciler")
+	if opts.QuietEvents {
+		recorder = newQuietRecorder(recorder)
+	}
+	if opts.AuditLog {
+		recorder = newAuditRecorder(recorder, mgr.GetScheme(), ctrl.Log.WithName("audit").WithName(name))
+	}
+
 	return Controller{
-		Client:       mgr.GetClient(),
-		Log:          ctrl.Log.WithName("controllers").WithName(name),
-		Scheme:       mgr.GetScheme(),
-		Recorder:     mgr.GetEventRecorderFor(strings.ToLower(name) + "-reconciler"),
-		DefaultToken: defaultToken,
+		Client:        mgr.GetClient(),
+		Log:           ctrl.Log.WithName("controllers").WithName(name),
+		Scheme:        mgr.GetScheme(),
+		Recorder:      recorder,
+		DefaultToken:  opts.DefaultToken,
+		ClientTimeout: opts.ClientTimeout,
+
+		newAivenClient:      opts.NewAivenClient,
+		preconditions:       newPreconditionBackoff(opts.PreconditionRequeueTimeout, opts.RequeueMaxInterval),
+		requeue:             newRequeueBackoff(opts.RequeueBaseInterval, opts.RequeueMaxInterval),
+		protectedNamespaces: opts.ProtectedNamespaces,
+		cache:               newGetCache(opts.GetCacheTTL),
+		scrapeConfigs:       opts.scrapeConfigs,
+		maxStatusSize:       opts.MaxStatusSize,
+		finalizerTimeout:    opts.FinalizerTimeout,
+		adoptSecrets:        opts.AdoptSecrets,
+		gcOrphanedEndpoints: opts.GCOrphanedEndpoints,
+		authTokens:          opts.AuthTokens,
+		userConfigSchemas:   opts.userConfigSchemas,
+		apiThrottle:         opts.apiThrottle,
 	}
 }
@@ -0,0 +1,132 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxConditionMessageLength the API server rejects longer condition messages
+	maxConditionMessageLength = 32768
+
+	// maxStatusItems number of the last items kept in status lists, except conditions
+	maxStatusItems = 100
+
+	// minDroppedStringLength shorter strings are never dropped, like ids and states
+	minDroppedStringLength = 1024
+)
+
+// trimStatus caps the object status, so status updates never fail with "object too large".
+// Condition messages are always truncated to the API limit. If the status is larger than maxSize bytes,
+// lists are cut to maxStatusItems last items, then the largest lists, objects and long strings are dropped
+// until the status fits. Conditions and scalars, like status.id, are never dropped. Returns the trimmed status fields
+func trimStatus(o client.Object, maxSize int) ([]string, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+	if err != nil {
+		return nil, err
+	}
+
+	status, ok := u["status"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	var trimmed []string
+	if conditions, ok := status["conditions"].([]interface{}); ok {
+		for _, c := range conditions {
+			c, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if m, ok := c["message"].(string); ok && len(m) > maxConditionMessageLength {
+				c["message"] = m[:maxConditionMessageLength]
+				trimmed = appendOnce(trimmed, "conditions")
+			}
+		}
+	}
+
+	if maxSize > 0 && statusSize(status) > maxSize {
+		for k, v := range status {
+			if l, ok := v.([]interface{}); ok && k != "conditions" && len(l) > maxStatusItems {
+				status[k] = l[len(l)-maxStatusItems:]
+				trimmed = append(trimmed, k)
+			}
+		}
+
+		for statusSize(status) > maxSize {
+			k := largestStatusField(status)
+			if k == "" {
+				break
+			}
+			delete(status, k)
+			trimmed = appendOnce(trimmed, k)
+		}
+	}
+
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+
+	// Converter doesn't reset the fields which are missing in the map
+	v := reflect.ValueOf(o).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(u, o); err != nil {
+		return nil, err
+	}
+	sort.Strings(trimmed)
+	return trimmed, nil
+}
+
+// statusSize returns status size in JSON
+func statusSize(status map[string]interface{}) int {
+	b, err := json.Marshal(status)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
+
+// largestStatusField returns the largest droppable status field name, empty if there is none
+func largestStatusField(status map[string]interface{}) string {
+	var largest string
+	var size int
+	for k, v := range status {
+		if !isDroppableStatusField(k, v) {
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err == nil && len(b) > size {
+			largest, size = k, len(b)
+		}
+	}
+	return largest
+}
+
+// isDroppableStatusField returns true for lists, objects and long strings, except conditions
+func isDroppableStatusField(k string, v interface{}) bool {
+	if k == "conditions" {
+		return false
+	}
+	switch v := v.(type) {
+	case []interface{}, map[string]interface{}:
+		return true
+	case string:
+		return len(v) >= minDroppedStringLength
+	}
+	return false
+}
+
+func appendOnce(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
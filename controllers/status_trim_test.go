@@ -0,0 +1,78 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aiven/aiven-operator/api/v1alpha1"
+)
+
+func Test_trimStatus(t *testing.T) {
+	databases := make([]string, maxStatusItems+10)
+	for i := range databases {
+		databases[i] = fmt.Sprintf("db%d.public", i)
+	}
+	si := &v1alpha1.ServiceIntegration{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Status: v1alpha1.ServiceIntegrationStatus{
+			Conditions: []metav1.Condition{{Type: conditionTypeRunning, Message: strings.Repeat("a", maxConditionMessageLength+1)}},
+			ID:         "id",
+			Databases:  databases,
+		},
+	}
+
+	// Condition messages are truncated regardless of the size limit
+	trimmed, err := trimStatus(si, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(trimmed, []string{"conditions"}) || len(si.Status.Conditions[0].Message) != maxConditionMessageLength {
+		t.Errorf("trimStatus() = %v, message length %d", trimmed, len(si.Status.Conditions[0].Message))
+	}
+	if len(si.Status.Databases) != len(databases) {
+		t.Errorf("lists must not be trimmed without the size limit")
+	}
+
+	// The last list items are kept
+	expected := si.Status.DeepCopy()
+	expected.Databases = databases[10:]
+	b, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trimmed, err = trimStatus(si, len(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(trimmed, []string{"databases"}) || !reflect.DeepEqual(si.Status.Databases, databases[10:]) {
+		t.Errorf("trimStatus() = %v, databases %d", trimmed, len(si.Status.Databases))
+	}
+	if si.Name != "foo" || si.Status.ID != "id" {
+		t.Errorf("trimStatus() changed not trimmed fields")
+	}
+
+	// The largest fields are dropped, conditions are kept
+	trimmed, err = trimStatus(si, len(b)-100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(trimmed, []string{"databases"}) || si.Status.Databases != nil || len(si.Status.Conditions) != 1 {
+		t.Errorf("trimStatus() = %v, status %v", trimmed, si.Status.Databases)
+	}
+
+	// Scalars are kept, even if the status doesn't fit
+	trimmed, err = trimStatus(si, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trimmed != nil || si.Status.ID != "id" || len(si.Status.Conditions) != 1 {
+		t.Errorf("trimStatus() = %v, id %q", trimmed, si.Status.ID)
+	}
+}
@@ -109,7 +109,7 @@ var _ = BeforeSuite(func() {
 		}
 	}
 
-	err = SetupControllers(k8sManager, aivenToken)
+	err = SetupControllers(k8sManager, Options{DefaultToken: aivenToken})
 	Expect(err).ToNot(HaveOccurred())
 
 	go func() {
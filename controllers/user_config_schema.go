@@ -0,0 +1,107 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aiven/aiven-go-client"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// userConfigSchemaTTL schemas change with Aiven releases only
+const userConfigSchemaTTL = time.Hour
+
+var errUnknownUserConfigOptions = errors.New("user config has options unknown to aiven")
+
+// userConfigHandler returns the user config sent to Aiven and the service type it is validated against
+type userConfigHandler interface {
+	getAPIUserConfig(client.Object) (project, serviceType string, userConfig map[string]interface{}, err error)
+}
+
+// userConfigSchemas caches the service types user config schemas pulled from Aiven
+type userConfigSchemas struct {
+	mu      sync.Mutex
+	entries map[string]userConfigSchemaEntry
+}
+
+type userConfigSchemaEntry struct {
+	schema  aiven.UserConfigSchema
+	expires time.Time
+}
+
+func newUserConfigSchemas() *userConfigSchemas {
+	return &userConfigSchemas{entries: make(map[string]userConfigSchemaEntry)}
+}
+
+// get returns the service type schema, pulls the schemas of all service types when the cache is expired
+func (c *userConfigSchemas) get(a AivenClient, project, serviceType string, now time.Time) (aiven.UserConfigSchema, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[serviceType]; ok && now.Before(e.expires) {
+		return e.schema, nil
+	}
+
+	types, err := a.Projects().ServiceTypes(project)
+	if err != nil {
+		return aiven.UserConfigSchema{}, fmt.Errorf("failed to get user config schemas: %w", err)
+	}
+	for name, t := range types {
+		c.entries[name] = userConfigSchemaEntry{schema: t.UserConfigSchema, expires: now.Add(userConfigSchemaTTL)}
+	}
+
+	e, ok := c.entries[serviceType]
+	if !ok {
+		return aiven.UserConfigSchema{}, fmt.Errorf("no user config schema for service type %q", serviceType)
+	}
+	return e.schema, nil
+}
+
+// validateUserConfig returns an error listing the user config options missing in the schema
+func validateUserConfig(schema aiven.UserConfigSchema, userConfig map[string]interface{}) error {
+	unknown := unknownUserConfigOptions("", schema, userConfig)
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("%w: %s", errUnknownUserConfigOptions, strings.Join(unknown, ", "))
+}
+
+// unknownUserConfigOptions walks nested objects and lists of objects.
+// Objects without properties in the schema are free-form, hence not checked
+func unknownUserConfigOptions(prefix string, schema aiven.UserConfigSchema, userConfig map[string]interface{}) []string {
+	if len(schema.Properties) == 0 {
+		return nil
+	}
+
+	var unknown []string
+	for k, v := range userConfig {
+		path := prefix + k
+		prop, ok := schema.Properties[k]
+		if !ok {
+			unknown = append(unknown, path)
+			continue
+		}
+
+		switch v := v.(type) {
+		case map[string]interface{}:
+			unknown = append(unknown, unknownUserConfigOptions(path+".", prop, v)...)
+		case []interface{}:
+			if prop.Items == nil {
+				continue
+			}
+			for i, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					unknown = append(unknown, unknownUserConfigOptions(fmt.Sprintf("%s[%d].", path, i), *prop.Items, m)...)
+				}
+			}
+		}
+	}
+	return unknown
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2022 Aiven, Helsinki, Finland. https://aiven.io/
+
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aiven/aiven-go-client"
+)
+
+func Test_validateUserConfig(t *testing.T) {
+	var calls int
+	raw := &aiven.Client{Client: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		body := `{"service_types": {"pg": {"user_config_schema": {"properties": {
+			"pg_version": {"type": "string"},
+			"pg": {"type": "object", "properties": {"jit": {"type": "boolean"}}},
+			"ip_filter": {"type": "array", "items": {"type": "object", "properties": {"network": {"type": "string"}}}},
+			"pglookout": {"type": "object"}
+		}}}}}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}}
+	raw.Init()
+	avn := newGoClient(raw)
+
+	schemas := newUserConfigSchemas()
+	now := time.Now()
+	schema, err := schemas.get(avn, "project", "pg", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = schemas.get(avn, "project", "pg", now.Add(time.Minute)); err != nil || calls != 1 {
+		t.Errorf("get() calls = %d, %v, want cached", calls, err)
+	}
+	if _, err = schemas.get(avn, "project", "kafka", now); err == nil {
+		t.Error("get() error = nil, want unknown service type")
+	}
+
+	valid := map[string]interface{}{
+		"pg_version": "15",
+		"pg":         map[string]interface{}{"jit": true},
+		"ip_filter":  []interface{}{map[string]interface{}{"network": "10.0.0.0/8"}},
+		"pglookout":  map[string]interface{}{"max_failover_replication_time_lag": 60.0},
+	}
+	if err = validateUserConfig(schema, valid); err != nil {
+		t.Errorf("validateUserConfig() error = %v, want nil", err)
+	}
+
+	invalid := map[string]interface{}{
+		"pg_versoin": "15",
+		"pg":         map[string]interface{}{"jti": true},
+		"ip_filter":  []interface{}{map[string]interface{}{"netwrok": "10.0.0.0/8"}},
+	}
+	err = validateUserConfig(schema, invalid)
+	if !errors.Is(err, errUnknownUserConfigOptions) || !strings.HasSuffix(err.Error(), "ip_filter[0].netwrok, pg.jti, pg_versoin") {
+		t.Errorf("validateUserConfig() error = %v, want unknown options", err)
+	}
+}
@@ -39,8 +39,9 @@ func updateClusterRole(operatorPath, crdCharts string) error {
 	return writeFile(dstPath, []byte(content))
 }
 
+// clusterRoleTmpl is a Role in namespace scoped mode
 var clusterRoleTmpl = `apiVersion: rbac.authorization.k8s.io/v1
-kind: ClusterRole
+kind: {{ if .Values.namespaceScoped }}Role{{ else }}ClusterRole{{ end }}
 metadata:
   name: {{ include "aiven-operator.fullname" . }}-role
   namespace: {{ include "aiven-operator.namespace" . }}
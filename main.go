@@ -5,12 +5,16 @@ package main
 import (
 	"flag"
 	"os"
+	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
@@ -20,7 +24,7 @@ import (
 )
 
 //go:generate go run ./generators/userconfigs/... --services mysql,cassandra,grafana,pg,kafka,redis,clickhouse,opensearch,kafka_connect
-//go:generate go run ./generators/userconfigs/... --integrations clickhouse_kafka,clickhouse_postgresql,datadog,kafka_connect,kafka_logs,kafka_mirrormaker,logs,metrics,external_aws_cloudwatch_metrics
+//go:generate go run ./generators/userconfigs/... --integrations clickhouse_kafka,clickhouse_postgresql,datadog,kafka_connect,kafka_logs,kafka_mirrormaker,logs,metrics,external_aws_cloudwatch_metrics,prometheus,rsyslog
 
 var (
 	scheme   = runtime.NewScheme()
@@ -40,12 +44,62 @@ func main() {
 	var enableLeaderElection bool
 	var probeAddr string
 	var development bool
+	var auditLog bool
+	var quietEvents bool
+	var preconditionRequeueTimeout time.Duration
+	var requeueBaseInterval time.Duration
+	var requeueMaxInterval time.Duration
+	var protectedNamespaces string
+	var getCacheTTL time.Duration
+	var costEstimation bool
+	var clientTimeout time.Duration
+	var watchNamespaces string
+	var namespaceScoped bool
+	var maxStatusSize int
+	var dryRunDiff bool
+	var finalizerTimeout time.Duration
+	var adoptSecrets bool
+	var validateUserConfig bool
+	var gcOrphanedEndpoints bool
+	var authTokenDir string
+	var authTokenFileNamespaces string
+	var authTokenEnvNamespaces string
+	var namingConvention string
+	var namingConventionConfigMap string
+	var apiErrorRateThreshold float64
+	var apiErrorRateWindow time.Duration
+	var apiThrottleFactor int
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.BoolVar(&development, "development", true, "Configures the logger to use a development config (stacktraces on warnings, no sampling)")
+	flag.BoolVar(&auditLog, "audit-log", false, "Writes a structured log line for every resource lifecycle event (created, deleted, preconditions failures)")
+	flag.BoolVar(&quietEvents, "quiet-events", false, "Records warnings and state change events only (created, updated, deleted), drops routine events emitted on every reconcile")
+	flag.DurationVar(&preconditionRequeueTimeout, "precondition-requeue-timeout", 30*time.Second, "Initial requeue interval when resource preconditions are not met, doubled on every failed check up to --requeue-max-interval")
+	flag.DurationVar(&requeueBaseInterval, "requeue-base-interval", 10*time.Second, "Initial requeue interval while a resource is not running yet on Aiven side, doubled on every requeue up to --requeue-max-interval")
+	flag.DurationVar(&requeueMaxInterval, "requeue-max-interval", 10*time.Minute, "Maximum requeue interval of resources which are not running yet or which preconditions are not met. Intervals get up to 20% of random jitter")
+	flag.StringVar(&protectedNamespaces, "protected-namespaces", "", "Label selector of namespaces which resources are never deleted on Aiven side, for instance, aiven.io/protected=true. Deleting such resources is blocked until they are annotated with controllers.aiven.io/orphan=true, then they are kept on Aiven side")
+	flag.DurationVar(&getCacheTTL, "get-cache-ttl", 0, "How long the state of a running resource is cached between reconciles, disabled by default. Set the controllers.aiven.io/reconcile-now annotation to a new value to bypass it")
+	flag.BoolVar(&costEstimation, "cost-estimation", false, "Estimates services monthly cost on create and rejects services over the namespace controllers.aiven.io/monthly-budget-usd annotation, or which cost can't be estimated in such namespaces. Requires webhooks")
+	flag.DurationVar(&clientTimeout, "aiven-client-timeout", time.Minute, "Aiven API HTTP client timeout, timed out calls are requeued. 0 disables the timeout")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", os.Getenv("WATCH_NAMESPACE"), "Comma-separated namespaces the operator watches, all namespaces if empty. Defaults to WATCH_NAMESPACE env")
+	flag.BoolVar(&namespaceScoped, "namespace-scoped", false, "Runs the operator in a single namespace set with --watch-namespaces, which requires namespaced Role only. Disables the features that read namespaces: --protected-namespaces and cost estimation budgets")
+	flag.IntVar(&maxStatusSize, "max-status-size", 0, "Maximum resource status size in bytes, larger statuses are trimmed: long lists are cut and the largest lists, objects and long strings are dropped. Disabled if 0")
+	flag.BoolVar(&dryRunDiff, "dry-run-diff", false, "Serves the /dry-run-diff endpoint on the metrics address, which returns the diff between a POSTed service manifest and the live service on Aiven. Requires a bearer token of a user allowed to update the resource. Nothing is applied")
+	flag.DurationVar(&finalizerTimeout, "finalizer-timeout", 0, "Removes the finalizer when the deletion on Aiven side doesn't succeed in time, so the resource is deleted and marked as orphaned. The Aiven side resource may be left. 0 disables the timeout")
+	flag.BoolVar(&adoptSecrets, "adopt-secrets", false, "Overwrites existing secrets which are not owned by the resource. By default, such secrets are left untouched and a SecretNotOwned warning is emitted")
+	flag.BoolVar(&validateUserConfig, "validate-user-config", false, "Validates service user configs against the schemas pulled from Aiven before sending, unknown options fail the reconcile")
+	flag.BoolVar(&gcOrphanedEndpoints, "gc-orphaned-endpoints", false, "Deletes datadog and external_schema_registry integration endpoints created by the operator, which no ServiceIntegration references anymore. The endpoints are checked hourly and, by default, reported with an OrphanedEndpoint warning. Endpoints created outside the operator are never touched")
+	flag.StringVar(&authTokenDir, "auth-token-dir", "", "Directory the authSecretRef.file tokens are read from, for instance, mounted by Vault agent or a CSI driver. Token files are disabled if empty")
+	flag.StringVar(&authTokenFileNamespaces, "auth-token-file-namespaces", "", "Comma-separated namespaces which resources can read authSecretRef.file tokens, * allows all. Token files are disabled if empty")
+	flag.StringVar(&authTokenEnvNamespaces, "auth-token-env-namespaces", "", "Comma-separated namespaces which resources can read authSecretRef.env tokens, * allows all. Environment variable tokens are disabled if empty")
+	flag.StringVar(&namingConvention, "naming-convention", "", "Regex the names of projects, services and Kafka topics must match on create, for instance, (dev|prod)-[a-z0-9-]+. Requires webhooks")
+	flag.StringVar(&namingConventionConfigMap, "naming-convention-configmap", "", "ConfigMap (namespace/name) with naming convention regexes per kind (for instance, KafkaTopic) or under the default key, overrides --naming-convention. Read on every create, requires webhooks")
+	flag.Float64Var(&apiErrorRateThreshold, "api-error-rate-threshold", 0, "Aiven API error rate (5xx and 429 responses) within --api-error-rate-window, from 0 to 1, which slows down all reconciles until the rate recovers, for instance, 0.5. Disabled if 0")
+	flag.DurationVar(&apiErrorRateWindow, "api-error-rate-window", 5*time.Minute, "The window the Aiven API error rate is calculated over, at least 10 calls are required")
+	flag.IntVar(&apiThrottleFactor, "api-throttle-factor", 4, "Requeue intervals are multiplied by the factor while the Aiven API error rate is over --api-error-rate-threshold")
 	opts := zap.Options{
 		Development: development,
 	}
@@ -54,8 +108,32 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	// Multi namespace cache watches cluster scoped objects too, like namespaces.
+	// Single namespace cache is used in the namespace scoped mode, so no cluster wide watches
+	var newCache cache.NewCacheFunc
+	var cacheNamespace string
+	namespaces := splitNamespaces(watchNamespaces)
+	switch {
+	case namespaceScoped:
+		if len(namespaces) != 1 {
+			setupLog.Error(nil, "namespace scoped mode requires exactly one namespace in --watch-namespaces")
+			os.Exit(1)
+		}
+		if protectedNamespaces != "" {
+			setupLog.Error(nil, "--protected-namespaces is not supported in namespace scoped mode")
+			os.Exit(1)
+		}
+		setupLog.Info("running in namespace scoped mode", "namespace", namespaces[0])
+		cacheNamespace = namespaces[0]
+	case len(namespaces) > 0:
+		setupLog.Info("watching namespaces", "namespaces", namespaces)
+		newCache = cache.MultiNamespacedCacheBuilder(namespaces)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
+		NewCache:               newCache,
+		Namespace:              cacheNamespace,
 		MetricsBindAddress:     metricsAddr,
 		Port:                   port,
 		HealthProbeBindAddress: probeAddr,
@@ -78,12 +156,49 @@ func main() {
 		os.Exit(1)
 	}
 
-	defaultToken := os.Getenv("DEFAULT_AIVEN_TOKEN")
-	err = controllers.SetupControllers(mgr, defaultToken)
+	protectedNamespacesSelector, err := labels.Parse(protectedNamespaces)
+	if err != nil {
+		setupLog.Error(err, "invalid protected namespaces selector")
+		os.Exit(1)
+	}
+
+	authTokens := controllers.AuthTokenSources{
+		Dir:            authTokenDir,
+		FileNamespaces: splitNamespaces(authTokenFileNamespaces),
+		EnvNamespaces:  splitNamespaces(authTokenEnvNamespaces),
+	}
+	operatorOpts := controllers.Options{
+		DefaultToken:               os.Getenv("DEFAULT_AIVEN_TOKEN"),
+		AuditLog:                   auditLog,
+		QuietEvents:                quietEvents,
+		PreconditionRequeueTimeout: preconditionRequeueTimeout,
+		RequeueBaseInterval:        requeueBaseInterval,
+		RequeueMaxInterval:         requeueMaxInterval,
+		ProtectedNamespaces:        protectedNamespacesSelector,
+		GetCacheTTL:                getCacheTTL,
+		ClientTimeout:              clientTimeout,
+		MaxStatusSize:              maxStatusSize,
+		FinalizerTimeout:           finalizerTimeout,
+		AdoptSecrets:               adoptSecrets,
+		ValidateUserConfig:         validateUserConfig,
+		GCOrphanedEndpoints:        gcOrphanedEndpoints,
+		AuthTokens:                 authTokens,
+		APIErrorRateThreshold:      apiErrorRateThreshold,
+		APIErrorRateWindow:         apiErrorRateWindow,
+		APIThrottleFactor:          apiThrottleFactor,
+	}
+	err = controllers.SetupControllers(mgr, operatorOpts)
 	if err != nil {
 		setupLog.Error(err, "controllers setup error")
 	}
 
+	if dryRunDiff {
+		if err = controllers.SetupDryRunDiffEndpoint(mgr, operatorOpts); err != nil {
+			setupLog.Error(err, "unable to set up dry-run diff endpoint")
+			os.Exit(1)
+		}
+	}
+
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		if err = (&v1alpha1.Project{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Project")
@@ -174,6 +289,14 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Grafana")
 			os.Exit(1)
 		}
+		if err = controllers.SetupCostEstimationWebhook(mgr, operatorOpts, costEstimation, !namespaceScoped); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "CostEstimation")
+			os.Exit(1)
+		}
+		if err = controllers.SetupNamingConventionWebhook(mgr, namingConvention, namingConventionConfigMap); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NamingConvention")
+			os.Exit(1)
+		}
 	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -191,3 +314,14 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// splitNamespaces returns non-empty namespaces of the comma-separated list
+func splitNamespaces(s string) []string {
+	var namespaces []string
+	for _, ns := range strings.Split(s, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
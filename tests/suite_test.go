@@ -114,7 +114,7 @@ func setupSuite() error {
 		return err
 	}
 
-	err = controllers.SetupControllers(mgr, aivenToken)
+	err = controllers.SetupControllers(mgr, controllers.Options{DefaultToken: aivenToken})
 	if err != nil {
 		return err
 	}